@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// volatileLine matches Markdown report lines that change on every run (the
+// generation time and report ID), which --check-only ignores
+var volatileLine = regexp.MustCompile(`^(- \*\*Generated At\*\*: |\*Generated by prtool \(report )`)
+
+// volatileJSONFields are the metadata fields of a JSON report that change on
+// every run. since_time and until_time are resolved from the current time when
+// --since or --until is relative.
+var volatileJSONFields = []string{"generated_at", "report_id", "since_time", "until_time"}
+
+// checkReport compares a freshly generated report with the existing file at
+// path, ignoring volatile lines and line endings. It returns a diff of the
+// remaining lines, empty when the report is up to date.
+func checkReport(path, generated string) (string, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return diffLines(reportLines(string(existing)), reportLines(generated)), nil
+}
+
+// reportLines splits a report into lines, dropping volatile lines. JSON
+// reports are re-indented without their volatile metadata fields first, so
+// compact and indented documents compare line by line.
+func reportLines(content string) []string {
+	if normalized, ok := normalizeJSONReport(content); ok {
+		return strings.Split(normalized, "\n")
+	}
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if !volatileLine.MatchString(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// normalizeJSONReport decodes a JSON report, drops its volatile metadata
+// fields and re-encodes it indented. It reports false when content is not a
+// JSON object.
+func normalizeJSONReport(content string) (string, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(content), "{") {
+		return "", false
+	}
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.UseNumber()
+	var report map[string]interface{}
+	if err := decoder.Decode(&report); err != nil {
+		return "", false
+	}
+	if metadata, ok := report["metadata"].(map[string]interface{}); ok {
+		for _, field := range volatileJSONFields {
+			delete(metadata, field)
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// diffLines returns the lines removed from old ("- ") and added in new ("+ "),
+// in order, based on their longest common subsequence. It returns "" when the
+// lines are equal.
+func diffLines(old, new []string) string {
+	// lcs[i][j] is the length of the longest common subsequence of old[i:]
+	// and new[j:]
+	lcs := make([][]int, len(old)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(new)+1)
+	}
+	for i := len(old) - 1; i >= 0; i-- {
+		for j := len(new) - 1; j >= 0; j-- {
+			if old[i] == new[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var b strings.Builder
+	i, j := 0, 0
+	for i < len(old) || j < len(new) {
+		switch {
+		case i < len(old) && j < len(new) && old[i] == new[j]:
+			i++
+			j++
+		case i < len(old) && (j == len(new) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&b, "- %s\n", old[i])
+			i++
+		default:
+			fmt.Fprintf(&b, "+ %s\n", new[j])
+			j++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/willis7/prtool/internal/model"
+	"github.com/willis7/prtool/internal/render"
+)
+
+func TestCheckReport(t *testing.T) {
+	existing := "# Pull Request Summary\n\n" +
+		"- **Generated At**: 2024-01-15 10:30:00 UTC\n" +
+		"- **Total PRs**: 2\n\n" +
+		"- Add login (#1)\n" +
+		"- Fix logout (#2)\n\n" +
+		"*Generated by prtool (report 1111)*\n"
+	path := filepath.Join(t.TempDir(), "report.md")
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("identical apart from timestamp and report ID", func(t *testing.T) {
+		generated := "# Pull Request Summary\n\n" +
+			"- **Generated At**: 2024-01-22 09:00:00 UTC\n" +
+			"- **Total PRs**: 2\n\n" +
+			"- Add login (#1)\n" +
+			"- Fix logout (#2)\n\n" +
+			"*Generated by prtool (report 2222)*\n"
+		diff, err := checkReport(path, generated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff != "" {
+			t.Errorf("Expected no diff, got:\n%s", diff)
+		}
+	})
+
+	t.Run("differing content", func(t *testing.T) {
+		generated := "# Pull Request Summary\n\n" +
+			"- **Generated At**: 2024-01-22 09:00:00 UTC\n" +
+			"- **Total PRs**: 2\n\n" +
+			"- Add login (#1)\n" +
+			"- Add signup (#3)\n\n" +
+			"*Generated by prtool (report 2222)*\n"
+		diff, err := checkReport(path, generated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "- - Fix logout (#2)\n+ - Add signup (#3)\n"
+		if diff != want {
+			t.Errorf("Expected diff %q, got %q", want, diff)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := checkReport(filepath.Join(t.TempDir(), "missing.md"), existing); err == nil {
+			t.Error("Expected an error for a missing file")
+		}
+	})
+}
+
+func TestCheckReport_JSON(t *testing.T) {
+	prs := []*model.PR{{Number: 1, Title: "Add login", Author: "alice", Repository: "acme/api"}}
+	renderAt := func(now time.Time, reportID string, compact bool, prs []*model.PR) string {
+		since, until := now.AddDate(0, 0, -7), now
+		meta := render.Metadata{
+			ReportID:    reportID,
+			GeneratedAt: now,
+			Since:       "-7d",
+			SinceTime:   &since,
+			UntilTime:   &until,
+			TotalPRs:    len(prs),
+		}
+		output, err := render.RenderJSONWithOptions(meta, prs, render.Options{CompactJSON: compact})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return output
+	}
+
+	first := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	second := first.Add(26 * time.Hour)
+
+	for _, compact := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "report.json")
+		if err := os.WriteFile(path, []byte(renderAt(first, "1111", compact, prs)), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		diff, err := checkReport(path, renderAt(second, "2222", compact, prs))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff != "" {
+			t.Errorf("Expected no diff with compact=%v apart from volatile metadata, got:\n%s", compact, diff)
+		}
+
+		changed := append(prs, &model.PR{Number: 2, Title: "Fix logout", Author: "bob", Repository: "acme/api"})
+		diff, err = checkReport(path, renderAt(second, "2222", compact, changed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(diff, `+       "title": "Fix logout"`) {
+			t.Errorf("Expected the new PR in the diff with compact=%v, got:\n%s", compact, diff)
+		}
+	}
+}
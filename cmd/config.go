@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/willis7/prtool/internal/config"
+)
+
+// configCmd groups the config file subcommands
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the prtool configuration file",
+}
+
+// configMigrateCmd represents the config migrate command
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade deprecated keys in the configuration file",
+	Long: `Rewrite deprecated keys of the configuration file (--config, default
+~/.prtool.yaml) in their current form, e.g. a single team string becomes a list
+of teams. Comments and key order are kept, and the original file is saved
+next to it with a .bak suffix.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigMigrate,
+}
+
+func init() {
+	configCmd.AddCommand(configMigrateCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfigMigrate(cmd *cobra.Command, args []string) error {
+	configPath := cfgFile
+	if configPath == "" {
+		configPath = "~/.prtool.yaml"
+	}
+	path, err := config.ExpandHome(configPath)
+	if err != nil {
+		return err
+	}
+
+	changes, err := migrateConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(changes) == 0 {
+		_, err = fmt.Fprintf(out, "%s is up to date\n", path)
+		return err
+	}
+	for _, change := range changes {
+		fmt.Fprintf(out, "  - %s\n", change)
+	}
+	_, err = fmt.Fprintf(out, "Migrated %s (backup: %s.bak)\n", path, path)
+	return err
+}
+
+// migrateConfigFile migrates the config file at path in place, keeping the
+// original as path.bak. It returns the changes made; an up-to-date file is
+// left untouched.
+func migrateConfigFile(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	migrated, changes, err := config.Migrate(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	// The config file may hold a token, so both files keep its permissions
+	mode := info.Mode().Perm()
+	if err := os.WriteFile(path+".bak", content, mode); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := writeToFile(path, string(migrated), mode); err != nil {
+		return nil, fmt.Errorf("failed to write config file: %w", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".prtool.yaml")
+	original := "github_token: \"\"\nteam: acme/backend\n"
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := migrateConfigFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Errorf("Expected one change, got %v", changes)
+	}
+
+	migrated, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "github_token: \"\"\nteam:\n  - acme/backend\n"; string(migrated) != expected {
+		t.Errorf("Expected migrated config %q, got %q", expected, migrated)
+	}
+
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil {
+		t.Fatalf("Expected a backup: %v", err)
+	}
+	if string(backup) != original {
+		t.Errorf("Expected the backup to hold the original config, got %q", backup)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the config file to keep mode 0600, got %v", info.Mode().Perm())
+	}
+
+	// A current config is left untouched
+	if err := os.Remove(path + ".bak"); err != nil {
+		t.Fatal(err)
+	}
+	changes, err = migrateConfigFile(path)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("Expected no changes for a current config, got %v, %v", changes, err)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Error("Expected no backup when nothing was migrated")
+	}
+}
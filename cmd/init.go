@@ -76,6 +76,16 @@ repo: ""
 # Environment variable: PRTOOL_SINCE
 since: "-7d"
 
+# PR processing
+# Remove common PR template boilerplate (headings, checklists, comments) from PR bodies
+# Environment variable: PRTOOL_STRIP_PR_TEMPLATE
+strip_pr_template: false
+
+# Map label names to a canonical name so grouping and counts agree across repos
+# (matched case-insensitively), e.g. {"Bug": "bug", "type:bug": "bug"}
+# Environment variable: PRTOOL_LABEL_ALIASES (e.g. "Bug=bug,type:bug=bug")
+label_aliases: {}
+
 # LLM configuration
 # LLM provider: "stub", "openai", or "ollama"
 # Environment variable: PRTOOL_LLM_PROVIDER
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/willis7/prtool/internal/config"
+)
+
+// Artifact is an output written by a run, as listed in the --manifest file
+type Artifact struct {
+	Path   string `json:"path"`
+	Format string `json:"format"`
+	// Bytes is the size of the content written by this run; with --append the
+	// file itself may be larger
+	Bytes int `json:"bytes"`
+}
+
+// Manifest lists the outputs written by a run, for downstream jobs
+type Manifest struct {
+	ReportID    string     `json:"report_id"`
+	GeneratedAt time.Time  `json:"generated_at"`
+	Artifacts   []Artifact `json:"artifacts"`
+}
+
+// outputArtifact describes content written to path with cfg's format
+func outputArtifact(cfg *config.Config, path, content string) Artifact {
+	format := cfg.Format
+	if cfg.Template != "" {
+		format = "template"
+	} else if format == "" {
+		format = "markdown"
+	}
+	return Artifact{Path: path, Format: format, Bytes: len(applyLineEnding(content, cfg.LineEnding))}
+}
+
+// writeManifest writes the manifest as indented JSON to path
+func writeManifest(path string, manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	return writeToFile(path, string(data)+"\n", defaultOutputMode)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/willis7/prtool/internal/config"
+	"github.com/willis7/prtool/internal/model"
+)
+
+func TestWriteManifest(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "report.md")
+	securityPath := filepath.Join(dir, "security.md")
+	manifestPath := filepath.Join(dir, "manifest.json")
+
+	prs := []*model.PR{
+		{Title: "Patch token leak", Repository: "acme/api", Number: 1, Labels: []string{"security"}},
+		{Title: "Add dark mode", Repository: "acme/web", Number: 2},
+	}
+	cfg := &config.Config{Org: "acme", Output: mainPath, Routes: []string{"label=security:" + securityPath}}
+
+	metadata := generateMetadata(cfg, prs, nil)
+	content, err := renderReport(cfg, metadata, prs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := writeOutput(cfg, nil, content, prs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	routed, err := writeRoutes(cfg, nil, metadata, prs, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	manifest := Manifest{ReportID: metadata.ReportID, GeneratedAt: metadata.GeneratedAt}
+	manifest.Artifacts = append([]Artifact{outputArtifact(cfg, mainPath, content)}, routed...)
+	if err := writeManifest(manifestPath, manifest); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var got Manifest
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Invalid manifest JSON: %v\n%s", err, data)
+	}
+
+	if got.ReportID != metadata.ReportID || len(got.Artifacts) != 2 {
+		t.Fatalf("Expected both outputs for report %s, got:\n%s", metadata.ReportID, data)
+	}
+	for i, path := range []string{mainPath, securityPath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Failed to stat %s: %v", path, err)
+		}
+		artifact := got.Artifacts[i]
+		if artifact.Path != path || artifact.Format != "markdown" || int64(artifact.Bytes) != info.Size() {
+			t.Errorf("Expected %s (markdown, %d bytes), got %+v", path, info.Size(), artifact)
+		}
+	}
+}
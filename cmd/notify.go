@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/willis7/prtool/internal/config"
+	"github.com/willis7/prtool/internal/logger"
+)
+
+// notifyClient sends --notify-on-error notifications
+var notifyClient = &http.Client{Timeout: 10 * time.Second}
+
+// ErrorNotification is the JSON payload POSTed to --notify-on-error when a
+// run fails
+type ErrorNotification struct {
+	Error     string    `json:"error"`
+	Scope     string    `json:"scope"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// notifyError POSTs an ErrorNotification describing runErr to webhookURL.
+// cfg is nil when the configuration could not be loaded; the scope is then
+// left empty.
+func notifyError(webhookURL string, cfg *config.Config, runErr error) error {
+	var scope string
+	if cfg != nil {
+		metadata := generateMetadata(cfg, nil, nil)
+		scope = metadata.Scope
+		if metadata.ScopeValue != "" {
+			scope = fmt.Sprintf("%s (%s)", metadata.Scope, metadata.ScopeValue)
+		}
+	}
+
+	payload, err := json.Marshal(ErrorNotification{
+		Error:     runErr.Error(),
+		Scope:     scope,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := notifyClient.Post(webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// isValidNotifyURL reports whether webhookURL is an http or https URL
+func isValidNotifyURL(webhookURL string) bool {
+	u, err := url.Parse(webhookURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// notifyFailure sends runErr to webhookURL when it is a valid webhook URL.
+// It is used before the logger exists, so delivery failures go to stderr.
+func notifyFailure(webhookURL string, cfg *config.Config, runErr error) {
+	if !isValidNotifyURL(webhookURL) {
+		return
+	}
+	if err := notifyError(webhookURL, cfg, runErr); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to send error notification: %v\n", err)
+	}
+}
+
+// loadRunConfig loads and validates the configuration for a run. Failures are
+// sent to --notify-on-error as well: from the flag or environment when the
+// config file cannot be loaded, and from the loaded config otherwise.
+func loadRunConfig() (*config.Config, error) {
+	cfg, err := GetConfig()
+	if err != nil {
+		runErr := fmt.Errorf("failed to load config: %w", err)
+		webhookURL := notifyOnError
+		if webhookURL == "" {
+			webhookURL = os.Getenv("PRTOOL_NOTIFY_ON_ERROR")
+		}
+		notifyFailure(webhookURL, nil, runErr)
+		return nil, runErr
+	}
+
+	if err := validateConfig(cfg); err != nil {
+		runErr := fmt.Errorf("invalid configuration: %w", err)
+		notifyFailure(cfg.NotifyOnError, cfg, runErr)
+		return nil, runErr
+	}
+	return cfg, nil
+}
+
+// notifyRunError sends runErr to --notify-on-error when it is set, logging
+// delivery failures
+func notifyRunError(cfg *config.Config, log *logger.Logger, runErr error) {
+	if cfg.NotifyOnError == "" {
+		return
+	}
+	if err := notifyError(cfg.NotifyOnError, cfg, runErr); err != nil {
+		log.Error("Failed to send error notification: %v", err)
+	}
+}
+
+// exitWithError logs a failed run, notifies --notify-on-error when set and
+// exits non-zero
+func exitWithError(cfg *config.Config, log *logger.Logger, format string, args ...interface{}) {
+	runErr := fmt.Errorf(format, args...)
+	log.Error("%v", runErr)
+	notifyRunError(cfg, log, runErr)
+	os.Exit(1)
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/willis7/prtool/internal/config"
+	"github.com/willis7/prtool/internal/gh"
+	"github.com/willis7/prtool/internal/service"
+)
+
+func TestNotifyError(t *testing.T) {
+	var received ErrorNotification
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode notification: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	// Force a fetch error
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{{FullName: github.String("acme/api")}})
+	mockClient.PRError = errors.New("API rate limit exceeded")
+	cfg := &config.Config{Org: "acme", Since: "-7d", NotifyOnError: server.URL}
+	_, fetchErr := service.NewFetcher(mockClient).Fetch(cfg)
+	if fetchErr == nil {
+		t.Fatal("Expected the fetch to fail")
+	}
+
+	if err := notifyError(cfg.NotifyOnError, cfg, fetchErr); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if contentType != "application/json" {
+		t.Errorf("Expected a JSON payload, got content type %q", contentType)
+	}
+	if !strings.Contains(received.Error, "API rate limit exceeded") {
+		t.Errorf("Expected the error message in the notification, got %q", received.Error)
+	}
+	if received.Scope != "organization (acme)" {
+		t.Errorf("Expected scope %q, got %q", "organization (acme)", received.Scope)
+	}
+	if time.Since(received.Timestamp) > time.Minute {
+		t.Errorf("Expected a recent timestamp, got %v", received.Timestamp)
+	}
+}
+
+func TestNotifyError_WebhookFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such hook", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := notifyError(server.URL, &config.Config{Org: "acme"}, errors.New("boom"))
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Expected an error with the webhook status, got %v", err)
+	}
+}
+
+func TestLoadRunConfig_NotifiesFailures(t *testing.T) {
+	var received []ErrorNotification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification ErrorNotification
+		if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+			t.Errorf("Failed to decode notification: %v", err)
+		}
+		received = append(received, notification)
+	}))
+	defer server.Close()
+
+	originalCfgFile := cfgFile
+	t.Cleanup(func() { cfgFile = originalCfgFile })
+	originalNotifyOnError := notifyOnError
+	t.Cleanup(func() { notifyOnError = originalNotifyOnError })
+	notifyOnError = ""
+
+	t.Run("validation failure", func(t *testing.T) {
+		received = nil
+		cfgFile = filepath.Join(t.TempDir(), "config.yaml")
+		content := "org: acme\ngithub_token: test-token\nstrip_urls: true\nnotify_on_error: " + server.URL + "\n"
+		if err := os.WriteFile(cfgFile, []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+
+		_, err := loadRunConfig()
+		if err == nil || !strings.Contains(err.Error(), "invalid configuration") {
+			t.Fatalf("Expected a configuration error, got %v", err)
+		}
+		if len(received) != 1 || !strings.Contains(received[0].Error, "--strip-urls requires --anonymize") {
+			t.Fatalf("Expected one notification with the validation error, got %+v", received)
+		}
+		if received[0].Scope != "organization (acme)" {
+			t.Errorf("Expected scope %q, got %q", "organization (acme)", received[0].Scope)
+		}
+	})
+
+	t.Run("unreadable config uses the flag", func(t *testing.T) {
+		received = nil
+		cfgFile = filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(cfgFile, []byte("org: [unterminated\n"), 0600); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		notifyOnError = server.URL
+
+		_, err := loadRunConfig()
+		if err == nil || !strings.Contains(err.Error(), "failed to load config") {
+			t.Fatalf("Expected a config loading error, got %v", err)
+		}
+		if len(received) != 1 || !strings.Contains(received[0].Error, "failed to load config") || received[0].Scope != "" {
+			t.Errorf("Expected one notification without a scope, got %+v", received)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/willis7/prtool/internal/gh"
+	"github.com/willis7/prtool/internal/llm"
+	"github.com/willis7/prtool/internal/logger"
+	"github.com/willis7/prtool/internal/model"
+)
+
+// prCmd represents the pr command
+var prCmd = &cobra.Command{
+	Use:   "pr <url>",
+	Short: "Summarize a single pull request",
+	Long: `Fetch one pull request by its GitHub URL, including its description and
+changed files, and print an AI summary of it. Scope and time range settings
+are ignored; GitHub and LLM settings come from the usual flags, environment
+and config file.`,
+	Example: "  prtool pr https://github.com/octocat/hello-world/pull/42 --llm-provider=openai",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runPR,
+}
+
+// newPRClient creates the GitHub client used by the pr command. Tests
+// replace it to avoid real API calls.
+var newPRClient = func(token string, opts ...gh.ClientOption) (gh.GitHubClient, error) {
+	return gh.NewRestClient(token, opts...)
+}
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+}
+
+func runPR(cmd *cobra.Command, args []string) error {
+	repo, number, err := gh.ParsePRURL(args[0])
+	if err != nil {
+		return err
+	}
+
+	cfg, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+	if cfg.GitHubToken == "" {
+		return fmt.Errorf("GitHub token is required")
+	}
+
+	log, err := logger.New(cfg.Verbose, cfg.CI, cfg.LogFile)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	ghClient, err := newPRClient(cfg.GitHubToken, githubClientOptions(cfg)...)
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	pr, summary, err := summarizePR(ghClient, withSummaryCache(cfg, log, createLLMClient(cfg, log)), repo, number, cfg.SummaryStyle)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), formatPRSummary(pr, summary))
+	return err
+}
+
+// summarizePR fetches a single PR and summarizes it with the LLM
+func summarizePR(ghClient gh.GitHubClient, llmClient llm.LLM, repo string, number int, style string) (*model.PR, string, error) {
+	pr, err := ghClient.GetPR(repo, number)
+	if err != nil {
+		return nil, "", err
+	}
+
+	context := llm.ApplySummaryStyle(llm.BuildContext([]*model.PR{pr}), style)
+	summary, err := llmClient.Summarise(context)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to summarize PR %s#%d: %w", repo, number, err)
+	}
+	if style == llm.SummaryStyleBullets {
+		summary = llm.FormatBullets(summary)
+	}
+
+	return pr, summary, nil
+}
+
+// formatPRSummary renders the summary of a single PR as Markdown
+func formatPRSummary(pr *model.PR, summary string) string {
+	return fmt.Sprintf("# %s (%s#%d)\n\n- **Author**: %s\n- **URL**: %s\n\n%s\n",
+		pr.Title, pr.Repository, pr.Number, pr.Author, pr.HTMLURL, summary)
+}
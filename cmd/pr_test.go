@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/willis7/prtool/internal/gh"
+	"github.com/willis7/prtool/internal/llm"
+	"github.com/willis7/prtool/internal/model"
+)
+
+func TestSummarizePR(t *testing.T) {
+	mergedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	client := gh.NewMockClient()
+	client.SetMockPRs([]*model.PR{
+		{
+			Title:      "Add caching layer",
+			Body:       "Caches API responses",
+			Author:     "alice",
+			Repository: "acme/api",
+			Number:     42,
+			MergedAt:   &mergedAt,
+			State:      "closed",
+			FilePaths:  []string{"cache.go", "cache_test.go"},
+			HTMLURL:    "https://github.com/acme/api/pull/42",
+		},
+	})
+
+	pr, summary, err := summarizePR(client, llm.NewStubLLMWithSummary("Adds a cache."), "acme/api", 42, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if summary != "Adds a cache." {
+		t.Errorf("Expected stub summary, got %q", summary)
+	}
+
+	out := formatPRSummary(pr, summary)
+	for _, want := range []string{"# Add caching layer (acme/api#42)", "alice", "Adds a cache."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	if len(client.CallLog) != 1 || client.CallLog[0] != "GetPR(acme/api, 42)" {
+		t.Errorf("Expected a single GetPR call, got %v", client.CallLog)
+	}
+}
+
+func TestSummarizePR_NotFound(t *testing.T) {
+	client := gh.NewMockClient()
+
+	if _, _, err := summarizePR(client, llm.NewStubLLM(), "acme/api", 7, ""); err == nil {
+		t.Error("Expected error for missing PR, got nil")
+	}
+}
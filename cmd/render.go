@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/willis7/prtool/internal/config"
+	"github.com/willis7/prtool/internal/llm"
+	"github.com/willis7/prtool/internal/logger"
+	"github.com/willis7/prtool/internal/render"
+)
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Re-render a saved JSON report",
+	Long: `Read a report saved with --format json and render it again in any output
+format, without contacting GitHub. The saved AI summary is kept unless
+--summarize asks the LLM for a new one. Rendering settings such as --group-by
+come from the environment and config file.`,
+	Example: `  prtool render --input report.json --format markdown --output report.md
+  prtool render --input report.json --summarize --llm-provider=openai`,
+	Args: cobra.NoArgs,
+	RunE: runRender,
+}
+
+var (
+	renderInput     string
+	renderSummarize bool
+)
+
+func init() {
+	renderCmd.Flags().StringVar(&renderInput, "input", "", "JSON report written by --format json")
+	renderCmd.Flags().BoolVar(&renderSummarize, "summarize", false, "Generate a new AI summary instead of keeping the saved one")
+	// Shared with the root command
+	renderCmd.Flags().StringVar(&format, "format", "", "Output format (markdown, json, jsonl, badge)")
+	renderCmd.Flags().StringVar(&output, "output", "", "Output file path, or an s3://bucket/key or gs://bucket/key URL")
+	renderCmd.Flags().StringVar(&llmProvider, "llm-provider", "", "LLM provider (openai, ollama)")
+	renderCmd.Flags().StringVar(&llmAPIKey, "llm-api-key", "", "LLM API key")
+	renderCmd.Flags().StringVar(&llmModel, "llm-model", "", "LLM model name")
+	_ = renderCmd.MarkFlagRequired("input")
+	rootCmd.AddCommand(renderCmd)
+}
+
+func runRender(cmd *cobra.Command, args []string) error {
+	cfg, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	log, err := logger.New(cfg.Verbose, cfg.CI, cfg.LogFile)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	data, err := os.ReadFile(renderInput)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+
+	content, err := renderSaved(cfg, log, data, renderSummarize)
+	if err != nil {
+		return err
+	}
+
+	return writeOutput(cfg, log, content, nil)
+}
+
+// renderSaved renders a saved JSON report with cfg, optionally replacing its
+// AI summary with a new one
+func renderSaved(cfg *config.Config, log *logger.Logger, data []byte, summarize bool) (string, error) {
+	var report render.JSONReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return "", fmt.Errorf("failed to parse JSON report: %w", err)
+	}
+	metadata, prs := report.Metadata, report.PullRequests
+
+	if summarize {
+		llmClient := withSummaryCache(cfg, log, createLLMClient(cfg, log))
+		summary, err := generateSummary(cfg, log, llmClient, prs)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate AI summary: %w", err)
+		}
+		if cfg.SummaryStyle == llm.SummaryStyleBullets {
+			summary = llm.FormatBullets(summary)
+		}
+		metadata.Summary = summary
+		metadata.LLMProvider, metadata.LLMModel = cfg.LLMProvider, cfg.LLMModel
+	}
+
+	return renderReport(cfg, metadata, prs)
+}
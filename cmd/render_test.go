@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/willis7/prtool/internal/config"
+)
+
+func TestRenderSaved_Markdown(t *testing.T) {
+	data, err := os.ReadFile("testdata/report.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	cfg := &config.Config{Format: "markdown", ShowEmptySections: true}
+	content, err := renderSaved(cfg, nil, data, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"acme",
+		"Saved summary of the week.",
+		"### 1. Add rate limiting",
+		"[View PR](https://github.com/acme/web/pull/7)",
+		"report 6f1c2d4e-8a3b-4c5d-9e7f-0a1b2c3d4e5f",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, content)
+		}
+	}
+}
+
+func TestRenderSaved_Summarize(t *testing.T) {
+	data, err := os.ReadFile("testdata/report.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	cfg := &config.Config{Format: "json", LLMProvider: "stub"}
+	content, err := renderSaved(cfg, nil, data, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if strings.Contains(content, "Saved summary of the week.") {
+		t.Errorf("Expected the saved summary to be replaced, got:\n%s", content)
+	}
+	if !strings.Contains(content, "stub LLM") || !strings.Contains(content, `"llm_provider": "stub"`) {
+		t.Errorf("Expected a new stub summary, got:\n%s", content)
+	}
+}
+
+func TestRenderSaved_InvalidJSON(t *testing.T) {
+	_, err := renderSaved(&config.Config{}, nil, []byte("# not json"), false)
+	if err == nil || !strings.Contains(err.Error(), "failed to parse JSON report") {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+}
@@ -1,16 +1,27 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/willis7/prtool/internal/build"
+	"github.com/willis7/prtool/internal/clipboard"
 	"github.com/willis7/prtool/internal/config"
 	"github.com/willis7/prtool/internal/gh"
 	"github.com/willis7/prtool/internal/llm"
@@ -19,6 +30,8 @@ import (
 	"github.com/willis7/prtool/internal/render"
 	"github.com/willis7/prtool/internal/scope"
 	"github.com/willis7/prtool/internal/service"
+	"github.com/willis7/prtool/internal/storage"
+	"github.com/willis7/prtool/internal/timeutil"
 )
 
 var version = "dev"
@@ -27,11 +40,13 @@ var version = "dev"
 var (
 	cfgFile      string
 	githubToken  string
+	userAgent    string
 	org          string
 	team         string
 	user         string
 	repo         string
 	since        string
+	until        string
 	llmProvider  string
 	llmAPIKey    string
 	llmModel     string
@@ -42,6 +57,90 @@ var (
 	ci           bool
 	logFile      string
 	versionCheck bool
+	printConfig  bool
+
+	stripPRTemplate    bool
+	excludeDrafts      bool
+	outputStdoutFormat string
+	maxLLMTokens       int
+	copyToClipboard    bool
+	format             string
+	mergeTarget        bool
+	largeWindowDays    int
+	allowLargeWindow   bool
+	topic              string
+	facetTopics        bool
+	jsonCompact        bool
+	extraRepos         []string
+	badgeLabel         string
+	badgeYellowAt      int
+	badgeGreenAt       int
+	groupBy            string
+	plan               bool
+	githubHeaders      []string
+	templateDir        string
+	templateName       string
+	summaryStyle       string
+	llmChunkTokens     int
+	llmConcurrency     int
+	minLabelCount      int
+	excludeReposNoPRs  bool
+	repoOrder          string
+	fixtureDump        string
+	confirmThreshold   int
+	headingOffset      int
+	repoFilterExpr     string
+	noDetailsBody      bool
+	pruneEmptySections bool
+	force              bool
+	includeFiles       bool
+	reportID           string
+	noSummaryCache     bool
+	appendOutput       bool
+	linkStyle          string
+	strictProvider     bool
+	routes             []string
+	contextFields      []string
+	maxOutputSize      string
+	paths              []string
+	excludePaths       []string
+	dateField          string
+	outputMode         string
+	grepTerms          []string
+	grepRegex          []string
+	statusIcons        bool
+	weightBy           string
+	perAuthorSummary   bool
+	repoLimit          int
+	includeCommits     bool
+	authorDomains      []string
+	detailsFormat      string
+	lineEnding         string
+	dedupeBy           string
+	githubTimeout      string
+	minSummaryChars    int
+	anonymize          bool
+	stripURLs          bool
+	summaryModel       string
+	manifestPath       string
+	lenientChunks      bool
+	checkOnly          string
+	scanOrder          string
+	estimate           bool
+	llmPricePer1K      string
+	notifyOnError      string
+	labelsAny          []string
+	labelsAll          []string
+	excludeLabels      []string
+	repoCacheFile      string
+	repoCacheTTL       string
+	refreshRepos       bool
+	minChanges         int
+	llmTemperature     string
+	llmTopP            string
+	llmMaxTokens       int
+	concurrency        int
+	repoDescriptions   bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -84,28 +183,117 @@ func init() {
 
 	// GitHub flags
 	rootCmd.Flags().StringVar(&githubToken, "github-token", "", "GitHub personal access token")
+	rootCmd.Flags().StringVar(&userAgent, "user-agent", "", "User-Agent header for GitHub requests (default prtool/<version>)")
+	rootCmd.Flags().StringArrayVar(&githubHeaders, "github-header", nil, "Extra header for GitHub requests (format: key=value, repeatable)")
+	rootCmd.Flags().StringVar(&githubTimeout, "github-timeout", "", "Timeout of each GitHub request, e.g. 30s or 2m; 0 disables it (default 30s)")
+	// Maintainer-only: records API responses for replay tests
+	rootCmd.Flags().StringVar(&fixtureDump, "fixture-dump", "", "Write raw GitHub API responses to this directory")
+	_ = rootCmd.Flags().MarkHidden("fixture-dump")
 
 	// Scope flags (mutually exclusive)
 	rootCmd.Flags().StringVar(&org, "org", "", "GitHub organization")
 	rootCmd.Flags().StringVar(&team, "team", "", "GitHub team(s) (format: org/team or comma-separated: org/team1,org/team2)")
-	rootCmd.Flags().StringVar(&user, "user", "", "GitHub user")
+	rootCmd.Flags().StringVar(&user, "user", "", "GitHub user (use @me for the authenticated user)")
 	rootCmd.Flags().StringVar(&repo, "repo", "", "GitHub repository (format: owner/repo)")
+	rootCmd.Flags().StringVar(&topic, "topic", "", "Only include repositories tagged with this topic")
+	rootCmd.Flags().StringVar(&repoFilterExpr, "repo-filter-expr", "", "Only include repositories matching this expression (fields: "+strings.Join(scope.RepoFilterFields, ", ")+")")
+	rootCmd.Flags().StringArrayVar(&extraRepos, "extra-repo", nil, "Additional repository to fetch alongside the scope (format: owner/repo, repeatable)")
+	rootCmd.Flags().IntVar(&repoLimit, "repo-limit", 0, "Scan only the first N repositories of the scope, in --scan-order or the order GitHub lists them (0 means no limit)")
+	rootCmd.Flags().StringVar(&repoCacheFile, "repo-cache-file", "", "Cache the repository listing of the scope in this file and reuse it until it is older than --repo-cache-ttl")
+	rootCmd.Flags().StringVar(&repoCacheTTL, "repo-cache-ttl", "", "How long a --repo-cache-file listing is reused, e.g. 12h (default 24h)")
+	rootCmd.Flags().BoolVar(&refreshRepos, "refresh-repos", false, "List the scope's repositories again and update --repo-cache-file")
+	rootCmd.Flags().IntVar(&concurrency, "concurrency", 0, "Maximum number of repositories fetched at once (default 8); see --llm-concurrency for LLM calls")
+	rootCmd.Flags().StringVar(&scanOrder, "scan-order", "", "Order the repositories to scan by "+strings.Join(scope.ScanOrderValues, ", ")+" before --repo-limit applies (default GitHub's listing order)")
+	rootCmd.Flags().IntVar(&confirmThreshold, "confirm-threshold", 0, fmt.Sprintf("Ask before scanning more than this many repositories outside CI mode (default %d)", defaultConfirmThreshold))
 
 	// Time range
-	rootCmd.Flags().StringVar(&since, "since", "", "Time range (e.g., -7d, -1m, -1yr)")
+	rootCmd.Flags().StringVar(&since, "since", "", "Time range (e.g., -7d, -1m, -1yr, today, yesterday, 2024-01-01, or latest-release for per-repo windows)")
+	rootCmd.Flags().StringVar(&until, "until", "", "End of the time range, as a relative duration like --since or a date (YYYY-MM-DD or RFC 3339) (default now)")
+	rootCmd.Flags().StringVar(&dateField, "date-field", "", "PR timestamp the since window filters on: "+strings.Join(service.DateFields, ", ")+" (default merged)")
+	rootCmd.Flags().IntVar(&largeWindowDays, "large-window-days", 0, fmt.Sprintf("Warn when the since window exceeds this many days (default %d)", defaultLargeWindowDays))
+	rootCmd.Flags().BoolVar(&allowLargeWindow, "allow-large-window", false, "Allow since windows larger than --large-window-days in CI mode")
+
+	// PR processing flags
+	rootCmd.Flags().BoolVar(&stripPRTemplate, "strip-pr-template", false, "Remove common PR template boilerplate from PR bodies")
+	rootCmd.Flags().BoolVar(&excludeDrafts, "exclude-drafts", true, "Exclude draft PRs (use --exclude-drafts=false to include them)")
+	rootCmd.Flags().BoolVar(&includeFiles, "include-files", false, "Fetch the files changed by each PR")
+	rootCmd.Flags().StringArrayVar(&paths, "path", nil, "Only include PRs changing a file matching this glob, ** matches directories (repeatable, needs --include-files)")
+	rootCmd.Flags().StringArrayVar(&excludePaths, "exclude-path", nil, "Exclude PRs changing a file matching this glob (repeatable, needs --include-files)")
+	rootCmd.Flags().IntVar(&minChanges, "min-changes", 0, "Exclude PRs with fewer changed lines (additions plus deletions); fetches line counts per PR")
+	rootCmd.Flags().StringSliceVar(&labelsAny, "labels-any", nil, "Only include PRs with at least one of these labels (comma-separated or repeatable)")
+	rootCmd.Flags().StringSliceVar(&labelsAll, "labels-all", nil, "Only include PRs with all of these labels (comma-separated or repeatable)")
+	rootCmd.Flags().StringSliceVar(&excludeLabels, "exclude-label", nil, "Exclude PRs with any of these labels (comma-separated or repeatable)")
+	rootCmd.Flags().BoolVar(&includeCommits, "include-commits", false, "Fetch the commits of each PR to capture the author's commit email")
+	rootCmd.Flags().StringArrayVar(&authorDomains, "author-domain", nil, "Only include PRs whose author commit email is in this domain or its subdomains (repeatable, needs --include-commits)")
+	rootCmd.Flags().StringArrayVar(&grepTerms, "grep", nil, "Only include PRs whose title or body contains this term, ignoring case (repeatable)")
+	rootCmd.Flags().StringArrayVar(&grepRegex, "grep-regex", nil, "Only include PRs whose title or body matches this regular expression (repeatable)")
 
 	// LLM flags
 	rootCmd.Flags().StringVar(&llmProvider, "llm-provider", "", "LLM provider (openai, ollama)")
+	rootCmd.Flags().BoolVar(&anonymize, "anonymize", false, "Replace author logins with stable pseudonyms such as \"Contributor A\" in the report and LLM context")
+	rootCmd.Flags().BoolVar(&stripURLs, "strip-urls", false, "Remove PR links from an anonymized report (requires --anonymize)")
+	rootCmd.Flags().BoolVar(&perAuthorSummary, "per-author-summary", false, "Group the details by author with an AI summary of each author's PRs (one LLM call per author)")
+	rootCmd.Flags().StringVar(&weightBy, "weight-by", "", "Mark each PR's relative importance in the LLM context by "+strings.Join(llm.WeightByValues, " or ")+" (size fetches line counts per PR)")
+	rootCmd.Flags().StringSliceVar(&contextFields, "context-fields", nil, "PR attributes sent to the LLM, comma-separated ("+strings.Join(llm.ContextFields, ", ")+"; default all)")
+	rootCmd.Flags().BoolVar(&strictProvider, "strict-provider", false, "Fail on an unknown --llm-provider instead of falling back to the stub (always on in CI mode)")
 	rootCmd.Flags().StringVar(&llmAPIKey, "llm-api-key", "", "LLM API key")
 	rootCmd.Flags().StringVar(&llmModel, "llm-model", "", "LLM model name")
+	rootCmd.Flags().StringVar(&summaryModel, "summary-model", "", "LLM model for the final summary call; --llm-model is used for chunk summaries (default: --llm-model)")
 	rootCmd.Flags().StringVar(&prompt, "prompt", "", "Path to custom prompt file")
+	rootCmd.Flags().StringVar(&summaryStyle, "summary-style", "", "AI summary style (prose, bullets)")
+	rootCmd.Flags().IntVar(&maxLLMTokens, "max-llm-tokens", 0, "Maximum estimated tokens sent to the LLM (0 for unlimited)")
+	rootCmd.Flags().IntVar(&minSummaryChars, "min-summary-chars", 0, "Retry a summary shorter than this many characters once with a stricter prompt (0 disables the check)")
+	rootCmd.Flags().StringVar(&llmTemperature, "llm-temperature", "", fmt.Sprintf("Sampling temperature of summary requests, 0 to 2; lower is more reproducible (default %g)", llm.DefaultTemperature))
+	rootCmd.Flags().StringVar(&llmTopP, "llm-top-p", "", "Nucleus sampling probability of summary requests, 0 to 1 (default the provider's)")
+	rootCmd.Flags().IntVar(&llmMaxTokens, "llm-max-tokens", 0, "Maximum length of each summary in tokens (default 500 for OpenAI, the model's for Ollama)")
+	rootCmd.Flags().BoolVar(&estimate, "estimate", false, "Print the estimated LLM input tokens and cost without calling the LLM")
+	rootCmd.Flags().StringVar(&llmPricePer1K, "llm-price-per-1k", "", "Price in US dollars per 1,000 input tokens for --estimate (default the provider's approximate price)")
+	rootCmd.Flags().BoolVar(&lenientChunks, "lenient-chunks", false, "With --llm-chunk-tokens, summarize the chunks that succeeded when some chunk calls fail, noting how many were skipped")
+	rootCmd.Flags().IntVar(&llmChunkTokens, "llm-chunk-tokens", 0, "Summarize PRs in chunks of this many estimated tokens, then combine (0 disables chunking)")
+	rootCmd.Flags().IntVar(&llmConcurrency, "llm-concurrency", 0, "Maximum number of chunk summaries requested at once (default 1)")
+	rootCmd.Flags().BoolVar(&noSummaryCache, "no-summary-cache", false, "Always request a new AI summary instead of reusing one cached for the same PRs")
 
 	// Output flags
-	rootCmd.Flags().StringVar(&output, "output", "", "Output file path")
+	rootCmd.Flags().StringVar(&output, "output", "", "Output file path, or an s3://bucket/key or gs://bucket/key URL")
+	rootCmd.Flags().StringVar(&format, "format", "", "Output format (markdown, json, jsonl, badge)")
+	rootCmd.Flags().StringVar(&templateDir, "template-dir", "", "Directory of named report templates (<name>.tmpl)")
+	rootCmd.Flags().StringVar(&templateName, "template", "", "Name of the template in --template-dir to render the report with")
+	rootCmd.Flags().BoolVar(&noDetailsBody, "no-details-body", false, "Render PR details as one line each, without descriptions")
+	rootCmd.Flags().BoolVar(&statusIcons, "status-icons", false, "Prefix each PR in the details with its state: ✅ merged, 🔄 open, ❌ closed unmerged, 📝 draft")
+	rootCmd.Flags().StringVar(&dedupeBy, "dedupe-by", "", "Collapse PRs in the details into one line listing every repository: title (matching normalized titles)")
+	rootCmd.Flags().StringVar(&detailsFormat, "details-format", "", "Render each PR in the details as one line from this format, e.g. '#{number} {title} ({author})'; placeholders: "+strings.Join(render.DetailsPlaceholders, ", "))
+	rootCmd.Flags().StringVar(&linkStyle, "link-style", "", "Markdown link style: inline, or reference for numbered links listed at the end (default inline)")
+	rootCmd.Flags().BoolVar(&pruneEmptySections, "prune-empty-sections", true, "Omit groups without PRs from the report (use --prune-empty-sections=false to show them)")
+	rootCmd.Flags().IntVar(&headingOffset, "heading-offset", 0, "Shift Markdown report headings down this many levels (e.g. 1 turns h1 into h2)")
+	rootCmd.Flags().BoolVar(&jsonCompact, "json-compact", false, "Emit single-line JSON instead of indented JSON")
+	rootCmd.Flags().StringVar(&badgeLabel, "badge-label", "", fmt.Sprintf("Label for --format badge (default %q)", render.DefaultBadgeLabel))
+	rootCmd.Flags().IntVar(&badgeYellowAt, "badge-yellow-at", 0, fmt.Sprintf("PR count at which the badge turns yellow (default %d)", render.DefaultBadgeYellowAt))
+	rootCmd.Flags().IntVar(&badgeGreenAt, "badge-green-at", 0, fmt.Sprintf("PR count at which the badge turns green (default %d)", render.DefaultBadgeGreenAt))
+	rootCmd.Flags().StringVar(&outputStdoutFormat, "output-stdout-format", "", "Also print to stdout when --output is a file (table, markdown)")
+	rootCmd.Flags().StringVar(&reportID, "report-id", "", "ID embedded in the report metadata and footer (default a random UUID)")
+	rootCmd.Flags().StringArrayVar(&routes, "route", nil, "Also write the PRs with a label to another file (format: label=<name>:<path>, repeatable)")
+	rootCmd.Flags().BoolVar(&appendOutput, "append", false, "Append the report to --output instead of replacing it")
+	rootCmd.Flags().StringVar(&lineEnding, "line-ending", "", "Newline style of the written output: lf or crlf (default lf)")
+	rootCmd.Flags().StringVar(&checkOnly, "check-only", "", "Compare the report with this existing file instead of writing it and exit non-zero with a diff if it would change")
+	rootCmd.Flags().StringVar(&notifyOnError, "notify-on-error", "", "POST a JSON notification (error, scope, timestamp) to this webhook URL when the run fails")
+	rootCmd.Flags().StringVar(&manifestPath, "manifest", "", "Write a JSON manifest listing every output written (path, format, bytes) to this file")
+	rootCmd.Flags().StringVar(&outputMode, "output-mode", "", "Octal permission mode of created --output files, e.g. 0600 (default 0644)")
+	rootCmd.Flags().StringVar(&maxOutputSize, "max-output-size", "", "With --append, rotate --output (report.md to report.1.md, ...) when it would grow past this size, e.g. 10MB")
+	rootCmd.Flags().BoolVar(&force, "force", false, "Rewrite --output even when the file already has identical content")
+	rootCmd.Flags().BoolVar(&copyToClipboard, "clipboard", false, "Copy the rendered report to the clipboard (ignored in CI mode)")
+	rootCmd.Flags().StringVar(&groupBy, "group-by", "", "Group PR details by "+strings.Join(render.GroupByValues, ", ")+" (default none)")
+	rootCmd.Flags().BoolVar(&excludeReposNoPRs, "exclude-repo-without-prs", true, "List only repositories with PRs in the report metadata (use --exclude-repo-without-prs=false to list every scanned repository)")
+	rootCmd.Flags().IntVar(&minLabelCount, "min-label-count", 0, "Hide labels used by fewer PRs from the label breakdown (default 1)")
+	rootCmd.Flags().StringVar(&repoOrder, "repo-order", "", "Order repo groups by "+strings.Join(render.RepoOrderValues, " or ")+" (default alpha)")
+	rootCmd.Flags().BoolVar(&repoDescriptions, "repo-descriptions", false, "Show each repository's description under its heading with --group-by=repo")
+	rootCmd.Flags().BoolVar(&mergeTarget, "merge-target", false, "Group PR details by the base branch they were merged into")
+	rootCmd.Flags().BoolVar(&facetTopics, "facet-topics", false, "Group PR details by repository topic")
 	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Skip LLM processing and show PR data")
+	rootCmd.Flags().BoolVar(&plan, "plan", false, "Show the repositories and query window that would be scanned, without fetching PRs")
 	rootCmd.Flags().BoolVar(&verbose, "verbose", false, "Enable verbose logging")
 	rootCmd.Flags().BoolVar(&ci, "ci", false, "Non-interactive mode for CI")
 	rootCmd.Flags().BoolVar(&versionCheck, "version-check", false, "Check for latest version on GitHub")
+	rootCmd.Flags().BoolVar(&printConfig, "print-config", false, "Print the effective configuration as YAML, with the source of each value and secrets redacted, and exit")
 	rootCmd.Flags().StringVar(&logFile, "log-file", "", "Log file path")
 
 	// Handle version flag and basic command execution
@@ -133,53 +321,117 @@ func init() {
 			return
 		}
 
-		// Load configuration
-		cfg, err := GetConfig()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-			os.Exit(1)
-		}
-
-		// Validate configuration
-		if err := validateConfig(cfg); err != nil {
-			fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
-			if cfg.CI {
+		// Handle print config flag
+		if printConfig {
+			effective, err := formatEffectiveConfig()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 				os.Exit(1)
 			}
+			fmt.Print(effective)
+			return
+		}
+
+		// Load and validate configuration
+		cfg, err := loadRunConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
 		// Create logger
 		log, err := logger.New(cfg.Verbose, cfg.CI, cfg.LogFile)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to create logger: %v\n", err)
+			runErr := fmt.Errorf("failed to create logger: %w", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", runErr)
+			notifyFailure(cfg.NotifyOnError, cfg, runErr)
 			os.Exit(1)
 		}
 
-		// Create GitHub client
+		// Guard against accidentally huge scans such as -100yr
+		if err := checkSinceWindow(cfg, log); err != nil {
+			exitWithError(cfg, log, "Configuration error: %v", err)
+		}
+
+		// Cancel the run on Ctrl-C so whatever was fetched can still be written
+		ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stopSignals()
+
+		// Create GitHub client. With only host profiles in scope every
+		// client comes from the profiles.
 		log.Progress("Connecting to GitHub...")
-		ghClient, err := gh.NewRestClient(cfg.GitHubToken)
+		clientOpts := append(githubClientOptions(cfg), gh.WithContext(ctx))
+		var ghClient gh.GitHubClient
+		if len(cfg.Hosts) == 0 || len(cfg.Scopes()) > 0 {
+			client, err := gh.NewRestClient(cfg.GitHubToken, clientOpts...)
+			if err != nil {
+				exitWithError(cfg, log, "Failed to create GitHub client: %v", err)
+			}
+			ghClient = client
+			if cfg.RepoCacheFile != "" {
+				ghClient = gh.NewRepoCache(client, cfg.RepoCacheFile, repoCacheTTLOrDefault(cfg), cfg.RefreshRepos)
+			}
+		}
+		hostClients, err := newHostClients(cfg, clientOpts)
 		if err != nil {
-			log.Error("Failed to create GitHub client: %v", err)
-			if cfg.CI {
-				os.Exit(1)
+			exitWithError(cfg, log, "Failed to create GitHub client: %v", err)
+		}
+
+		// Resolve @me to the authenticated user
+		if err := scope.ResolveSelf(cfg, ghClient); err != nil {
+			exitWithError(cfg, log, "Failed to resolve scope: %v", err)
+		}
+
+		fetcher := service.NewFetcher(ghClient)
+		fetcher.SetHostClients(hostClients)
+		fetcher.SetLogger(log)
+		fetcher.SetContext(ctx)
+		fetcher.SetConfirm(confirmScan(cfg, os.Stdin, os.Stderr))
+
+		// Handle plan mode: show what would be scanned and stop
+		if cfg.Plan {
+			log.Progress("Resolving repositories...")
+			fetchPlan, err := fetcher.Plan(cfg)
+			if err != nil {
+				exitWithError(cfg, log, "Failed to resolve query plan: %v", err)
 			}
-			os.Exit(1)
+			log.Output("%s", formatPlan(cfg, fetchPlan))
+			return
 		}
 
 		// Fetch PRs
 		log.Progress("Fetching pull requests...")
-		prs, err := service.Fetch(cfg, ghClient)
-		if err != nil {
-			log.Error("Failed to fetch PRs: %v", err)
-			if cfg.CI {
-				os.Exit(1)
+		prs, err := fetcher.Fetch(cfg)
+		if errors.Is(err, service.ErrScanDeclined) {
+			exitWithError(cfg, log, "Aborted")
+		}
+		if err != nil && ctx.Err() != nil {
+			log.Error("Interrupted, writing partial report with %d pull request(s)", len(prs))
+			if err := writePartialReport(cfg, log, prs, fetcher); err != nil {
+				log.Error("Failed to write partial report: %v", err)
 			}
-			os.Exit(1)
+			notifyRunError(cfg, log, fmt.Errorf("interrupted after fetching %d pull request(s): %w", len(prs), err))
+			os.Exit(exitInterrupted)
+		}
+		// Later interrupts terminate immediately
+		stopSignals()
+		if err != nil {
+			exitWithError(cfg, log, "Failed to fetch PRs: %v", err)
 		}
 
 		log.Info("Fetched %d pull requests", len(prs))
 
+		// Anonymize before anything is rendered or sent to the LLM
+		if cfg.Anonymize {
+			service.Anonymize(prs, cfg.StripURLs)
+		}
+
+		// Estimate the LLM usage instead of generating the report
+		if cfg.Estimate {
+			log.Output("%s", formatEstimate(cfg, estimateLLMUsage(cfg, prs)))
+			return
+		}
+
 		// Handle dry-run mode
 		if cfg.DryRun {
 			log.Output("%s", render.RenderTable(prs))
@@ -187,42 +439,88 @@ func init() {
 		}
 
 		// Generate metadata
-		metadata := generateMetadata(cfg, prs)
+		metadata := generateMetadata(cfg, prs, fetcher.ScannedRepos())
+		metadata.SinceTime, metadata.UntilTime = fetcher.Window()
 
-		// Generate LLM summary if not in dry-run mode
-		if !cfg.DryRun {
-			llmClient := createLLMClient(cfg)
+		// Generate LLM summary if not in dry-run mode. Badges only show the
+		// count and JSON lines carry no metadata.
+		if !cfg.DryRun && cfg.Format != "badge" && cfg.Format != "jsonl" {
+			llmClient := withSummaryCache(cfg, log, createLLMClient(cfg, log))
 			if llmClient != nil {
 				log.Progress("Generating AI summary...")
 
-				context := llm.BuildContext(prs)
-				summary, err := llmClient.Summarise(context)
+				summary, err := generateSummary(cfg, log, llmClient, prs)
 				if err != nil {
 					log.Info("Warning: Failed to generate AI summary: %v", err)
 					// Continue without summary rather than failing completely
 				} else {
+					if cfg.SummaryStyle == llm.SummaryStyleBullets {
+						summary = llm.FormatBullets(summary)
+					}
 					metadata.Summary = summary
 					log.Info("AI summary generated successfully")
 				}
+
+				if cfg.PerAuthorSummary {
+					log.Progress("Generating per-author summaries...")
+					metadata.AuthorSummaries, err = generateAuthorSummaries(cfg, log, llmClient, prs)
+					if err != nil {
+						log.Info("Warning: Failed to generate per-author summaries: %v", err)
+					}
+				}
 			}
 		}
 
-		// Render markdown
-		log.Progress("Rendering markdown...")
-		markdownOutput := render.Render(metadata, prs)
+		// Render report
+		log.Progress("Rendering report...")
+		reportOutput, err := renderReport(cfg, metadata, prs)
+		if err != nil {
+			exitWithError(cfg, log, "Failed to render report: %v", err)
+		}
+
+		// Compare with the committed report instead of writing it
+		if cfg.CheckOnly != "" {
+			diff, err := checkReport(cfg.CheckOnly, reportOutput)
+			if err != nil {
+				exitWithError(cfg, log, "Failed to check report: %v", err)
+			}
+			if diff != "" {
+				exitWithError(cfg, log, "Report %s is out of date:\n%s", cfg.CheckOnly, diff)
+			}
+			log.Info("Report is up to date: %s", cfg.CheckOnly)
+			return
+		}
 
 		// Output to file or stdout
-		if cfg.Output != "" {
-			if err := writeToFile(cfg.Output, markdownOutput); err != nil {
-				log.Error("Failed to write output file: %v", err)
-				if cfg.CI {
-					os.Exit(1)
-				}
-				os.Exit(1)
+		if err := writeOutput(cfg, log, reportOutput, prs); err != nil {
+			exitWithError(cfg, log, "Failed to write output file: %v", err)
+		}
+
+		// Write label-routed subsets alongside the main output
+		routed, err := writeRoutes(cfg, log, metadata, prs, fetcher.ScannedRepos())
+		if err != nil {
+			exitWithError(cfg, log, "Failed to write routed output: %v", err)
+		}
+
+		if cfg.Manifest != "" {
+			manifest := Manifest{ReportID: metadata.ReportID, GeneratedAt: metadata.GeneratedAt}
+			if cfg.Output != "" {
+				manifest.Artifacts = append(manifest.Artifacts, outputArtifact(cfg, cfg.Output, reportOutput))
+			}
+			manifest.Artifacts = append(manifest.Artifacts, routed...)
+			if err := writeManifest(cfg.Manifest, manifest); err != nil {
+				exitWithError(cfg, log, "Failed to write manifest: %v", err)
+			}
+			log.Info("Manifest written to: %s", cfg.Manifest)
+		}
+
+		// Copy to clipboard for quick sharing
+		if cfg.Clipboard && !cfg.CI {
+			if err := clipboard.New().Copy(reportOutput); err != nil {
+				log.Error("Failed to copy report to clipboard: %v", err)
+			} else {
+				log.Info("Report copied to clipboard")
 			}
-			log.Info("Output written to: %s", cfg.Output)
-		} else {
-			log.Output("%s", markdownOutput)
 		}
 
 		if cfg.CI {
@@ -234,19 +532,34 @@ func init() {
 
 // GetConfig loads and merges configuration from all sources
 func GetConfig() (*config.Config, error) {
+	cliConfig, envConfig, yamlConfig, err := loadConfigLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge with precedence: CLI > env > YAML
+	merged := config.MergeConfig(cliConfig, envConfig, yamlConfig)
+	merged.LLMProvider = strings.ToLower(strings.TrimSpace(merged.LLMProvider))
+
+	return merged, nil
+}
+
+// loadConfigLayers loads the CLI, environment and YAML configuration before
+// they are merged
+func loadConfigLayers() (cliConfig, envConfig, yamlConfig *config.Config, err error) {
 	// Load from YAML file
 	configPath := cfgFile
 	if configPath == "" {
 		configPath = "~/.prtool.yaml"
 	}
 
-	yamlConfig, err := config.LoadFromFile(configPath)
+	yamlConfig, err = config.LoadFromFile(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load config file: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to load config file: %w", err)
 	}
 
 	// Load from environment
-	envConfig := config.LoadFromEnv()
+	envConfig = config.LoadFromEnv()
 
 	// Parse teams from comma-separated string
 	var teams []string
@@ -255,46 +568,429 @@ func GetConfig() (*config.Config, error) {
 	}
 
 	// Create CLI config from flags
-	cliConfig := &config.Config{
-		GitHubToken: githubToken,
-		Org:         org,
-		Team:        teams,
-		User:        user,
-		Repo:        repo,
-		Since:       since,
-		LLMProvider: llmProvider,
-		LLMAPIKey:   llmAPIKey,
-		LLMModel:    llmModel,
-		Prompt:      prompt,
-		Output:      output,
-		DryRun:      dryRun,
-		Verbose:     verbose,
-		CI:          ci,
-		LogFile:     logFile,
+	cliConfig = &config.Config{
+		GitHubToken:            githubToken,
+		UserAgent:              userAgent,
+		GitHubHeaders:          githubHeaders,
+		FixtureDump:            fixtureDump,
+		GitHubTimeout:          githubTimeout,
+		ConfirmThreshold:       confirmThreshold,
+		Org:                    org,
+		Team:                   teams,
+		User:                   user,
+		Repo:                   repo,
+		Topic:                  topic,
+		RepoFilterExpr:         repoFilterExpr,
+		ExtraRepos:             extraRepos,
+		RepoLimit:              repoLimit,
+		Concurrency:            concurrency,
+		ScanOrder:              scanOrder,
+		RepoCacheFile:          repoCacheFile,
+		RepoCacheTTL:           repoCacheTTL,
+		RefreshRepos:           refreshRepos,
+		Since:                  since,
+		Until:                  until,
+		DateField:              dateField,
+		LargeWindowDays:        largeWindowDays,
+		AllowLargeWindow:       allowLargeWindow,
+		StripPRTemplate:        stripPRTemplate,
+		IncludeDrafts:          !excludeDrafts,
+		IncludeFiles:           includeFiles,
+		Paths:                  paths,
+		ExcludePaths:           excludePaths,
+		MinChanges:             minChanges,
+		LabelsAny:              labelsAny,
+		LabelsAll:              labelsAll,
+		ExcludeLabels:          excludeLabels,
+		IncludeCommits:         includeCommits,
+		AuthorDomains:          authorDomains,
+		Grep:                   grepTerms,
+		GrepRegex:              grepRegex,
+		LLMProvider:            llmProvider,
+		LLMAPIKey:              llmAPIKey,
+		LLMModel:               llmModel,
+		SummaryModel:           summaryModel,
+		Prompt:                 prompt,
+		SummaryStyle:           summaryStyle,
+		MaxLLMTokens:           maxLLMTokens,
+		LLMChunkTokens:         llmChunkTokens,
+		MinSummaryChars:        minSummaryChars,
+		LenientChunks:          lenientChunks,
+		Estimate:               estimate,
+		LLMTemperature:         llmTemperature,
+		LLMTopP:                llmTopP,
+		LLMMaxTokens:           llmMaxTokens,
+		LLMPricePer1K:          llmPricePer1K,
+		LLMConcurrency:         llmConcurrency,
+		Output:                 output,
+		OutputStdoutFormat:     outputStdoutFormat,
+		Force:                  force,
+		ReportID:               reportID,
+		NoSummaryCache:         noSummaryCache,
+		Append:                 appendOutput,
+		MaxOutputSize:          maxOutputSize,
+		OutputMode:             outputMode,
+		LineEnding:             lineEnding,
+		Manifest:               manifestPath,
+		NotifyOnError:          notifyOnError,
+		CheckOnly:              checkOnly,
+		Clipboard:              copyToClipboard,
+		Format:                 format,
+		JSONCompact:            jsonCompact,
+		TemplateDir:            templateDir,
+		Template:               templateName,
+		BadgeLabel:             badgeLabel,
+		BadgeYellowAt:          badgeYellowAt,
+		BadgeGreenAt:           badgeGreenAt,
+		GroupBy:                groupBy,
+		MinLabelCount:          minLabelCount,
+		RepoOrder:              repoOrder,
+		RepoDescriptions:       repoDescriptions,
+		HeadingOffset:          headingOffset,
+		NoDetailsBody:          noDetailsBody,
+		ShowEmptySections:      !pruneEmptySections,
+		LinkStyle:              linkStyle,
+		StatusIcons:            statusIcons,
+		DetailsFormat:          detailsFormat,
+		DedupeBy:               dedupeBy,
+		StrictProvider:         strictProvider,
+		Routes:                 routes,
+		ContextFields:          contextFields,
+		WeightBy:               weightBy,
+		PerAuthorSummary:       perAuthorSummary,
+		Anonymize:              anonymize,
+		StripURLs:              stripURLs,
+		IncludeReposWithoutPRs: !excludeReposNoPRs,
+		MergeTarget:            mergeTarget,
+		FacetTopics:            facetTopics,
+		DryRun:                 dryRun,
+		Plan:                   plan,
+		Verbose:                verbose,
+		CI:                     ci,
+		LogFile:                logFile,
 	}
 
-	// Merge with precedence: CLI > env > YAML
-	merged := config.MergeConfig(cliConfig, envConfig, yamlConfig)
+	return cliConfig, envConfig, yamlConfig, nil
+}
 
-	return merged, nil
+// formatEffectiveConfig renders the merged configuration for --print-config
+func formatEffectiveConfig() (string, error) {
+	cliConfig, envConfig, yamlConfig, err := loadConfigLayers()
+	if err != nil {
+		return "", err
+	}
+	merged := config.MergeConfig(cliConfig, envConfig, yamlConfig)
+	return config.FormatEffective(cliConfig, envConfig, yamlConfig, merged)
 }
 
 // validateConfig validates the configuration
 func validateConfig(cfg *config.Config) error {
-	if cfg.GitHubToken == "" {
+	if cfg.GitHubToken == "" && needsDefaultToken(cfg) {
 		return fmt.Errorf("GitHub token is required")
 	}
 
+	if cfg.Plan && len(cfg.Hosts) > 0 {
+		return fmt.Errorf("--plan does not support host profiles")
+	}
+
 	// Validate scope using the scope package
 	if err := scope.ValidateScope(cfg); err != nil {
 		return err
 	}
 
+	// Unknown providers fall back to the stub with a warning, except in CI
+	// or strict mode where a typo must not silently skip the summary
+	if (cfg.CI || cfg.StrictProvider) && !llm.IsValidProvider(cfg.LLMProvider) {
+		return fmt.Errorf("unknown LLM provider '%s' (supported: %s)", cfg.LLMProvider, strings.Join(llm.Providers, ", "))
+	}
+
+	if err := llm.ValidateContextFields(cfg.ContextFields); err != nil {
+		return err
+	}
+
+	if cfg.StripURLs && !cfg.Anonymize {
+		return fmt.Errorf("--strip-urls requires --anonymize")
+	}
+
+	if cfg.PerAuthorSummary && cfg.GroupBy != "" && cfg.GroupBy != render.GroupByNone && cfg.GroupBy != render.GroupByAuthor {
+		return fmt.Errorf("--per-author-summary groups by author and cannot be combined with --group-by %s", cfg.GroupBy)
+	}
+
+	if !llm.IsValidWeightBy(cfg.WeightBy) {
+		return fmt.Errorf("invalid weight-by '%s' (supported: %s)", cfg.WeightBy, strings.Join(llm.WeightByValues, ", "))
+	}
+
+	switch cfg.SummaryStyle {
+	case "", llm.SummaryStyleProse, llm.SummaryStyleBullets:
+	default:
+		return fmt.Errorf("invalid summary style '%s' (supported: prose, bullets)", cfg.SummaryStyle)
+	}
+
+	if (len(cfg.Paths) > 0 || len(cfg.ExcludePaths) > 0) && !cfg.IncludeFiles {
+		return fmt.Errorf("--path and --exclude-path require --include-files")
+	}
+	if err := service.ValidatePathPatterns(append(append([]string(nil), cfg.Paths...), cfg.ExcludePaths...)); err != nil {
+		return err
+	}
+	if err := service.ValidateGrepPatterns(cfg.GrepRegex); err != nil {
+		return err
+	}
+	if len(cfg.AuthorDomains) > 0 && !cfg.IncludeCommits {
+		return fmt.Errorf("--author-domain requires --include-commits")
+	}
+
+	if cfg.LLMTemperature != "" {
+		if t, err := strconv.ParseFloat(cfg.LLMTemperature, 32); err != nil || t < 0 || t > 2 {
+			return fmt.Errorf("invalid llm-temperature '%s' (expected a number from 0 to 2)", cfg.LLMTemperature)
+		}
+	}
+	if cfg.LLMTopP != "" {
+		if p, err := strconv.ParseFloat(cfg.LLMTopP, 32); err != nil || p <= 0 || p > 1 {
+			return fmt.Errorf("invalid llm-top-p '%s' (expected a number above 0 and at most 1)", cfg.LLMTopP)
+		}
+	}
+	if cfg.LLMMaxTokens < 0 {
+		return fmt.Errorf("LLM max tokens must not be negative, got %d", cfg.LLMMaxTokens)
+	}
+	if cfg.LLMPricePer1K != "" {
+		if price, err := strconv.ParseFloat(cfg.LLMPricePer1K, 64); err != nil || price < 0 {
+			return fmt.Errorf("invalid llm-price-per-1k '%s' (expected a non-negative number such as 0.0025)", cfg.LLMPricePer1K)
+		}
+	}
+
+	if cfg.MaxLLMTokens < 0 {
+		return fmt.Errorf("max LLM tokens must not be negative, got %d", cfg.MaxLLMTokens)
+	}
+
+	if cfg.MinSummaryChars < 0 {
+		return fmt.Errorf("minimum summary characters must not be negative, got %d", cfg.MinSummaryChars)
+	}
+
+	if cfg.LLMChunkTokens < 0 {
+		return fmt.Errorf("LLM chunk tokens must not be negative, got %d", cfg.LLMChunkTokens)
+	}
+
+	if cfg.ConfirmThreshold < 0 {
+		return fmt.Errorf("confirm threshold must not be negative, got %d", cfg.ConfirmThreshold)
+	}
+
+	if cfg.MinChanges < 0 {
+		return fmt.Errorf("min changes must not be negative, got %d", cfg.MinChanges)
+	}
+
+	if cfg.Concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative, got %d", cfg.Concurrency)
+	}
+
+	if cfg.RepoLimit < 0 {
+		return fmt.Errorf("repo limit must not be negative, got %d", cfg.RepoLimit)
+	}
+	if cfg.RepoCacheTTL != "" {
+		if ttl, err := time.ParseDuration(cfg.RepoCacheTTL); err != nil || ttl < 0 {
+			return fmt.Errorf("invalid repo-cache-ttl '%s' (expected a duration such as 12h)", cfg.RepoCacheTTL)
+		}
+	}
+	if !scope.IsValidScanOrder(cfg.ScanOrder) {
+		return fmt.Errorf("invalid scan-order '%s' (supported: %s)", cfg.ScanOrder, strings.Join(scope.ScanOrderValues, ", "))
+	}
+
+	if cfg.HeadingOffset < 0 {
+		return fmt.Errorf("heading offset must not be negative, got %d", cfg.HeadingOffset)
+	}
+
+	if cfg.MinLabelCount < 0 {
+		return fmt.Errorf("min label count must not be negative, got %d", cfg.MinLabelCount)
+	}
+
+	if cfg.LLMConcurrency < 0 {
+		return fmt.Errorf("LLM concurrency must not be negative, got %d", cfg.LLMConcurrency)
+	}
+
+	for _, header := range cfg.GitHubHeaders {
+		if _, _, err := parseHeader(header); err != nil {
+			return err
+		}
+	}
+
+	if cfg.GitHubTimeout != "" {
+		if timeout, err := time.ParseDuration(cfg.GitHubTimeout); err != nil || timeout < 0 {
+			return fmt.Errorf("invalid github-timeout '%s' (expected a duration such as 30s or 2m)", cfg.GitHubTimeout)
+		}
+	}
+
+	for _, extra := range cfg.ExtraRepos {
+		parts := strings.Split(extra, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("extra repository '%s' must be in format 'owner/repo'", extra)
+		}
+	}
+
+	if cfg.Template != "" {
+		if cfg.Format != "" && cfg.Format != "markdown" {
+			return fmt.Errorf("template cannot be combined with format '%s'", cfg.Format)
+		}
+		// Fail before fetching rather than after the LLM call
+		if _, err := loadNamedTemplate(cfg.TemplateDir, cfg.Template); err != nil {
+			return err
+		}
+	}
+
+	if !render.IsValidGroupBy(cfg.GroupBy) {
+		return fmt.Errorf("invalid group-by '%s' (supported: %s)", cfg.GroupBy, strings.Join(render.GroupByValues, ", "))
+	}
+
+	if cfg.RepoFilterExpr != "" {
+		if _, err := scope.ParseRepoFilter(cfg.RepoFilterExpr); err != nil {
+			return err
+		}
+	}
+
+	if err := service.ValidateWindow(cfg); err != nil {
+		return err
+	}
+
+	if !service.IsValidDateField(cfg.DateField) {
+		return fmt.Errorf("invalid date-field '%s' (supported: %s)", cfg.DateField, strings.Join(service.DateFields, ", "))
+	}
+
+	if !render.IsValidLinkStyle(cfg.LinkStyle) {
+		return fmt.Errorf("invalid link-style '%s' (supported: %s, %s)", cfg.LinkStyle, render.LinkStyleInline, render.LinkStyleReference)
+	}
+
+	if err := render.ValidateDetailsFormat(cfg.DetailsFormat); err != nil {
+		return err
+	}
+
+	if !render.IsValidDedupeBy(cfg.DedupeBy) {
+		return fmt.Errorf("invalid dedupe-by '%s' (supported: %s)", cfg.DedupeBy, render.DedupeByTitle)
+	}
+
+	if !render.IsValidRepoOrder(cfg.RepoOrder) {
+		return fmt.Errorf("invalid repo-order '%s' (supported: %s)", cfg.RepoOrder, strings.Join(render.RepoOrderValues, ", "))
+	}
+
+	if cfg.MergeTarget && cfg.FacetTopics {
+		return fmt.Errorf("merge-target and facet-topics cannot be combined")
+	}
+	if (cfg.MergeTarget || cfg.FacetTopics) && cfg.GroupBy != "" && cfg.GroupBy != render.GroupByNone {
+		return fmt.Errorf("group-by cannot be combined with merge-target or facet-topics")
+	}
+
+	switch cfg.Format {
+	case "", "markdown", "json", "jsonl", "badge":
+	default:
+		return fmt.Errorf("invalid format '%s' (supported: markdown, json, jsonl, badge)", cfg.Format)
+	}
+
+	switch cfg.OutputStdoutFormat {
+	case "", "none", "table", "markdown":
+	default:
+		return fmt.Errorf("invalid output stdout format '%s' (supported: none, table, markdown)", cfg.OutputStdoutFormat)
+	}
+
+	for _, rule := range cfg.Routes {
+		if _, err := parseRoute(rule); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Append && (cfg.Output == "" || storage.IsRemote(cfg.Output)) {
+		return fmt.Errorf("--append requires a local --output file")
+	}
+	if cfg.NotifyOnError != "" && !isValidNotifyURL(cfg.NotifyOnError) {
+		return fmt.Errorf("invalid notify-on-error URL '%s' (expected an http or https URL)", cfg.NotifyOnError)
+	}
+	if cfg.CheckOnly != "" && cfg.Append {
+		return fmt.Errorf("--check-only cannot be combined with --append")
+	}
+	if cfg.MaxOutputSize != "" {
+		if !cfg.Append {
+			return fmt.Errorf("--max-output-size requires --append")
+		}
+		if _, err := parseSize(cfg.MaxOutputSize); err != nil {
+			return err
+		}
+	}
+	if _, err := parseFileMode(cfg.OutputMode); err != nil {
+		return err
+	}
+
+	switch cfg.LineEnding {
+	case "", lineEndingLF, lineEndingCRLF:
+	default:
+		return fmt.Errorf("invalid line ending '%s' (supported: %s, %s)", cfg.LineEnding, lineEndingLF, lineEndingCRLF)
+	}
+
 	return nil
 }
 
-// generateMetadata creates metadata for the report
-func generateMetadata(cfg *config.Config, prs []*model.PR) render.Metadata {
+// defaultLargeWindowDays is the since window size that triggers a warning
+// when large_window_days is not configured
+const defaultLargeWindowDays = 730
+
+// defaultConfirmThreshold is the repository count above which interactive
+// runs ask for confirmation when confirm_threshold is not configured
+const defaultConfirmThreshold = 100
+
+// confirmScan returns the Fetcher confirmation callback. Interactive runs
+// that resolve more repositories than the threshold are asked on out whether
+// to continue, reading the answer from in. CI runs never prompt.
+func confirmScan(cfg *config.Config, in io.Reader, out io.Writer) func(int) bool {
+	return func(repoCount int) bool {
+		threshold := cfg.ConfirmThreshold
+		if threshold == 0 {
+			threshold = defaultConfirmThreshold
+		}
+		if cfg.CI || repoCount <= threshold {
+			return true
+		}
+
+		fmt.Fprintf(out, "About to scan %d repos, continue? [y/N] ", repoCount)
+		answer, _ := bufio.NewReader(in).ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// checkSinceWindow warns when the since window is larger than the configured
+// threshold. In CI mode a large window is an error unless AllowLargeWindow is set.
+func checkSinceWindow(cfg *config.Config, log *logger.Logger) error {
+	if cfg.Since == "" || cfg.Since == service.SinceLatestRelease {
+		return nil
+	}
+
+	sinceTime, err := timeutil.ParseTime(cfg.Since)
+	if err != nil {
+		// validated again when fetching
+		return nil
+	}
+
+	threshold := cfg.LargeWindowDays
+	if threshold <= 0 {
+		threshold = defaultLargeWindowDays
+	}
+
+	days := int(time.Since(sinceTime).Hours() / 24)
+	if days <= threshold {
+		return nil
+	}
+
+	if cfg.CI && !cfg.AllowLargeWindow {
+		return fmt.Errorf("since window '%s' spans %d days, more than %d (use --allow-large-window to proceed)", cfg.Since, days, threshold)
+	}
+
+	log.Warn("since window '%s' spans %d days, more than %d; this may be a slow scan", cfg.Since, days, threshold)
+	return nil
+}
+
+// generateMetadata creates metadata for the report. scanned lists every
+// repository that was scanned; it is recorded when cfg includes repositories
+// without PRs.
+func generateMetadata(cfg *config.Config, prs []*model.PR, scanned []string) render.Metadata {
 	// Determine scope type and value
 	var scopeType, scopeValue string
 	if cfg.Org != "" {
@@ -312,12 +1008,33 @@ func generateMetadata(cfg *config.Config, prs []*model.PR) render.Metadata {
 		scopeType, scopeValue = "repository", cfg.Repo
 	}
 
-	// Collect unique repositories
+	// Host profiles add their orgs and repos, e.g. "ghe: corp, corp/tools"
+	if len(cfg.Hosts) > 0 {
+		values := []string{}
+		if scopeValue != "" {
+			values = append(values, scopeValue)
+		}
+		for _, host := range cfg.Hosts {
+			values = append(values, fmt.Sprintf("%s: %s", host.Name, strings.Join(append(append([]string(nil), host.Orgs...), host.Repos...), ", ")))
+		}
+		if scopeType == "" {
+			scopeType = "hosts"
+		} else {
+			scopeType += " and hosts"
+		}
+		scopeValue = strings.Join(values, "; ")
+	}
+
+	// Collect unique repositories and contributors
 	repoSet := make(map[string]bool)
+	authorSet := make(map[string]bool)
 	for _, pr := range prs {
 		if pr.Repository != "" {
 			repoSet[pr.Repository] = true
 		}
+		if pr.Author != "" {
+			authorSet[pr.Author] = true
+		}
 	}
 
 	var repositories []string
@@ -331,21 +1048,349 @@ func generateMetadata(cfg *config.Config, prs []*model.PR) render.Metadata {
 		since = "-7d" // default
 	}
 
+	var scannedRepos []string
+	// Empty repo groups are built from the scanned repositories
+	if (cfg.IncludeReposWithoutPRs || cfg.ShowEmptySections) && len(scanned) > 0 {
+		scannedRepos = append([]string(nil), scanned...)
+		sort.Strings(scannedRepos)
+	}
+
+	reportID := cfg.ReportID
+	if reportID == "" {
+		reportID = newReportID()
+	}
+
 	return render.Metadata{
-		GeneratedAt:  time.Now().UTC(),
-		Scope:        scopeType,
-		ScopeValue:   scopeValue,
-		Since:        since,
-		TotalPRs:     len(prs),
-		Repositories: repositories,
-		LLMProvider:  cfg.LLMProvider,
-		LLMModel:     cfg.LLMModel,
-		Summary:      "", // Will be filled by LLM in later iterations
+		ReportID:          reportID,
+		GeneratedAt:       time.Now().UTC(),
+		Scope:             scopeType,
+		ScopeValue:        scopeValue,
+		Since:             since,
+		Until:             cfg.Until,
+		Filters:           describeFilters(cfg),
+		TotalPRs:          len(prs),
+		TotalContributors: len(authorSet),
+		TotalRepositories: len(repositories),
+		Repositories:      repositories,
+		LLMProvider:       cfg.LLMProvider,
+		LLMModel:          cfg.LLMModel,
+		Summary:           "", // Will be filled by LLM in later iterations
+
+		ScannedRepositories: scannedRepos,
+	}
+}
+
+// newReportID returns a random (version 4) UUID identifying a report
+func newReportID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// Fall back to a timestamp, which is unique enough for a single run
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// describeFilters lists the effective filters applied to the report, for auditability
+func describeFilters(cfg *config.Config) []string {
+	since := cfg.Since
+	if since == "" {
+		since = "-7d" // default
+	}
+
+	filters := []string{
+		fmt.Sprintf("since=%s", since),
+		"state=merged",
+	}
+
+	if cfg.Until != "" {
+		filters = append(filters, fmt.Sprintf("until=%s", cfg.Until))
+	}
+
+	if cfg.DateField != "" && cfg.DateField != service.DateFieldMerged {
+		filters = append(filters, fmt.Sprintf("date-field=%s", cfg.DateField))
+	}
+
+	if cfg.Topic != "" {
+		filters = append(filters, fmt.Sprintf("topic=%s", cfg.Topic))
+	}
+
+	if cfg.RepoFilterExpr != "" {
+		filters = append(filters, fmt.Sprintf("repos=%s", cfg.RepoFilterExpr))
 	}
+
+	if cfg.RepoLimit > 0 {
+		if cfg.ScanOrder != "" {
+			filters = append(filters, fmt.Sprintf("repo-limit=%d (by %s)", cfg.RepoLimit, cfg.ScanOrder))
+		} else {
+			filters = append(filters, fmt.Sprintf("repo-limit=%d", cfg.RepoLimit))
+		}
+	}
+
+	if cfg.IncludeDrafts {
+		filters = append(filters, "include-drafts")
+	}
+	for _, pattern := range cfg.Paths {
+		filters = append(filters, fmt.Sprintf("path=%s", pattern))
+	}
+	for _, pattern := range cfg.ExcludePaths {
+		filters = append(filters, fmt.Sprintf("exclude-path=%s", pattern))
+	}
+	if cfg.MinChanges > 0 {
+		filters = append(filters, fmt.Sprintf("min-changes=%d", cfg.MinChanges))
+	}
+	if len(cfg.LabelsAny) > 0 {
+		filters = append(filters, fmt.Sprintf("labels-any=%s", strings.Join(cfg.LabelsAny, ",")))
+	}
+	if len(cfg.LabelsAll) > 0 {
+		filters = append(filters, fmt.Sprintf("labels-all=%s", strings.Join(cfg.LabelsAll, ",")))
+	}
+	for _, label := range cfg.ExcludeLabels {
+		filters = append(filters, fmt.Sprintf("exclude-label=%s", label))
+	}
+	for _, domain := range cfg.AuthorDomains {
+		filters = append(filters, fmt.Sprintf("author-domain=%s", domain))
+	}
+	for _, term := range cfg.Grep {
+		filters = append(filters, fmt.Sprintf("grep=%s", term))
+	}
+	for _, pattern := range cfg.GrepRegex {
+		filters = append(filters, fmt.Sprintf("grep-regex=%s", pattern))
+	}
+	if cfg.StripPRTemplate {
+		filters = append(filters, "strip-pr-template")
+	}
+
+	return filters
 }
 
-// writeToFile writes content to a file
-func writeToFile(filename, content string) error {
+// formatPlan renders a query plan for --plan
+func formatPlan(cfg *config.Config, plan *service.Plan) string {
+	var sb strings.Builder
+
+	sb.WriteString("Query Plan:\n\n")
+	for _, filter := range describeFilters(cfg) {
+		sb.WriteString(fmt.Sprintf("- %s\n", filter))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nRepositories (%d):\n\n", len(plan.Repos)))
+	for _, repo := range plan.Repos {
+		sb.WriteString(fmt.Sprintf("- %s (since %s)\n", repo.Name, repo.Since.Format("2006-01-02 15:04")))
+	}
+
+	return sb.String()
+}
+
+// loadNamedTemplate reads <dir>/<name>.tmpl
+func loadNamedTemplate(dir, name string) (string, error) {
+	if dir == "" {
+		return "", fmt.Errorf("template '%s' requires --template-dir", name)
+	}
+	if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid template name '%s'", name)
+	}
+
+	path := filepath.Join(dir, name+".tmpl")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("template '%s' not found in %s (expected %s)", name, dir, path)
+		}
+		return "", fmt.Errorf("failed to read template %s: %w", path, err)
+	}
+
+	return string(data), nil
+}
+
+// exitInterrupted is the exit code used when a run is interrupted by a signal
+const exitInterrupted = 130
+
+// writePartialReport renders and writes a report for the PRs fetched before
+// the run was interrupted. The LLM summary is skipped.
+func writePartialReport(cfg *config.Config, log *logger.Logger, prs []*model.PR, fetcher *service.Fetcher) error {
+	if cfg.Anonymize {
+		service.Anonymize(prs, cfg.StripURLs)
+	}
+
+	metadata := generateMetadata(cfg, prs, fetcher.ScannedRepos())
+	metadata.SinceTime, metadata.UntilTime = fetcher.Window()
+	metadata.Partial = true
+
+	reportOutput, err := renderReport(cfg, metadata, prs)
+	if err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	return writeOutput(cfg, log, reportOutput, prs)
+}
+
+// renderReport renders the report in the configured format
+func renderReport(cfg *config.Config, metadata render.Metadata, prs []*model.PR) (string, error) {
+	if cfg.Template != "" {
+		text, err := loadNamedTemplate(cfg.TemplateDir, cfg.Template)
+		if err != nil {
+			return "", err
+		}
+		return render.RenderTemplate(cfg.Template, text, metadata, prs)
+	}
+
+	switch cfg.Format {
+	case "", "markdown":
+		return render.RenderWithOptions(metadata, prs, renderOptions(cfg)), nil
+	case "json":
+		return render.RenderJSONWithOptions(metadata, prs, renderOptions(cfg))
+	case "jsonl":
+		return render.RenderJSONLines(prs)
+	case "badge":
+		return render.RenderBadge(metadata, renderOptions(cfg))
+	default:
+		return "", fmt.Errorf("unsupported format '%s'", cfg.Format)
+	}
+}
+
+// renderOptions maps the configuration onto Markdown rendering options
+func renderOptions(cfg *config.Config) render.Options {
+	opts := render.Options{GroupBy: cfg.GroupBy}
+	if cfg.PerAuthorSummary {
+		opts.GroupBy = render.GroupByAuthor
+	}
+	if cfg.MergeTarget {
+		opts.GroupBy = render.GroupByBase
+	}
+	if cfg.FacetTopics {
+		opts.GroupBy = render.GroupByTopic
+	}
+	opts.CompactJSON = cfg.JSONCompact
+	opts.BadgeLabel = cfg.BadgeLabel
+	opts.BadgeYellowAt = cfg.BadgeYellowAt
+	opts.BadgeGreenAt = cfg.BadgeGreenAt
+	opts.MinLabelCount = cfg.MinLabelCount
+	opts.RepoOrder = cfg.RepoOrder
+	opts.RepoDescriptions = cfg.RepoDescriptions
+	opts.HeadingOffset = cfg.HeadingOffset
+	opts.NoDetailsBody = cfg.NoDetailsBody
+	opts.ShowEmptySections = cfg.ShowEmptySections
+	opts.LinkStyle = cfg.LinkStyle
+	opts.StatusIcons = cfg.StatusIcons
+	opts.DetailsFormat = cfg.DetailsFormat
+	opts.DedupeBy = cfg.DedupeBy
+	return opts
+}
+
+// writeOutput writes the rendered report to the output file, or to stdout when no file is set.
+// When writing to a file, a second view of the report can be printed to stdout
+// according to OutputStdoutFormat.
+func writeOutput(cfg *config.Config, log *logger.Logger, content string, prs []*model.PR) error {
+	content = applyLineEnding(content, cfg.LineEnding)
+
+	if cfg.Output == "" {
+		log.Output("%s", content)
+		return nil
+	}
+
+	mode, err := parseFileMode(cfg.OutputMode)
+	if err != nil {
+		return err
+	}
+
+	if storage.IsRemote(cfg.Output) {
+		if err := storage.Upload(context.Background(), cfg.Output, []byte(content)); err != nil {
+			return err
+		}
+		log.Info("Output uploaded to: %s", cfg.Output)
+	} else if isNamedPipe(cfg.Output) {
+		// Pipes are streamed to as is: reading them to compare, replacing
+		// them with a temp file or rotating them would break the reader
+		if err := writeToPipe(cfg.Output, content); err != nil {
+			return err
+		}
+		log.Info("Output written to pipe: %s", cfg.Output)
+	} else if cfg.Append {
+		// Validated by validateConfig
+		maxSize, _ := parseSize(cfg.MaxOutputSize)
+		rotated, err := appendToFile(cfg.Output, content, maxSize, appendSeparator(cfg), mode)
+		if err != nil {
+			return err
+		}
+		if rotated {
+			log.Info("Output exceeded %s, rotated %s", cfg.MaxOutputSize, cfg.Output)
+		}
+		log.Info("Output appended to: %s", cfg.Output)
+	} else if !cfg.Force && fileUnchanged(cfg.Output, content) {
+		log.Info("Output unchanged, not rewriting: %s", cfg.Output)
+	} else {
+		if err := writeToFile(cfg.Output, content, mode); err != nil {
+			return err
+		}
+		log.Info("Output written to: %s", cfg.Output)
+	}
+
+	switch cfg.OutputStdoutFormat {
+	case "table":
+		log.Output("%s", render.RenderTable(prs))
+	case "markdown":
+		log.Output("%s", content)
+	}
+
+	return nil
+}
+
+// Supported --line-ending values
+const (
+	lineEndingLF   = "lf"
+	lineEndingCRLF = "crlf"
+)
+
+// applyLineEnding converts the newlines of content to the given style. LF
+// (or an empty style) leaves content unchanged.
+func applyLineEnding(content, style string) string {
+	if style != lineEndingCRLF {
+		return content
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\n", "\r\n")
+}
+
+// fileUnchanged reports whether filename already holds content, ignoring the
+// lines --check-only ignores (the generation time, report ID and resolved
+// window), so a rerun over the same PRs leaves the file alone. A change of line
+// endings counts as a change. A missing or unreadable file counts as changed.
+func fileUnchanged(filename, content string) bool {
+	existing, err := os.ReadFile(filename)
+	if err != nil {
+		return false
+	}
+	if strings.Contains(string(existing), "\r\n") != strings.Contains(content, "\r\n") {
+		return false
+	}
+	return slices.Equal(reportLines(string(existing)), reportLines(content))
+}
+
+// isNamedPipe reports whether filename is an existing named pipe (FIFO)
+func isNamedPipe(filename string) bool {
+	info, err := os.Stat(filename)
+	return err == nil && info.Mode()&os.ModeNamedPipe != 0
+}
+
+// writeToPipe writes content to an existing named pipe, blocking until a
+// reader opens it
+func writeToPipe(filename, content string) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open pipe %s: %w", filename, err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write to pipe %s: %w", filename, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write to pipe %s: %w", filename, err)
+	}
+	return nil
+}
+
+// writeToFile writes content to a file with the given permission mode
+func writeToFile(filename, content string, mode os.FileMode) error {
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(filename)
 	if dir != "." {
@@ -354,32 +1399,265 @@ func writeToFile(filename, content string) error {
 		}
 	}
 
-	// Write file
-	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+	// Write to a temp file in the same directory and rename it into place, so
+	// readers never see a half-written report
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write file %s: %w", filename, err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write file %s: %w", filename, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write file %s: %w", filename, err)
+	}
+	if err := os.Chmod(tmpName, mode); err != nil {
+		os.Remove(tmpName)
+		return fmt.Errorf("failed to write file %s: %w", filename, err)
+	}
+	if err := os.Rename(tmpName, filename); err != nil {
+		os.Remove(tmpName)
 		return fmt.Errorf("failed to write file %s: %w", filename, err)
 	}
 
 	return nil
 }
 
+// appendSeparator returns what goes between appended reports: a blank line
+// for Markdown, nothing for line-oriented and JSON formats
+func appendSeparator(cfg *config.Config) string {
+	switch cfg.Format {
+	case "", "markdown":
+		return "\n"
+	default:
+		return ""
+	}
+}
+
+// appendToFile appends content to a file, separated from existing content by
+// sep. When maxSize is positive and the append would grow a non-empty file
+// past it, the existing files are rotated first (report.md becomes
+// report.1.md, report.1.md becomes report.2.md, ...) and content starts a
+// fresh file. It reports whether a rotation happened.
+func appendToFile(filename, content string, maxSize int64, sep string, mode os.FileMode) (bool, error) {
+	dir := filepath.Dir(filename)
+	if dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return false, fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	rotated := false
+	if info, err := os.Stat(filename); err == nil && info.Size() > 0 {
+		if maxSize > 0 && info.Size()+int64(len(sep)+len(content)) > maxSize {
+			if err := rotateFiles(filename); err != nil {
+				return false, err
+			}
+			rotated = true
+		} else {
+			content = sep + content
+		}
+	}
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_APPEND, mode)
+	if err != nil {
+		return rotated, fmt.Errorf("failed to append to file %s: %w", filename, err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return rotated, fmt.Errorf("failed to append to file %s: %w", filename, err)
+	}
+	if err := f.Close(); err != nil {
+		return rotated, fmt.Errorf("failed to append to file %s: %w", filename, err)
+	}
+
+	return rotated, nil
+}
+
+// rotatedName returns the name of the nth rotated copy of filename, keeping
+// its extension: report.md becomes report.1.md
+func rotatedName(filename string, n int) string {
+	ext := filepath.Ext(filename)
+	return fmt.Sprintf("%s.%d%s", strings.TrimSuffix(filename, ext), n, ext)
+}
+
+// rotateFiles shifts every rotated copy of filename up by one and moves
+// filename to the first slot
+func rotateFiles(filename string) error {
+	last := 0
+	for {
+		if _, err := os.Stat(rotatedName(filename, last+1)); err != nil {
+			break
+		}
+		last++
+	}
+
+	for n := last; n >= 1; n-- {
+		if err := os.Rename(rotatedName(filename, n), rotatedName(filename, n+1)); err != nil {
+			return fmt.Errorf("failed to rotate %s: %w", filename, err)
+		}
+	}
+	if err := os.Rename(filename, rotatedName(filename, 1)); err != nil {
+		return fmt.Errorf("failed to rotate %s: %w", filename, err)
+	}
+
+	return nil
+}
+
+// parseSize parses a byte size such as 500000, 512KB or 10MB (powers of
+// 1024). The empty string is 0, meaning no limit.
+func parseSize(size string) (int64, error) {
+	if size == "" {
+		return 0, nil
+	}
+
+	units := []struct {
+		suffix string
+		factor int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+
+	value, factor := strings.ToUpper(strings.TrimSpace(size)), int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(value, unit.suffix) {
+			value, factor = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix)), unit.factor
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size '%s' (expected e.g. 500000, 512KB or 10MB)", size)
+	}
+	return n * factor, nil
+}
+
+// defaultOutputMode is the permission mode of created output files
+const defaultOutputMode os.FileMode = 0644
+
+// parseFileMode parses an octal permission mode such as 0600 or 640. The
+// empty string is defaultOutputMode.
+func parseFileMode(mode string) (os.FileMode, error) {
+	if mode == "" {
+		return defaultOutputMode, nil
+	}
+
+	n, err := strconv.ParseUint(strings.TrimSpace(mode), 8, 32)
+	if err != nil || n > 0777 {
+		return 0, fmt.Errorf("invalid output mode '%s' (expected an octal mode such as 0600)", mode)
+	}
+	return os.FileMode(n), nil
+}
+
+// githubUserAgent returns the configured User-Agent, defaulting to prtool/<version>
+func githubUserAgent(cfg *config.Config) string {
+	if cfg.UserAgent != "" {
+		return cfg.UserAgent
+	}
+	return fmt.Sprintf("%s/%s", gh.DefaultUserAgent, version)
+}
+
+// parseHeader splits a "key=value" header option
+func parseHeader(header string) (string, string, error) {
+	key, value, ok := strings.Cut(header, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" || strings.ContainsAny(key, " \t:") {
+		return "", "", fmt.Errorf("invalid GitHub header '%s' (expected key=value)", header)
+	}
+	return key, strings.TrimSpace(value), nil
+}
+
+// needsDefaultToken reports whether github_token is used: by the configured
+// scope, or by a host profile without a token of its own
+func needsDefaultToken(cfg *config.Config) bool {
+	if len(cfg.Hosts) == 0 || len(cfg.Scopes()) > 0 {
+		return true
+	}
+	for _, host := range cfg.Hosts {
+		if host.Token == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// newHostClients connects to each configured host profile, keyed by host
+// name. Host tokens default to github_token.
+func newHostClients(cfg *config.Config, opts []gh.ClientOption) (map[string]gh.GitHubClient, error) {
+	clients := make(map[string]gh.GitHubClient, len(cfg.Hosts))
+	for _, host := range cfg.Hosts {
+		token := host.Token
+		if token == "" {
+			token = cfg.GitHubToken
+		}
+		hostOpts := append(append([]gh.ClientOption(nil), opts...), gh.WithBaseURL(host.BaseURL))
+		client, err := gh.NewRestClient(token, hostOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("host %s: %w", host.Name, err)
+		}
+		clients[host.Name] = client
+	}
+	return clients, nil
+}
+
+// githubClientOptions returns the GitHub client options for the configuration.
+// Headers are validated by validateConfig, so invalid ones are skipped here.
+func githubClientOptions(cfg *config.Config) []gh.ClientOption {
+	opts := []gh.ClientOption{gh.WithUserAgent(githubUserAgent(cfg))}
+	for _, header := range cfg.GitHubHeaders {
+		key, value, err := parseHeader(header)
+		if err != nil {
+			continue
+		}
+		opts = append(opts, gh.WithHeader(key, value))
+	}
+	if cfg.FixtureDump != "" {
+		opts = append(opts, gh.WithFixtureDump(cfg.FixtureDump))
+	}
+	if timeout, err := time.ParseDuration(cfg.GitHubTimeout); err == nil {
+		opts = append(opts, gh.WithTimeout(timeout))
+	}
+	return opts
+}
+
+// repoCacheTTLOrDefault returns the configured --repo-cache-ttl, or
+// gh.DefaultRepoCacheTTL when none is set
+func repoCacheTTLOrDefault(cfg *config.Config) time.Duration {
+	// Validated by validateConfig
+	if ttl, err := time.ParseDuration(cfg.RepoCacheTTL); err == nil {
+		return ttl
+	}
+	return gh.DefaultRepoCacheTTL
+}
+
 // createLLMClient creates an LLM client based on configuration
-func createLLMClient(cfg *config.Config) llm.LLM {
+func createLLMClient(cfg *config.Config, log *logger.Logger) llm.LLM {
 	if cfg.LLMProvider == "" {
 		// Default to stub for testing
 		return llm.NewStubLLM()
 	}
 
 	switch cfg.LLMProvider {
-	case "stub":
+	case llm.ProviderStub:
 		return llm.NewStubLLM()
-	case "openai":
+	case llm.ProviderOpenAI:
 		if cfg.LLMAPIKey == "" {
 			fmt.Fprintf(os.Stderr, "Warning: OpenAI API key not provided, falling back to stub\n")
 			return llm.NewStubLLM()
 		}
-		return llm.NewOpenAILLM(cfg.LLMAPIKey, cfg.LLMModel)
-	case "ollama":
-		return llm.NewOllamaLLM("", cfg.LLMModel) // Use default localhost URL
+		client := llm.NewOpenAILLM(cfg.LLMAPIKey, cfg.LLMModel)
+		client.SetLogger(log)
+		client.SetSampling(llmSampling(cfg))
+		return client
+	case llm.ProviderOllama:
+		client := llm.NewOllamaLLM("", cfg.LLMModel) // Use default localhost URL
+		client.SetLogger(log)
+		client.SetSampling(llmSampling(cfg))
+		return client
 	default:
 		// Unsupported provider, return stub as fallback
 		fmt.Fprintf(os.Stderr, "Warning: Unknown LLM provider '%s', falling back to stub\n", cfg.LLMProvider)
@@ -387,6 +1665,56 @@ func createLLMClient(cfg *config.Config) llm.LLM {
 	}
 }
 
+// llmSampling maps the configuration onto the sampling settings of summary
+// requests, starting from llm.DefaultSampling
+func llmSampling(cfg *config.Config) llm.Sampling {
+	sampling := llm.DefaultSampling()
+	// Validated by validateConfig
+	if t, err := strconv.ParseFloat(cfg.LLMTemperature, 32); err == nil {
+		sampling.Temperature = float32(t)
+	}
+	if p, err := strconv.ParseFloat(cfg.LLMTopP, 32); err == nil {
+		sampling.TopP = float32(p)
+	}
+	sampling.MaxTokens = cfg.LLMMaxTokens
+	return sampling
+}
+
+// summaryLLMClient returns the client for the final summary call: a client
+// for --summary-model when it differs from --llm-model, otherwise llmClient
+func summaryLLMClient(cfg *config.Config, log *logger.Logger, llmClient llm.LLM) llm.LLM {
+	if cfg.SummaryModel == "" || cfg.SummaryModel == cfg.LLMModel {
+		return llmClient
+	}
+
+	summaryCfg := *cfg
+	summaryCfg.LLMModel = cfg.SummaryModel
+	return withSummaryCache(&summaryCfg, log, createLLMClient(&summaryCfg, log))
+}
+
+// withSummaryCache wraps client in the on-disk summary cache unless caching is
+// disabled or the stub provider is used. Without a user cache directory the
+// client is returned unchanged.
+func withSummaryCache(cfg *config.Config, log *logger.Logger, client llm.LLM) llm.LLM {
+	if cfg.NoSummaryCache || cfg.LLMProvider == "" || cfg.LLMProvider == "stub" {
+		return client
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		log.Info("Summary cache disabled: %v", err)
+		return client
+	}
+
+	// Sampling settings change the summary, so configured ones are part of
+	// the key
+	key := cfg.LLMProvider + "/" + cfg.LLMModel
+	if cfg.LLMTemperature != "" || cfg.LLMTopP != "" || cfg.LLMMaxTokens != 0 {
+		key += fmt.Sprintf("/%+v", llmSampling(cfg))
+	}
+	return llm.NewCachedLLM(client, filepath.Join(dir, "prtool", "summaries"), key)
+}
+
 // GitHubRelease represents a GitHub release response
 type GitHubRelease struct {
 	TagName string `json:"tag_name"`
@@ -402,22 +1730,55 @@ type VersionChecker interface {
 // RealVersionChecker implements VersionChecker using GitHub API
 type RealVersionChecker struct {
 	client *http.Client
+	// attempts is the number of tries made for transient failures
+	attempts   int
+	retryDelay time.Duration
 }
 
 // NewRealVersionChecker creates a new real version checker
 func NewRealVersionChecker() *RealVersionChecker {
 	return &RealVersionChecker{
-		client: &http.Client{Timeout: 10 * time.Second},
+		client:     &http.Client{Timeout: 10 * time.Second},
+		attempts:   2,
+		retryDelay: time.Second,
 	}
 }
 
-// GetLatestRelease fetches the latest release from GitHub
+// GetLatestRelease fetches the latest release from GitHub, retrying once on
+// network errors and 5xx responses. The client timeout applies per attempt.
 func (r *RealVersionChecker) GetLatestRelease() (*GitHubRelease, error) {
+	attempts := r.attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(r.retryDelay)
+		}
+
+		release, retryable, err := r.fetchLatestRelease()
+		if err == nil {
+			return release, nil
+		}
+		lastErr = err
+		if !retryable {
+			break
+		}
+	}
+
+	return nil, lastErr
+}
+
+// fetchLatestRelease makes a single request for the latest release and
+// reports whether a failure is worth retrying
+func (r *RealVersionChecker) fetchLatestRelease() (*GitHubRelease, bool, error) {
 	url := "https://api.github.com/repos/willis7/prtool/releases/latest"
 
 	resp, err := r.client.Get(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch latest release: %w", err)
+		return nil, true, fmt.Errorf("failed to fetch latest release: %w", err)
 	}
 	defer func() {
 		if closeErr := resp.Body.Close(); closeErr != nil {
@@ -427,15 +1788,15 @@ func (r *RealVersionChecker) GetLatestRelease() (*GitHubRelease, error) {
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+		return nil, resp.StatusCode >= 500, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
 	}
 
 	var release GitHubRelease
 	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		return nil, fmt.Errorf("failed to decode release response: %w", err)
+		return nil, false, fmt.Errorf("failed to decode release response: %w", err)
 	}
 
-	return &release, nil
+	return &release, false, nil
 }
 
 var versionChecker VersionChecker = NewRealVersionChecker()
@@ -503,3 +1864,81 @@ Examples:
 		return nil
 	},
 }
+
+// generateSummary asks the LLM for a summary of prs. With chunking enabled the
+// PRs are summarised in chunks and combined; otherwise the context is trimmed
+// to the token budget and summarised in one call.
+func generateSummary(cfg *config.Config, log *logger.Logger, llmClient llm.LLM, prs []*model.PR) (string, error) {
+	prs = llm.SelectContextFields(llm.WeightPRs(prs, cfg.WeightBy), cfg.ContextFields)
+	if cfg.LLMChunkTokens > 0 {
+		chunks := llm.ChunkPRs(prs, cfg.LLMChunkTokens)
+		log.Info("Summarizing %d PR(s) in %d chunk(s)", len(prs), len(chunks))
+		return llm.SummariseChunkedWith(llmClient, summaryLLMClient(cfg, log, llmClient), prs, cfg.LLMChunkTokens, cfg.LLMConcurrency, cfg.SummaryStyle, cfg.LenientChunks)
+	}
+
+	context, trimmed := llm.BuildContextWithBudget(prs, cfg.MaxLLMTokens)
+	if len(trimmed) > 0 {
+		log.Info("LLM context exceeded %d tokens, trimmed %d item(s):", cfg.MaxLLMTokens, len(trimmed))
+		for _, item := range trimmed {
+			log.Info("  - %s", item)
+		}
+	}
+	summary, short, err := llm.SummariseWithMinLength(summaryLLMClient(cfg, log, llmClient), llm.ApplySummaryStyle(context, cfg.SummaryStyle), cfg.MinSummaryChars)
+	if short {
+		log.Warn("Summary is still shorter than %d characters after a retry", cfg.MinSummaryChars)
+	}
+	return summary, err
+}
+
+// estimateLLMUsage estimates the input tokens and cost of the summary calls
+// for prs, building the same contexts as generateSummary. The final call of a
+// chunked summary depends on the chunk summaries and is not counted.
+func estimateLLMUsage(cfg *config.Config, prs []*model.PR) llm.Estimate {
+	prs = llm.SelectContextFields(llm.WeightPRs(prs, cfg.WeightBy), cfg.ContextFields)
+
+	var contexts []string
+	if cfg.LLMChunkTokens > 0 {
+		for _, chunk := range llm.ChunkPRs(prs, cfg.LLMChunkTokens) {
+			contexts = append(contexts, llm.BuildContext(chunk))
+		}
+	} else {
+		context, _ := llm.BuildContextWithBudget(prs, cfg.MaxLLMTokens)
+		contexts = []string{llm.ApplySummaryStyle(context, cfg.SummaryStyle)}
+	}
+
+	// Validated by validateConfig
+	price, err := strconv.ParseFloat(cfg.LLMPricePer1K, 64)
+	if err != nil {
+		price = llm.DefaultPricesPer1K[cfg.LLMProvider]
+	}
+	return llm.EstimateUsage(len(prs), contexts, price)
+}
+
+// formatEstimate describes an LLM usage estimate for the console
+func formatEstimate(cfg *config.Config, estimate llm.Estimate) string {
+	provider := cfg.LLMProvider
+	if provider == "" {
+		provider = llm.ProviderStub
+	}
+	if cfg.LLMModel != "" {
+		provider = fmt.Sprintf("%s (%s)", provider, cfg.LLMModel)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "LLM estimate for %d PR(s) with %s:\n", estimate.PRs, provider)
+	fmt.Fprintf(&b, "  Input tokens: ~%d\n", estimate.Tokens)
+	fmt.Fprintf(&b, "  Approximate cost: $%.4f (at $%g per 1K tokens)\n", estimate.Cost, estimate.PricePer1K)
+	return b.String()
+}
+
+// generateAuthorSummaries asks the LLM for a summary of each author's PRs,
+// with up to --llm-concurrency calls at once
+func generateAuthorSummaries(cfg *config.Config, log *logger.Logger, llmClient llm.LLM, prs []*model.PR) (map[string]string, error) {
+	prs = llm.SelectContextFields(llm.WeightPRs(prs, cfg.WeightBy), cfg.ContextFields)
+	summaries, err := llm.SummariseByAuthor(llmClient, prs, cfg.LLMConcurrency)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Generated summaries for %d author(s)", len(summaries))
+	return summaries, nil
+}
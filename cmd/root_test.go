@@ -2,9 +2,15 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -124,6 +130,27 @@ func TestValidateConfig(t *testing.T) {
 			expectErr: true,
 			errMsg:    "no scope specified",
 		},
+		{
+			name: "path filter without include-files",
+			cfg: &config.Config{
+				GitHubToken: "token123",
+				Org:         "test-org",
+				Paths:       []string{"internal/**"},
+			},
+			expectErr: true,
+			errMsg:    "require --include-files",
+		},
+		{
+			name: "invalid path pattern",
+			cfg: &config.Config{
+				GitHubToken:  "token123",
+				Org:          "test-org",
+				IncludeFiles: true,
+				ExcludePaths: []string{"docs/[a"},
+			},
+			expectErr: true,
+			errMsg:    "invalid path pattern",
+		},
 		{
 			name: "multiple scopes specified",
 			cfg: &config.Config{
@@ -172,19 +199,21 @@ func TestGenerateMetadata(t *testing.T) {
 				LLMModel:    "gpt-4",
 			},
 			prs: []*model.PR{
-				{Repository: "test-org/repo1"},
-				{Repository: "test-org/repo2"},
-				{Repository: "test-org/repo1"}, // duplicate
+				{Repository: "test-org/repo1", Author: "alice"},
+				{Repository: "test-org/repo2", Author: "bob"},
+				{Repository: "test-org/repo1", Author: "alice"}, // duplicate
 			},
 			expected: render.Metadata{
-				Scope:        "organization",
-				ScopeValue:   "test-org",
-				Since:        "-7d",
-				TotalPRs:     3,
-				Repositories: []string{"test-org/repo1", "test-org/repo2"}, // unique
-				LLMProvider:  "openai",
-				LLMModel:     "gpt-4",
-				Summary:      "",
+				Scope:             "organization",
+				ScopeValue:        "test-org",
+				Since:             "-7d",
+				TotalPRs:          3,
+				TotalContributors: 2,
+				TotalRepositories: 2,
+				Repositories:      []string{"test-org/repo1", "test-org/repo2"}, // unique
+				LLMProvider:       "openai",
+				LLMModel:          "gpt-4",
+				Summary:           "",
 			},
 		},
 		{
@@ -193,24 +222,26 @@ func TestGenerateMetadata(t *testing.T) {
 				User: "test-user",
 			},
 			prs: []*model.PR{
-				{Repository: "test-user/personal-repo"},
+				{Repository: "test-user/personal-repo", Author: "test-user"},
 			},
 			expected: render.Metadata{
-				Scope:        "user",
-				ScopeValue:   "test-user",
-				Since:        "-7d", // default
-				TotalPRs:     1,
-				Repositories: []string{"test-user/personal-repo"},
-				LLMProvider:  "",
-				LLMModel:     "",
-				Summary:      "",
+				Scope:             "user",
+				ScopeValue:        "test-user",
+				Since:             "-7d", // default
+				TotalPRs:          1,
+				TotalContributors: 1,
+				TotalRepositories: 1,
+				Repositories:      []string{"test-user/personal-repo"},
+				LLMProvider:       "",
+				LLMModel:          "",
+				Summary:           "",
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := generateMetadata(tt.cfg, tt.prs)
+			result := generateMetadata(tt.cfg, tt.prs, nil)
 
 			// Check specific fields (ignore GeneratedAt as it's time-dependent)
 			if result.Scope != tt.expected.Scope {
@@ -225,6 +256,12 @@ func TestGenerateMetadata(t *testing.T) {
 			if result.TotalPRs != tt.expected.TotalPRs {
 				t.Errorf("Expected total PRs %d, got %d", tt.expected.TotalPRs, result.TotalPRs)
 			}
+			if result.TotalContributors != tt.expected.TotalContributors {
+				t.Errorf("Expected %d contributors, got %d", tt.expected.TotalContributors, result.TotalContributors)
+			}
+			if result.TotalRepositories != tt.expected.TotalRepositories {
+				t.Errorf("Expected %d repositories in total, got %d", tt.expected.TotalRepositories, result.TotalRepositories)
+			}
 			if len(result.Repositories) != len(tt.expected.Repositories) {
 				t.Errorf("Expected %d repositories, got %d", len(tt.expected.Repositories), len(result.Repositories))
 			}
@@ -237,6 +274,35 @@ func TestGenerateMetadata(t *testing.T) {
 	}
 }
 
+func TestGenerateMetadata_ReposWithoutPRs(t *testing.T) {
+	prs := []*model.PR{{Title: "Fix", Repository: "acme/api"}}
+	scanned := []string{"acme/web", "acme/api"}
+
+	metadata := generateMetadata(&config.Config{Org: "acme"}, prs, scanned)
+	if len(metadata.ScannedRepositories) != 0 {
+		t.Errorf("Expected scanned repositories to be omitted by default, got %v", metadata.ScannedRepositories)
+	}
+
+	metadata = generateMetadata(&config.Config{Org: "acme", IncludeReposWithoutPRs: true}, prs, scanned)
+	if got := strings.Join(metadata.ScannedRepositories, ","); got != "acme/api,acme/web" {
+		t.Errorf("Expected sorted scanned repositories, got %s", got)
+	}
+
+	report := render.Render(metadata, prs)
+	if !strings.Contains(report, "- **Repositories Scanned**: 2 (1 with PRs)") {
+		t.Errorf("Expected scanned repository count in report, got:\n%s", report)
+	}
+	if !strings.Contains(report, "- **Repositories Without PRs**: acme/web") {
+		t.Errorf("Expected repository without PRs in report, got:\n%s", report)
+	}
+
+	// Showing empty sections needs the scanned repositories for empty repo groups
+	metadata = generateMetadata(&config.Config{Org: "acme", ShowEmptySections: true}, prs, scanned)
+	if len(metadata.ScannedRepositories) != 2 {
+		t.Errorf("Expected scanned repositories when showing empty sections, got %v", metadata.ScannedRepositories)
+	}
+}
+
 func TestWriteToFile(t *testing.T) {
 	// Create temporary directory for tests
 	tempDir := t.TempDir()
@@ -269,7 +335,7 @@ func TestWriteToFile(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := writeToFile(tt.filename, tt.content)
+			err := writeToFile(tt.filename, tt.content, defaultOutputMode)
 
 			if tt.expectError {
 				if err == nil {
@@ -294,6 +360,93 @@ func TestWriteToFile(t *testing.T) {
 	}
 }
 
+func TestWriteToFile_Atomic(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "report.md")
+
+	if err := os.WriteFile(filename, []byte("old report"), 0644); err != nil {
+		t.Fatalf("Failed to seed output file: %v", err)
+	}
+
+	if err := writeToFile(filename, "new report", defaultOutputMode); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	written, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read written file: %v", err)
+	}
+	if string(written) != "new report" {
+		t.Errorf("Expected the complete new report, got %q", string(written))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read output dir: %v", err)
+	}
+	if len(entries) != 1 {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Errorf("Expected only the report to remain, got %v", names)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatalf("Failed to stat written file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0644 {
+		t.Errorf("Expected file mode 0644, got %o", perm)
+	}
+}
+
+func TestWritePartialReport(t *testing.T) {
+	mergedAt := time.Date(2024, 1, 14, 15, 20, 0, 0, time.UTC)
+	outputPath := filepath.Join(t.TempDir(), "partial.md")
+	cfg := &config.Config{Org: "acme", Output: outputPath}
+	prs := []*model.PR{
+		{Title: "Fetched before Ctrl-C", Author: "alice", Repository: "acme/api", Number: 1, MergedAt: &mergedAt},
+	}
+
+	if err := writePartialReport(cfg, nil, prs, service.NewFetcher(gh.NewMockClient())); err != nil {
+		t.Fatalf("writePartialReport() failed: %v", err)
+	}
+
+	report, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Expected a partial report to be written: %v", err)
+	}
+	if !strings.Contains(string(report), "Fetched before Ctrl-C") {
+		t.Errorf("Expected partial report to include fetched PRs, got:\n%s", report)
+	}
+	if !strings.Contains(string(report), "**Status**: Partial") {
+		t.Errorf("Expected partial report to be marked as partial, got:\n%s", report)
+	}
+}
+
+func TestFormatPlan(t *testing.T) {
+	since := time.Date(2024, 1, 8, 9, 30, 0, 0, time.UTC)
+	plan := &service.Plan{Repos: []service.PlannedRepo{
+		{Name: "acme/api", Since: since},
+		{Name: "acme/web", Since: since},
+	}}
+
+	output := formatPlan(&config.Config{Org: "acme", Since: "-7d"}, plan)
+
+	for _, expected := range []string{
+		"- since=-7d",
+		"- state=merged",
+		"Repositories (2):",
+		"- acme/api (since 2024-01-08 09:30)",
+		"- acme/web (since 2024-01-08 09:30)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected plan to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
 func TestDryRunIntegration(t *testing.T) {
 	// This test verifies the dry-run functionality works end-to-end
 	// We'll mock the GitHub client and test the table output
@@ -416,7 +569,7 @@ func TestCreateLLMClient(t *testing.T) {
 			r, w, _ := os.Pipe()
 			os.Stderr = w
 
-			client := createLLMClient(tt.cfg)
+			client := createLLMClient(tt.cfg, nil)
 
 			// Restore stderr
 			_ = w.Close() // Ignore error in test cleanup
@@ -488,10 +641,10 @@ func TestLLMIntegration(t *testing.T) {
 	}
 
 	// Generate metadata with LLM summary
-	metadata := generateMetadata(cfg, prs)
+	metadata := generateMetadata(cfg, prs, nil)
 
 	// Create LLM client and generate summary
-	llmClient := createLLMClient(cfg)
+	llmClient := createLLMClient(cfg, nil)
 	context := llm.BuildContext(prs)
 	summary, err := llmClient.Summarise(context)
 	if err != nil {
@@ -591,7 +744,7 @@ func TestEndToEndWithLLM(t *testing.T) {
 	}
 
 	// Step 3: Generate metadata
-	metadata := generateMetadata(cfg, prs)
+	metadata := generateMetadata(cfg, prs, nil)
 
 	// Verify basic metadata
 	if metadata.Scope != "organization" {
@@ -605,7 +758,7 @@ func TestEndToEndWithLLM(t *testing.T) {
 	}
 
 	// Step 4: Generate LLM summary
-	llmClient := createLLMClient(cfg)
+	llmClient := createLLMClient(cfg, nil)
 	context := llm.BuildContext(prs)
 	summary, err := llmClient.Summarise(context)
 	if err != nil {
@@ -871,6 +1024,94 @@ func TestRealVersionChecker(t *testing.T) {
 	}
 }
 
+func TestRealVersionChecker_Retry(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      []func(*http.Request) (*http.Response, error)
+		expectError   bool
+		expectedCalls int
+	}{
+		{
+			name: "network error then success",
+			failures: []func(*http.Request) (*http.Response, error){
+				func(*http.Request) (*http.Response, error) { return nil, fmt.Errorf("connection reset") },
+			},
+			expectedCalls: 2,
+		},
+		{
+			name: "server error then success",
+			failures: []func(*http.Request) (*http.Response, error){
+				func(req *http.Request) (*http.Response, error) {
+					return &http.Response{StatusCode: http.StatusBadGateway, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+				},
+			},
+			expectedCalls: 2,
+		},
+		{
+			name: "client error is not retried",
+			failures: []func(*http.Request) (*http.Response, error){
+				func(req *http.Request) (*http.Response, error) {
+					return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+				},
+			},
+			expectError:   true,
+			expectedCalls: 1,
+		},
+		{
+			name: "gives up after two attempts",
+			failures: []func(*http.Request) (*http.Response, error){
+				func(*http.Request) (*http.Response, error) { return nil, fmt.Errorf("connection reset") },
+				func(*http.Request) (*http.Response, error) { return nil, fmt.Errorf("connection reset") },
+			},
+			expectError:   true,
+			expectedCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			checker := NewRealVersionChecker()
+			checker.retryDelay = 0
+			checker.client.Transport = versionRoundTripFunc(func(req *http.Request) (*http.Response, error) {
+				calls++
+				if calls <= len(tt.failures) {
+					return tt.failures[calls-1](req)
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"tag_name":"v1.2.3"}`)),
+					Request:    req,
+				}, nil
+			})
+
+			release, err := checker.GetLatestRelease()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if release.TagName != "v1.2.3" {
+					t.Errorf("Expected tag v1.2.3, got %q", release.TagName)
+				}
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("Expected %d attempts, got %d", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
+
+// versionRoundTripFunc adapts a function to http.RoundTripper
+type versionRoundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f versionRoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
 func TestCIMode(t *testing.T) {
 	// Save original version checker
 	originalChecker := versionChecker
@@ -1087,3 +1328,1022 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+func TestWriteOutput_StdoutFormat(t *testing.T) {
+	mergedAt := time.Date(2024, 1, 14, 15, 20, 0, 0, time.UTC)
+	prs := []*model.PR{
+		{
+			Title:      "Add caching layer",
+			Author:     "alice",
+			Repository: "test-org/repo1",
+			Number:     7,
+			MergedAt:   &mergedAt,
+			State:      "closed",
+		},
+	}
+	markdownOutput := render.Render(render.Metadata{TotalPRs: 1}, prs)
+
+	tests := []struct {
+		name           string
+		stdoutFormat   string
+		expectStdout   string
+		unexpectStdout string
+	}{
+		{
+			name:           "table on stdout",
+			stdoutFormat:   "table",
+			expectStdout:   "| # | Title | Author | Repository | Merged At |",
+			unexpectStdout: "# Pull Request Summary",
+		},
+		{
+			name:         "markdown on stdout",
+			stdoutFormat: "markdown",
+			expectStdout: "# Pull Request Summary",
+		},
+		{
+			name:           "nothing on stdout by default",
+			stdoutFormat:   "",
+			unexpectStdout: "Add caching layer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "report.md")
+			cfg := &config.Config{
+				Output:             outputPath,
+				OutputStdoutFormat: tt.stdoutFormat,
+			}
+
+			log, err := logger.New(false, false, "")
+			if err != nil {
+				t.Fatalf("Failed to create logger: %v", err)
+			}
+
+			var writeErr error
+			stdout := captureStdout(t, func() {
+				writeErr = writeOutput(cfg, log, markdownOutput, prs)
+			})
+			if writeErr != nil {
+				t.Fatalf("Unexpected error: %v", writeErr)
+			}
+
+			written, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("Failed to read output file: %v", err)
+			}
+			if string(written) != markdownOutput {
+				t.Errorf("Expected file to contain markdown report, got %q", string(written))
+			}
+
+			if tt.expectStdout != "" && !strings.Contains(stdout, tt.expectStdout) {
+				t.Errorf("Expected stdout to contain %q, got %q", tt.expectStdout, stdout)
+			}
+			if tt.unexpectStdout != "" && strings.Contains(stdout, tt.unexpectStdout) {
+				t.Errorf("Expected stdout not to contain %q, got %q", tt.unexpectStdout, stdout)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_OutputStdoutFormat(t *testing.T) {
+	cfg := &config.Config{
+		GitHubToken:        "token123",
+		Org:                "test-org",
+		OutputStdoutFormat: "html",
+	}
+
+	err := validateConfig(cfg)
+	if err == nil {
+		t.Fatal("Expected error for invalid stdout format")
+	}
+	if !strings.Contains(err.Error(), "invalid output stdout format") {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+}
+
+func TestValidateConfig_GroupBy(t *testing.T) {
+	tests := []struct {
+		name        string
+		groupBy     string
+		mergeTarget bool
+		expectError string
+	}{
+		{name: "empty", groupBy: ""},
+		{name: "explicit none", groupBy: "none"},
+		{name: "repo", groupBy: "repo"},
+		{name: "milestone", groupBy: "milestone"},
+		{name: "typo", groupBy: "auther", expectError: "invalid group-by 'auther'"},
+		{name: "combined with merge-target", groupBy: "author", mergeTarget: true, expectError: "cannot be combined"},
+		{name: "none with merge-target", groupBy: "none", mergeTarget: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				GitHubToken: "token123",
+				Org:         "test-org",
+				GroupBy:     tt.groupBy,
+				MergeTarget: tt.mergeTarget,
+			}
+
+			err := validateConfig(cfg)
+			if tt.expectError == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		header      string
+		key         string
+		value       string
+		expectError bool
+	}{
+		{header: "X-Internal-Auth=secret", key: "X-Internal-Auth", value: "secret"},
+		{header: "X-Trace = a=b", key: "X-Trace", value: "a=b"},
+		{header: "X-Empty=", key: "X-Empty", value: ""},
+		{header: "X-Internal-Auth", expectError: true},
+		{header: "=secret", expectError: true},
+		{header: "X-Auth: secret", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			key, value, err := parseHeader(tt.header)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %q", tt.header)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if key != tt.key || value != tt.value {
+				t.Errorf("Expected %q=%q, got %q=%q", tt.key, tt.value, key, value)
+			}
+		})
+	}
+
+	cfg := &config.Config{GitHubToken: "token123", Org: "acme", GitHubHeaders: []string{"bogus"}}
+	if err := validateConfig(cfg); err == nil {
+		t.Error("Expected validateConfig to reject a malformed header")
+	}
+}
+
+func TestRenderReport_NamedTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "exec.tmpl"), []byte("Exec: {{.Metadata.TotalPRs}} PRs\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "changelog.tmpl"), []byte("Changelog\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	cfg := &config.Config{TemplateDir: dir, Template: "exec"}
+	output, err := renderReport(cfg, render.Metadata{TotalPRs: 3}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output != "Exec: 3 PRs\n" {
+		t.Errorf("Expected the exec template output, got %q", output)
+	}
+
+	cfg.Template = "detailed"
+	_, err = renderReport(cfg, render.Metadata{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "template 'detailed' not found") {
+		t.Errorf("Expected a missing template error, got %v", err)
+	}
+
+	validation := &config.Config{GitHubToken: "token123", Org: "acme", TemplateDir: dir, Template: "detailed"}
+	if err := validateConfig(validation); err == nil {
+		t.Error("Expected validateConfig to reject a missing template")
+	}
+
+	cfg.Template = "../exec"
+	if _, err := renderReport(cfg, render.Metadata{}, nil); err == nil {
+		t.Error("Expected error for a template name containing a path")
+	}
+}
+
+func TestValidateConfig_SummaryStyle(t *testing.T) {
+	for _, style := range []string{"", "prose", "bullets"} {
+		cfg := &config.Config{GitHubToken: "token123", Org: "acme", SummaryStyle: style}
+		if err := validateConfig(cfg); err != nil {
+			t.Errorf("Unexpected error for style %q: %v", style, err)
+		}
+	}
+
+	cfg := &config.Config{GitHubToken: "token123", Org: "acme", SummaryStyle: "haiku"}
+	if err := validateConfig(cfg); err == nil || !strings.Contains(err.Error(), "invalid summary style") {
+		t.Errorf("Expected invalid summary style error, got %v", err)
+	}
+}
+
+// captureStdout runs fn and returns everything it wrote to os.Stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	os.Stdout = w
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	fn()
+
+	_ = w.Close()
+	os.Stdout = oldStdout
+
+	return <-done
+}
+
+func TestGitHubUserAgent(t *testing.T) {
+	if got := githubUserAgent(&config.Config{}); got != "prtool/"+version {
+		t.Errorf("Expected default user agent %q, got %q", "prtool/"+version, got)
+	}
+	if got := githubUserAgent(&config.Config{UserAgent: "gateway-client/1.0"}); got != "gateway-client/1.0" {
+		t.Errorf("Expected configured user agent, got %q", got)
+	}
+}
+
+func TestDescribeFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		expected []string
+	}{
+		{
+			name:     "defaults",
+			cfg:      &config.Config{Org: "test-org"},
+			expected: []string{"since=-7d", "state=merged"},
+		},
+		{
+			name:     "custom since and template stripping",
+			cfg:      &config.Config{Org: "test-org", Since: "-30d", StripPRTemplate: true},
+			expected: []string{"since=-30d", "state=merged", "strip-pr-template"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := describeFilters(tt.cfg)
+			if strings.Join(result, ", ") != strings.Join(tt.expected, ", ") {
+				t.Errorf("Expected filters %v, got %v", tt.expected, result)
+			}
+
+			metadata := generateMetadata(tt.cfg, nil, nil)
+			if strings.Join(metadata.Filters, ", ") != strings.Join(tt.expected, ", ") {
+				t.Errorf("Expected metadata filters %v, got %v", tt.expected, metadata.Filters)
+			}
+		})
+	}
+}
+
+func TestFormatEffectiveConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "prtool.yaml")
+	if err := os.WriteFile(configPath, []byte("org: yaml-org\nsince: -30d\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	originalCfgFile := cfgFile
+	cfgFile = configPath
+	t.Cleanup(func() { cfgFile = originalCfgFile })
+
+	t.Setenv("PRTOOL_GITHUB_TOKEN", "ghp_secret123")
+	t.Setenv("PRTOOL_ORG", "env-org")
+	t.Setenv("PRTOOL_GITHUB_HEADERS", "X-Internal-Auth=hunter2")
+	originalOrg := org
+	org = "cli-org"
+	t.Cleanup(func() { org = originalOrg })
+
+	effective, err := formatEffectiveConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"org: cli-org # cli\n",
+		"since: -30d # yaml\n",
+		"github_token: <redacted> # env\n",
+		"- X-Internal-Auth=<redacted>\n",
+		"group_by: none # default\n",
+	} {
+		if !strings.Contains(effective, want) {
+			t.Errorf("Expected %q in effective config, got:\n%s", want, effective)
+		}
+	}
+	if strings.Contains(effective, "ghp_secret123") || strings.Contains(effective, "hunter2") {
+		t.Errorf("Expected secrets to be redacted, got:\n%s", effective)
+	}
+}
+
+func TestGetConfig_FlagWiring(t *testing.T) {
+	originalCfgFile := cfgFile
+	cfgFile = filepath.Join(t.TempDir(), "missing.yaml")
+	t.Cleanup(func() { cfgFile = originalCfgFile })
+
+	originalMaxLLMTokens := maxLLMTokens
+	maxLLMTokens = 1234
+	t.Cleanup(func() { maxLLMTokens = originalMaxLLMTokens })
+
+	originalClipboard := copyToClipboard
+	copyToClipboard = true
+	t.Cleanup(func() { copyToClipboard = originalClipboard })
+
+	originalMergeTarget := mergeTarget
+	mergeTarget = true
+	t.Cleanup(func() { mergeTarget = originalMergeTarget })
+
+	originalLargeWindowDays := largeWindowDays
+	largeWindowDays = 90
+	t.Cleanup(func() { largeWindowDays = originalLargeWindowDays })
+
+	originalLLMConcurrency := llmConcurrency
+	llmConcurrency = 4
+	t.Cleanup(func() { llmConcurrency = originalLLMConcurrency })
+
+	cfg, err := GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig() failed: %v", err)
+	}
+
+	if cfg.MaxLLMTokens != 1234 {
+		t.Errorf("Expected MaxLLMTokens 1234, got %d", cfg.MaxLLMTokens)
+	}
+	if !cfg.Clipboard {
+		t.Error("Expected Clipboard to be enabled")
+	}
+	if !cfg.MergeTarget {
+		t.Error("Expected MergeTarget to be enabled")
+	}
+	if cfg.LargeWindowDays != 90 {
+		t.Errorf("Expected LargeWindowDays 90, got %d", cfg.LargeWindowDays)
+	}
+	if cfg.LLMConcurrency != 4 {
+		t.Errorf("Expected LLMConcurrency 4, got %d", cfg.LLMConcurrency)
+	}
+}
+
+func TestCheckSinceWindow(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.Config
+		expectWarn  bool
+		expectError bool
+	}{
+		{
+			name: "small window",
+			cfg:  config.Config{Since: "-7d"},
+		},
+		{
+			name:       "large window warns",
+			cfg:        config.Config{Since: "-100yr"},
+			expectWarn: true,
+		},
+		{
+			name:       "custom threshold",
+			cfg:        config.Config{Since: "-2m", LargeWindowDays: 30},
+			expectWarn: true,
+		},
+		{
+			name:        "large window fails in CI",
+			cfg:         config.Config{Since: "-100yr", CI: true},
+			expectError: true,
+		},
+		{
+			name:       "large window allowed in CI",
+			cfg:        config.Config{Since: "-100yr", CI: true, AllowLargeWindow: true},
+			expectWarn: true,
+		},
+		{
+			name: "latest release is not checked",
+			cfg:  config.Config{Since: "latest-release"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logFile := filepath.Join(t.TempDir(), "prtool.log")
+			log, err := logger.New(false, tt.cfg.CI, logFile)
+			if err != nil {
+				t.Fatalf("Failed to create logger: %v", err)
+			}
+
+			err = checkSinceWindow(&tt.cfg, log)
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			logged, _ := os.ReadFile(logFile)
+			warned := strings.Contains(string(logged), "Warning: since window")
+			if warned != tt.expectWarn {
+				t.Errorf("Expected warning=%v, got log %q", tt.expectWarn, string(logged))
+			}
+		})
+	}
+}
+
+func TestRenderReport_Formats(t *testing.T) {
+	cfg := &config.Config{Format: "json"}
+	output, err := renderReport(cfg, render.Metadata{TotalPRs: 0}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !json.Valid([]byte(output)) {
+		t.Errorf("Expected valid JSON output, got %q", output)
+	}
+
+	cfg.Format = "markdown"
+	output, err = renderReport(cfg, render.Metadata{}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(output, "# Pull Request Summary") {
+		t.Errorf("Expected markdown output, got %q", output)
+	}
+
+	cfg.Format = "badge"
+	cfg.BadgeLabel = "PRs this sprint"
+	output, err = renderReport(cfg, render.Metadata{TotalPRs: 42}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `"label":"PRs this sprint"`) || !strings.Contains(output, `"message":"42"`) {
+		t.Errorf("Expected badge output, got %q", output)
+	}
+
+	cfg.Format = "xml"
+	if _, err := renderReport(cfg, render.Metadata{}, nil); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+func TestConfirmScan(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		input      string
+		expectScan bool
+		expectAsk  bool
+	}{
+		{name: "declined", cfg: &config.Config{Org: "acme", ConfirmThreshold: 2}, input: "n\n", expectAsk: true},
+		{name: "empty answer declines", cfg: &config.Config{Org: "acme", ConfirmThreshold: 2}, input: "\n", expectAsk: true},
+		{name: "accepted", cfg: &config.Config{Org: "acme", ConfirmThreshold: 2}, input: "y\n", expectScan: true, expectAsk: true},
+		{name: "CI never prompts", cfg: &config.Config{Org: "acme", ConfirmThreshold: 2, CI: true}, expectScan: true},
+		{name: "below default threshold", cfg: &config.Config{Org: "acme"}, expectScan: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := gh.NewMockClient()
+			mockClient.SetMockRepos([]*github.Repository{
+				{FullName: github.String("acme/one")},
+				{FullName: github.String("acme/two")},
+				{FullName: github.String("acme/three")},
+			})
+
+			var prompt bytes.Buffer
+			fetcher := service.NewFetcher(mockClient)
+			fetcher.SetConfirm(confirmScan(tt.cfg, strings.NewReader(tt.input), &prompt))
+
+			_, err := fetcher.Fetch(tt.cfg)
+
+			listed := 0
+			for _, call := range mockClient.GetCallLog() {
+				if strings.HasPrefix(call, "ListPRs") {
+					listed++
+				}
+			}
+
+			if tt.expectScan {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if listed != 3 {
+					t.Errorf("Expected all 3 repositories to be scanned, got %d", listed)
+				}
+			} else {
+				if !errors.Is(err, service.ErrScanDeclined) {
+					t.Errorf("Expected ErrScanDeclined, got %v", err)
+				}
+				if listed != 0 {
+					t.Errorf("Expected no ListPRs calls after declining, got %d", listed)
+				}
+			}
+
+			asked := strings.Contains(prompt.String(), "About to scan 3 repos, continue? [y/N]")
+			if asked != tt.expectAsk {
+				t.Errorf("Expected prompt shown=%v, got %q", tt.expectAsk, prompt.String())
+			}
+		})
+	}
+}
+
+func TestWriteOutput_SkipsUnchangedContent(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "report.md")
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	writeAndStat := func(cfg *config.Config, content string) time.Time {
+		t.Helper()
+		if err := writeOutput(cfg, nil, content, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		info, err := os.Stat(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to stat output: %v", err)
+		}
+		return info.ModTime()
+	}
+	age := func() {
+		t.Helper()
+		if err := os.Chtimes(outputPath, old, old); err != nil {
+			t.Fatalf("Failed to set file times: %v", err)
+		}
+	}
+
+	cfg := &config.Config{Output: outputPath}
+	writeAndStat(cfg, "# Report\n")
+	age()
+
+	if modTime := writeAndStat(cfg, "# Report\n"); !modTime.Equal(old) {
+		t.Errorf("Expected identical content not to be rewritten, modified at %v", modTime)
+	}
+
+	if modTime := writeAndStat(&config.Config{Output: outputPath, Force: true}, "# Report\n"); modTime.Equal(old) {
+		t.Error("Expected --force to rewrite identical content")
+	}
+	age()
+
+	if modTime := writeAndStat(cfg, "# New report\n"); modTime.Equal(old) {
+		t.Error("Expected changed content to be written")
+	}
+	if written, _ := os.ReadFile(outputPath); string(written) != "# New report\n" {
+		t.Errorf("Expected new content, got %q", written)
+	}
+}
+
+func TestWriteOutput_SkipsRerenderedReport(t *testing.T) {
+	prs := []*model.PR{{Number: 1, Title: "Add login", Author: "alice", Repository: "acme/api"}}
+	for _, format := range []string{"markdown", "json"} {
+		t.Run(format, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "report")
+			cfg := &config.Config{Org: "acme", Since: "-7d", Format: format, Output: outputPath}
+			renderNow := func() string {
+				t.Helper()
+				metadata := generateMetadata(cfg, prs, nil)
+				now := time.Now()
+				since := now.AddDate(0, 0, -7)
+				metadata.SinceTime, metadata.UntilTime = &since, &now
+				content, err := renderReport(cfg, metadata, prs)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return content
+			}
+
+			first := renderNow()
+			if err := writeOutput(cfg, nil, first, nil); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			second := renderNow()
+			if first == second {
+				t.Fatal("Expected the two renders to differ in their volatile fields")
+			}
+			if !fileUnchanged(outputPath, second) {
+				t.Error("Expected a rerender of the same PRs to count as unchanged")
+			}
+			if fileUnchanged(outputPath, applyLineEnding(second, lineEndingCRLF)) {
+				t.Error("Expected a change of line endings to count as changed")
+			}
+		})
+	}
+}
+
+func TestGenerateMetadata_ReportID(t *testing.T) {
+	first := generateMetadata(&config.Config{Org: "acme"}, nil, nil)
+	second := generateMetadata(&config.Config{Org: "acme"}, nil, nil)
+	if first.ReportID == "" || first.ReportID == second.ReportID {
+		t.Errorf("Expected unique generated report IDs, got %q and %q", first.ReportID, second.ReportID)
+	}
+	if matched, _ := regexp.MatchString(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, first.ReportID); !matched {
+		t.Errorf("Expected a UUID report ID, got %q", first.ReportID)
+	}
+
+	metadata := generateMetadata(&config.Config{Org: "acme", ReportID: "weekly-2024-03"}, nil, nil)
+	if metadata.ReportID != "weekly-2024-03" {
+		t.Errorf("Expected the supplied report ID, got %q", metadata.ReportID)
+	}
+}
+
+func TestWithSummaryCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	client := llm.NewStubLLM()
+
+	if got := withSummaryCache(&config.Config{LLMProvider: "stub"}, nil, client); got != llm.LLM(client) {
+		t.Error("Expected the stub provider not to be cached")
+	}
+	if got := withSummaryCache(&config.Config{LLMProvider: "ollama", NoSummaryCache: true}, nil, client); got != llm.LLM(client) {
+		t.Error("Expected --no-summary-cache to bypass the cache")
+	}
+	if _, ok := withSummaryCache(&config.Config{LLMProvider: "ollama"}, nil, client).(*llm.CachedLLM); !ok {
+		t.Error("Expected real providers to be cached by default")
+	}
+}
+
+func TestSummaryLLMClient(t *testing.T) {
+	client := llm.NewStubLLM()
+
+	if got := summaryLLMClient(&config.Config{LLMProvider: "ollama", LLMModel: "llama3.2"}, nil, client); got != llm.LLM(client) {
+		t.Error("Expected the base client without --summary-model")
+	}
+	if got := summaryLLMClient(&config.Config{LLMProvider: "ollama", LLMModel: "llama3.2", SummaryModel: "llama3.2"}, nil, client); got != llm.LLM(client) {
+		t.Error("Expected the base client when --summary-model matches --llm-model")
+	}
+	cfg := &config.Config{LLMProvider: "ollama", LLMModel: "llama3.2", SummaryModel: "llama3.3:70b", NoSummaryCache: true}
+	if _, ok := summaryLLMClient(cfg, nil, client).(*llm.OllamaLLM); !ok {
+		t.Error("Expected a separate client for --summary-model")
+	}
+}
+
+func TestLLMSampling(t *testing.T) {
+	if got := llmSampling(&config.Config{}); got != llm.DefaultSampling() {
+		t.Errorf("Expected the default sampling, got %+v", got)
+	}
+
+	got := llmSampling(&config.Config{LLMTemperature: "0", LLMTopP: "0.9", LLMMaxTokens: 1000})
+	if expected := (llm.Sampling{Temperature: 0, TopP: 0.9, MaxTokens: 1000}); got != expected {
+		t.Errorf("Expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestEstimateLLMUsage(t *testing.T) {
+	makePRs := func(n int) []*model.PR {
+		prs := make([]*model.PR, n)
+		for i := range prs {
+			prs[i] = &model.PR{
+				Title:      fmt.Sprintf("Improve request handling %d", i),
+				Body:       strings.Repeat("Details of the change. ", 10),
+				Author:     "alice",
+				Repository: "test-org/api",
+			}
+		}
+		return prs
+	}
+
+	cfg := &config.Config{LLMProvider: "openai", LLMPricePer1K: "0.01"}
+	small := estimateLLMUsage(cfg, makePRs(5))
+	large := estimateLLMUsage(cfg, makePRs(50))
+
+	if small.PRs != 5 || large.PRs != 50 {
+		t.Errorf("Expected 5 and 50 PRs, got %d and %d", small.PRs, large.PRs)
+	}
+	if large.Tokens < 9*small.Tokens {
+		t.Errorf("Expected tokens to scale with PR count, got %d for 5 PRs and %d for 50", small.Tokens, large.Tokens)
+	}
+	if want := float64(large.Tokens) / 1000 * 0.01; large.Cost != want {
+		t.Errorf("Expected cost %f, got %f", want, large.Cost)
+	}
+
+	// Without a configured price the provider default applies
+	if got := estimateLLMUsage(&config.Config{LLMProvider: "openai"}, makePRs(5)); got.PricePer1K != llm.DefaultPricesPer1K["openai"] {
+		t.Errorf("Expected the default OpenAI price, got %g", got.PricePer1K)
+	}
+	if got := estimateLLMUsage(&config.Config{LLMProvider: "ollama"}, makePRs(5)); got.Cost != 0 {
+		t.Errorf("Expected Ollama to be free, got %f", got.Cost)
+	}
+}
+
+func TestWriteOutput_AppendRotates(t *testing.T) {
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "report.md")
+	cfg := &config.Config{Output: outputPath, Append: true, MaxOutputSize: "20B"}
+
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		return string(data)
+	}
+
+	for _, report := range []string{"# Run 1\n", "# Run 2\n", "# Run 3\n", "# Run 4\n"} {
+		if err := writeOutput(cfg, nil, report, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// Runs 1 and 2 fit in 20 bytes; run 3 rotates them out, and so on
+	if got := read("report.md"); got != "# Run 3\n\n# Run 4\n" {
+		t.Errorf("Unexpected current file %q", got)
+	}
+	if got := read("report.1.md"); got != "# Run 1\n\n# Run 2\n" {
+		t.Errorf("Unexpected rotated file %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "report.2.md")); err == nil {
+		t.Error("Expected a single rotation")
+	}
+
+	// Growing past the limit again shifts the older copy
+	if err := writeOutput(cfg, nil, "# Run 5\n", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := read("report.2.md"); got != "# Run 1\n\n# Run 2\n" {
+		t.Errorf("Expected the oldest copy to move to report.2.md, got %q", got)
+	}
+	if got := read("report.1.md"); got != "# Run 3\n\n# Run 4\n" {
+		t.Errorf("Unexpected report.1.md %q", got)
+	}
+	if got := read("report.md"); got != "# Run 5\n" {
+		t.Errorf("Expected a fresh file after rotation, got %q", got)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{input: "", expected: 0},
+		{input: "500000", expected: 500000},
+		{input: "512KB", expected: 512 << 10},
+		{input: "10mb", expected: 10 << 20},
+		{input: "1 GB", expected: 1 << 30},
+		{input: "20B", expected: 20},
+		{input: "ten", wantErr: true},
+		{input: "-5MB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSize(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseSize(%q): expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("parseSize(%q) = %d, %v; expected %d", tt.input, got, err, tt.expected)
+		}
+	}
+}
+
+func TestWriteOutput_OutputMode(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "report.md")
+	cfg := &config.Config{Output: filename, OutputMode: "0600"}
+
+	if err := writeOutput(cfg, nil, "secret report", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatalf("Failed to stat output: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("Expected mode 0600, got %#o", mode)
+	}
+}
+
+func TestWriteOutput_LineEndingCRLF(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "report.md")
+	cfg := &config.Config{Output: filename, LineEnding: "crlf"}
+
+	if err := writeOutput(cfg, nil, "# Report\n\n- first\r\n- second\n", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	expected := "# Report\r\n\r\n- first\r\n- second\r\n"
+	if string(data) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(data))
+	}
+}
+
+func TestWriteOutput_NamedPipe(t *testing.T) {
+	if _, err := exec.LookPath("mkfifo"); err != nil {
+		t.Skip("mkfifo is not available")
+	}
+	pipe := filepath.Join(t.TempDir(), "report.pipe")
+	if out, err := exec.Command("mkfifo", pipe).CombinedOutput(); err != nil {
+		t.Fatalf("Failed to create pipe: %v: %s", err, out)
+	}
+
+	received := make(chan string, 1)
+	go func() {
+		data, _ := os.ReadFile(pipe)
+		received <- string(data)
+	}()
+
+	cfg := &config.Config{Output: pipe}
+	if err := writeOutput(cfg, nil, "# Report\n", nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	select {
+	case got := <-received:
+		if got != "# Report\n" {
+			t.Errorf("Expected the report to arrive through the pipe, got %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the reader")
+	}
+	if !isNamedPipe(pipe) {
+		t.Error("Expected the pipe not to be replaced by a regular file")
+	}
+}
+
+func TestParseFileMode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected os.FileMode
+		wantErr  bool
+	}{
+		{input: "", expected: 0644},
+		{input: "0600", expected: 0600},
+		{input: "640", expected: 0640},
+		{input: "0o600", wantErr: true},
+		{input: "0800", wantErr: true},
+		{input: "01777", wantErr: true},
+		{input: "rw", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseFileMode(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseFileMode(%q): expected error", tt.input)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("parseFileMode(%q) = %#o, %v; expected %#o", tt.input, got, err, tt.expected)
+		}
+	}
+}
+
+func TestValidateConfig_Append(t *testing.T) {
+	base := config.Config{GitHubToken: "token123", Org: "test-org"}
+
+	cfg := base
+	cfg.MaxOutputSize = "10MB"
+	cfg.Output = "report.md"
+	if err := validateConfig(&cfg); err == nil || !strings.Contains(err.Error(), "requires --append") {
+		t.Errorf("Expected --max-output-size to require --append, got %v", err)
+	}
+
+	cfg = base
+	cfg.Append = true
+	cfg.Output = "s3://bucket/report.md"
+	if err := validateConfig(&cfg); err == nil || !strings.Contains(err.Error(), "local --output") {
+		t.Errorf("Expected --append to require a local file, got %v", err)
+	}
+
+	cfg = base
+	cfg.Append = true
+	cfg.Output = "report.md"
+	cfg.MaxOutputSize = "10MB"
+	if err := validateConfig(&cfg); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestValidateConfig_StrictProvider(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       config.Config
+		expectErr bool
+	}{
+		{name: "unknown provider is lenient interactively", cfg: config.Config{LLMProvider: "opnai"}},
+		{name: "unknown provider fails in strict mode", cfg: config.Config{LLMProvider: "opnai", StrictProvider: true}, expectErr: true},
+		{name: "unknown provider fails in CI", cfg: config.Config{LLMProvider: "opnai", CI: true}, expectErr: true},
+		{name: "known provider passes in strict mode", cfg: config.Config{LLMProvider: "ollama", StrictProvider: true}},
+		{name: "no provider passes in CI", cfg: config.Config{CI: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.GitHubToken = "token123"
+			cfg.Org = "test-org"
+
+			err := validateConfig(&cfg)
+			if tt.expectErr {
+				if err == nil || !strings.Contains(err.Error(), "unknown LLM provider 'opnai'") {
+					t.Errorf("Expected unknown provider error, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetConfig_NormalizesProvider(t *testing.T) {
+	t.Setenv("PRTOOL_LLM_PROVIDER", " OpenAI ")
+	originalCfgFile := cfgFile
+	cfgFile = filepath.Join(t.TempDir(), "missing.yaml")
+	t.Cleanup(func() { cfgFile = originalCfgFile })
+
+	cfg, err := GetConfig()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.LLMProvider != "openai" {
+		t.Errorf("Expected normalized provider, got %q", cfg.LLMProvider)
+	}
+}
+
+func TestGetConfig_OutputStdoutFormat(t *testing.T) {
+	originalCfgFile := cfgFile
+	cfgFile = filepath.Join(t.TempDir(), "missing.yaml")
+	t.Cleanup(func() { cfgFile = originalCfgFile })
+
+	originalFormat := outputStdoutFormat
+	t.Cleanup(func() { outputStdoutFormat = originalFormat })
+
+	// From the environment when the flag is unset
+	outputStdoutFormat = ""
+	t.Setenv("PRTOOL_OUTPUT_STDOUT_FORMAT", "table")
+	cfg, err := GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig() failed: %v", err)
+	}
+	if cfg.OutputStdoutFormat != "table" {
+		t.Errorf("Expected OutputStdoutFormat table from the environment, got %q", cfg.OutputStdoutFormat)
+	}
+
+	// The flag wins over the environment
+	outputStdoutFormat = "markdown"
+	cfg, err = GetConfig()
+	if err != nil {
+		t.Fatalf("GetConfig() failed: %v", err)
+	}
+	if cfg.OutputStdoutFormat != "markdown" {
+		t.Errorf("Expected OutputStdoutFormat markdown from the flag, got %q", cfg.OutputStdoutFormat)
+	}
+}
+
+func TestGetConfig_UserAgentPrecedence(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("user_agent: yaml-agent\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	originalCfgFile := cfgFile
+	cfgFile = configPath
+	t.Cleanup(func() { cfgFile = originalCfgFile })
+
+	originalUserAgent := userAgent
+	userAgent = ""
+	t.Cleanup(func() { userAgent = originalUserAgent })
+
+	tests := []struct {
+		name     string
+		env      string
+		flag     string
+		expected string
+	}{
+		{name: "yaml", expected: "yaml-agent"},
+		{name: "env over yaml", env: "env-agent", expected: "env-agent"},
+		{name: "flag over env", env: "env-agent", flag: "flag-agent", expected: "flag-agent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PRTOOL_USER_AGENT", tt.env)
+			userAgent = tt.flag
+
+			cfg, err := GetConfig()
+			if err != nil {
+				t.Fatalf("GetConfig() failed: %v", err)
+			}
+			if cfg.UserAgent != tt.expected {
+				t.Errorf("Expected UserAgent %q, got %q", tt.expected, cfg.UserAgent)
+			}
+		})
+	}
+}
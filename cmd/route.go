@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/willis7/prtool/internal/config"
+	"github.com/willis7/prtool/internal/logger"
+	"github.com/willis7/prtool/internal/model"
+	"github.com/willis7/prtool/internal/render"
+)
+
+// outputRoute writes the PRs carrying a label to an extra output
+type outputRoute struct {
+	Label string
+	Path  string
+}
+
+// parseRoute parses a --route rule of the form label=<name>:<path>. The path
+// may itself contain colons, e.g. an s3:// URL.
+func parseRoute(rule string) (outputRoute, error) {
+	key, rest, ok := strings.Cut(rule, "=")
+	if !ok || strings.TrimSpace(key) != "label" {
+		return outputRoute{}, fmt.Errorf("invalid route '%s' (expected label=<name>:<path>)", rule)
+	}
+
+	label, path, ok := strings.Cut(rest, ":")
+	label, path = strings.TrimSpace(label), strings.TrimSpace(path)
+	if !ok || label == "" || path == "" {
+		return outputRoute{}, fmt.Errorf("invalid route '%s' (expected label=<name>:<path>)", rule)
+	}
+
+	return outputRoute{Label: label, Path: path}, nil
+}
+
+// matches reports whether pr carries the route's label, ignoring case
+func (r outputRoute) matches(pr *model.PR) bool {
+	for _, label := range pr.Labels {
+		if strings.EqualFold(label, r.Label) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeRoutes renders the PRs matching each --route rule with the report
+// metadata and writes them to the rule's path, alongside the main output.
+// Routed reports have no AI summary, since it describes every PR. It returns
+// the routed outputs written.
+func writeRoutes(cfg *config.Config, log *logger.Logger, metadata render.Metadata, prs []*model.PR, scanned []string) ([]Artifact, error) {
+	var artifacts []Artifact
+	for _, rule := range cfg.Routes {
+		route, err := parseRoute(rule)
+		if err != nil {
+			return artifacts, err
+		}
+
+		var routed []*model.PR
+		for _, pr := range prs {
+			if route.matches(pr) {
+				routed = append(routed, pr)
+			}
+		}
+
+		routeMeta := generateMetadata(cfg, routed, scanned)
+		routeMeta.ReportID = metadata.ReportID
+		routeMeta.GeneratedAt = metadata.GeneratedAt
+		routeMeta.SinceTime, routeMeta.UntilTime = metadata.SinceTime, metadata.UntilTime
+		routeMeta.Partial = metadata.Partial
+		routeMeta.Filters = append(routeMeta.Filters, fmt.Sprintf("label=%s", route.Label))
+
+		content, err := renderReport(cfg, routeMeta, routed)
+		if err != nil {
+			return artifacts, fmt.Errorf("failed to render route %s: %w", rule, err)
+		}
+
+		routeCfg := *cfg
+		routeCfg.Output = route.Path
+		routeCfg.OutputStdoutFormat = ""
+		if err := writeOutput(&routeCfg, log, content, routed); err != nil {
+			return artifacts, fmt.Errorf("failed to write route %s: %w", rule, err)
+		}
+		artifacts = append(artifacts, outputArtifact(&routeCfg, route.Path, content))
+		log.Info("Routed %d PR(s) labeled %s to %s", len(routed), route.Label, route.Path)
+	}
+
+	return artifacts, nil
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/willis7/prtool/internal/config"
+	"github.com/willis7/prtool/internal/model"
+)
+
+func TestWriteRoutes(t *testing.T) {
+	dir := t.TempDir()
+	mainPath := filepath.Join(dir, "report.md")
+	securityPath := filepath.Join(dir, "security.md")
+
+	prs := []*model.PR{
+		{Title: "Patch token leak", Repository: "acme/api", Number: 1, Labels: []string{"Security"}},
+		{Title: "Add dark mode", Repository: "acme/web", Number: 2, Labels: []string{"feature"}},
+	}
+	cfg := &config.Config{Org: "acme", Output: mainPath, Routes: []string{"label=security:" + securityPath}}
+
+	metadata := generateMetadata(cfg, prs, nil)
+	metadata.Summary = "Everything this week."
+	content, err := renderReport(cfg, metadata, prs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := writeOutput(cfg, nil, content, prs); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := writeRoutes(cfg, nil, metadata, prs, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	main, err := os.ReadFile(mainPath)
+	if err != nil {
+		t.Fatalf("Failed to read main report: %v", err)
+	}
+	if !strings.Contains(string(main), "Patch token leak") || !strings.Contains(string(main), "Add dark mode") {
+		t.Errorf("Expected every PR in the main report, got:\n%s", main)
+	}
+
+	routed, err := os.ReadFile(securityPath)
+	if err != nil {
+		t.Fatalf("Failed to read routed report: %v", err)
+	}
+	report := string(routed)
+	if !strings.Contains(report, "Patch token leak") || strings.Contains(report, "Add dark mode") {
+		t.Errorf("Expected only the security PR in the routed report, got:\n%s", report)
+	}
+	for _, want := range []string{"- **Scope**: organization (acme)", "- **Total PRs**: 1", "label=security", "(report " + metadata.ReportID + ")"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected %q in the routed report, got:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "Everything this week.") {
+		t.Error("Expected the routed report not to reuse the full AI summary")
+	}
+}
+
+func TestParseRoute(t *testing.T) {
+	route, err := parseRoute("label=security:s3://bucket/security.md")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if route.Label != "security" || route.Path != "s3://bucket/security.md" {
+		t.Errorf("Unexpected route: %+v", route)
+	}
+
+	for _, rule := range []string{"security:out.md", "author=alice:out.md", "label=security", "label=:out.md", "label=security:"} {
+		if _, err := parseRoute(rule); err == nil {
+			t.Errorf("Expected error for route %q", rule)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSchemaCommand(t *testing.T) {
+	var stdout bytes.Buffer
+
+	cmd := &cobra.Command{Use: "prtool"}
+	cmd.AddCommand(schemaCmd)
+	cmd.SetOut(&stdout)
+	cmd.SetArgs([]string{"schema"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var schema struct {
+		Properties map[string]struct {
+			Items struct {
+				AnyOf []struct {
+					Properties map[string]json.RawMessage `json:"properties"`
+				} `json:"anyOf"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &schema); err != nil {
+		t.Fatalf("Schema output is not valid JSON: %v", err)
+	}
+
+	prItems := schema.Properties["pull_requests"].Items.AnyOf
+	if len(prItems) == 0 {
+		t.Fatal("Expected pull request items to have an object schema")
+	}
+	prFields := prItems[0].Properties
+	for _, field := range []string{"title", "author", "repository", "number", "merged_at", "html_url"} {
+		if _, ok := prFields[field]; !ok {
+			t.Errorf("Expected schema to list PR field %q", field)
+		}
+	}
+}
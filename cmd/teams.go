@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/spf13/cobra"
+	"github.com/willis7/prtool/internal/gh"
+)
+
+// teamsCmd represents the teams command
+var teamsCmd = &cobra.Command{
+	Use:   "teams",
+	Short: "List the teams of an organization",
+	Long: `List the teams of a GitHub organization with their slugs and names, to
+discover valid --team values. Only teams visible to the token are listed.`,
+	Example: "  prtool teams --org=myorg",
+	Args:    cobra.NoArgs,
+	RunE:    runTeams,
+}
+
+// newTeamsClient creates the GitHub client used by the teams command. Tests
+// replace it to avoid real API calls.
+var newTeamsClient = func(token string, opts ...gh.ClientOption) (gh.GitHubClient, error) {
+	return gh.NewRestClient(token, opts...)
+}
+
+func init() {
+	// Shares the --org value with the root command
+	teamsCmd.Flags().StringVar(&org, "org", "", "GitHub organization")
+	rootCmd.AddCommand(teamsCmd)
+}
+
+func runTeams(cmd *cobra.Command, args []string) error {
+	cfg, err := GetConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+	if cfg.GitHubToken == "" {
+		return fmt.Errorf("GitHub token is required")
+	}
+	if cfg.Org == "" {
+		return fmt.Errorf("--org is required")
+	}
+
+	ghClient, err := newTeamsClient(cfg.GitHubToken, githubClientOptions(cfg)...)
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+
+	teams, err := ghClient.ListTeams(cfg.Org)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprint(cmd.OutOrStdout(), formatTeams(cfg.Org, teams))
+	return err
+}
+
+// formatTeams renders teams as a table of --team values and names, sorted by
+// slug
+func formatTeams(org string, teams []*github.Team) string {
+	if len(teams) == 0 {
+		return fmt.Sprintf("No teams found in %s.\n", org)
+	}
+
+	sorted := append([]*github.Team(nil), teams...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetSlug() < sorted[j].GetSlug()
+	})
+
+	var sb strings.Builder
+	sb.WriteString("| Team | Name |\n")
+	sb.WriteString("|------|------|\n")
+	for _, team := range sorted {
+		sb.WriteString(fmt.Sprintf("| %s/%s | %s |\n", org, team.GetSlug(), team.GetName()))
+	}
+	sb.WriteString(fmt.Sprintf("\nTotal: %d team(s)\n", len(teams)))
+
+	return sb.String()
+}
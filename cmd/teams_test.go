@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/willis7/prtool/internal/gh"
+)
+
+func TestRunTeams(t *testing.T) {
+	mock := gh.NewMockClient()
+	mock.SetMockTeams("acme", []*github.Team{
+		{Slug: github.String("platform"), Name: github.String("Platform")},
+		{Slug: github.String("backend-core"), Name: github.String("Backend Core")},
+	})
+
+	original := newTeamsClient
+	newTeamsClient = func(token string, opts ...gh.ClientOption) (gh.GitHubClient, error) {
+		return mock, nil
+	}
+	t.Cleanup(func() { newTeamsClient = original })
+
+	t.Setenv("PRTOOL_GITHUB_TOKEN", "token123")
+	originalOrg := org
+	org = "acme"
+	t.Cleanup(func() { org = originalOrg })
+
+	var out bytes.Buffer
+	teamsCmd.SetOut(&out)
+	t.Cleanup(func() { teamsCmd.SetOut(nil) })
+
+	if err := runTeams(teamsCmd, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	output := out.String()
+	backend := strings.Index(output, "| acme/backend-core | Backend Core |")
+	platform := strings.Index(output, "| acme/platform | Platform |")
+	if backend < 0 || platform < 0 {
+		t.Fatalf("Expected team slugs and names in output, got:\n%s", output)
+	}
+	if backend > platform {
+		t.Errorf("Expected teams sorted by slug, got:\n%s", output)
+	}
+	if !strings.Contains(output, "Total: 2 team(s)") {
+		t.Errorf("Expected a team count, got:\n%s", output)
+	}
+}
+
+func TestFormatTeams_Empty(t *testing.T) {
+	if got := formatTeams("acme", nil); got != "No teams found in acme.\n" {
+		t.Errorf("Unexpected output: %q", got)
+	}
+}
@@ -0,0 +1,88 @@
+package clipboard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Clipboard copies text to the system clipboard. On a terminal it uses the
+// OSC 52 escape sequence, which also works over SSH; otherwise it falls back
+// to the platform clipboard command.
+type Clipboard struct {
+	out        io.Writer
+	isTerminal bool
+	fallback   func(text string) error
+}
+
+// New creates a clipboard that writes OSC 52 sequences to stderr when it is a terminal
+func New() *Clipboard {
+	return &Clipboard{
+		out:        os.Stderr,
+		isTerminal: IsTerminal(os.Stderr),
+		fallback:   platformCopy,
+	}
+}
+
+// Copy copies text to the clipboard
+func (c *Clipboard) Copy(text string) error {
+	if c.isTerminal {
+		if _, err := io.WriteString(c.out, OSC52(text)); err != nil {
+			return fmt.Errorf("failed to write OSC 52 sequence: %w", err)
+		}
+		return nil
+	}
+
+	return c.fallback(text)
+}
+
+// OSC52 returns the escape sequence asking the terminal to set the clipboard to text
+func OSC52(text string) string {
+	return "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+}
+
+// IsTerminal reports whether f is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// platformCopy pipes text into the platform's clipboard command
+func platformCopy(text string) error {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		candidates = [][]string{
+			{"wl-copy"},
+			{"xclip", "-selection", "clipboard"},
+			{"xsel", "--clipboard", "--input"},
+		}
+	}
+
+	for _, candidate := range candidates {
+		path, err := exec.LookPath(candidate[0])
+		if err != nil {
+			continue
+		}
+
+		cmd := exec.Command(path, candidate[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to run %s: %w", candidate[0], err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no clipboard command available on %s", runtime.GOOS)
+}
@@ -0,0 +1,81 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestOSC52(t *testing.T) {
+	seq := OSC52("hello")
+
+	if !strings.HasPrefix(seq, "\x1b]52;c;") {
+		t.Errorf("Expected OSC 52 prefix, got %q", seq)
+	}
+	if !strings.HasSuffix(seq, "\a") {
+		t.Errorf("Expected BEL terminator, got %q", seq)
+	}
+
+	encoded := strings.TrimSuffix(strings.TrimPrefix(seq, "\x1b]52;c;"), "\a")
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("Expected base64 payload: %v", err)
+	}
+	if string(decoded) != "hello" {
+		t.Errorf("Expected payload %q, got %q", "hello", decoded)
+	}
+}
+
+func TestClipboard_Copy(t *testing.T) {
+	tests := []struct {
+		name           string
+		isTerminal     bool
+		expectOSC52    bool
+		expectFallback bool
+	}{
+		{
+			name:        "terminal uses OSC 52",
+			isTerminal:  true,
+			expectOSC52: true,
+		},
+		{
+			name:           "non-terminal uses platform fallback",
+			isTerminal:     false,
+			expectFallback: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			var fallbackText string
+
+			c := &Clipboard{
+				out:        &out,
+				isTerminal: tt.isTerminal,
+				fallback: func(text string) error {
+					fallbackText = text
+					return nil
+				},
+			}
+
+			if err := c.Copy("# Report"); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tt.expectOSC52 && out.String() != OSC52("# Report") {
+				t.Errorf("Expected OSC 52 sequence, got %q", out.String())
+			}
+			if !tt.expectOSC52 && out.Len() != 0 {
+				t.Errorf("Expected no terminal output, got %q", out.String())
+			}
+			if tt.expectFallback && fallbackText != "# Report" {
+				t.Errorf("Expected fallback to receive report, got %q", fallbackText)
+			}
+			if !tt.expectFallback && fallbackText != "" {
+				t.Errorf("Expected fallback not to be used, got %q", fallbackText)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v2"
@@ -27,31 +28,239 @@ func (t *TeamList) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// HostProfile is a GitHub host, such as a GitHub Enterprise server, whose
+// orgs and repositories are fetched with their own client and token and
+// combined into the report
+type HostProfile struct {
+	// Name labels the host's PRs in the report, e.g. "ghe"
+	Name string `yaml:"name"`
+	// BaseURL is the host's API URL, e.g. https://ghe.example.com/api/v3/.
+	// Empty means github.com.
+	BaseURL string `yaml:"base_url"`
+	// Token authenticates against the host; empty uses github_token
+	Token string   `yaml:"token"`
+	Orgs  []string `yaml:"orgs"`
+	Repos []string `yaml:"repos"`
+}
+
 // Config represents the complete configuration for prtool
 type Config struct {
 	// GitHub configuration
 	GitHubToken string `yaml:"github_token" env:"PRTOOL_GITHUB_TOKEN"`
+	UserAgent   string `yaml:"user_agent" env:"PRTOOL_USER_AGENT"`
+	// GitHubHeaders are extra "key=value" headers sent on every GitHub request.
+	// PRTOOL_GITHUB_HEADERS separates them with newlines, since header values
+	// may contain commas.
+	GitHubHeaders []string `yaml:"github_headers" env:"PRTOOL_GITHUB_HEADERS"`
+	// FixtureDump records the raw GitHub API responses to this directory for replay tests
+	FixtureDump string `yaml:"fixture_dump" env:"PRTOOL_FIXTURE_DUMP"`
+	// GitHubTimeout bounds each GitHub request, e.g. 30s (0 disables it)
+	GitHubTimeout string `yaml:"github_timeout" env:"PRTOOL_GITHUB_TIMEOUT"`
 
 	// Scope configuration (mutually exclusive)
 	Org  string   `yaml:"org" env:"PRTOOL_ORG"`
 	Team TeamList `yaml:"team" env:"PRTOOL_TEAM"`
 	User string   `yaml:"user" env:"PRTOOL_USER"`
 	Repo string   `yaml:"repo" env:"PRTOOL_REPO"`
+	// Topic limits the scope to repositories tagged with this topic
+	Topic string `yaml:"topic" env:"PRTOOL_TOPIC"`
+	// RepoFilterExpr limits the scope to repositories matching a boolean
+	// expression over repo attributes, e.g. `topic == "backend" && !archived`
+	RepoFilterExpr string `yaml:"repo_filter_expr" env:"PRTOOL_REPO_FILTER_EXPR"`
+	// ExtraRepos are fetched in addition to the repositories resolved from the scope
+	ExtraRepos []string `yaml:"extra_repos" env:"PRTOOL_EXTRA_REPOS"`
+	// Hosts are further GitHub hosts whose orgs and repos are fetched
+	// alongside the scope above. They are only read from the config file.
+	Hosts []HostProfile `yaml:"hosts"`
+	// RepoLimit scans only the first N repositories resolved from the scope
+	// (0 means no limit). Extra repos are always scanned.
+	RepoLimit int `yaml:"repo_limit" env:"PRTOOL_REPO_LIMIT"`
+	// ScanOrder orders the resolved repositories before RepoLimit applies
+	// (pushed, alpha or stars; empty keeps GitHub's listing order)
+	ScanOrder string `yaml:"scan_order" env:"PRTOOL_SCAN_ORDER"`
+	// Concurrency is the maximum number of repositories fetched at once
+	// (0 means the default of 8)
+	Concurrency int `yaml:"concurrency" env:"PRTOOL_CONCURRENCY"`
+	// RepoCacheFile persists repository listings so runs within RepoCacheTTL
+	// (a duration, default 24h) skip listing the scope again. RefreshRepos
+	// lists the scope again regardless.
+	RepoCacheFile string `yaml:"repo_cache_file" env:"PRTOOL_REPO_CACHE_FILE"`
+	RepoCacheTTL  string `yaml:"repo_cache_ttl" env:"PRTOOL_REPO_CACHE_TTL"`
+	RefreshRepos  bool   `yaml:"refresh_repos" env:"PRTOOL_REFRESH_REPOS"`
+	// ConfirmThreshold is the resolved repository count above which an
+	// interactive run asks for confirmation before fetching (0 uses the default)
+	ConfirmThreshold int `yaml:"confirm_threshold" env:"PRTOOL_CONFIRM_THRESHOLD"`
 
 	// Time range
 	Since string `yaml:"since" env:"PRTOOL_SINCE"`
+	// Until bounds the window's upper end, as a relative duration or an
+	// absolute date (empty means now)
+	Until string `yaml:"until" env:"PRTOOL_UNTIL"`
+	// DateField is the PR timestamp the since window filters on (merged, closed, created)
+	DateField string `yaml:"date_field" env:"PRTOOL_DATE_FIELD"`
+	// LargeWindowDays is the since window size that triggers a warning (0 uses the default)
+	LargeWindowDays  int  `yaml:"large_window_days" env:"PRTOOL_LARGE_WINDOW_DAYS"`
+	AllowLargeWindow bool `yaml:"allow_large_window" env:"PRTOOL_ALLOW_LARGE_WINDOW"`
+
+	// PR processing
+	StripPRTemplate bool `yaml:"strip_pr_template" env:"PRTOOL_STRIP_PR_TEMPLATE"`
+	// IncludeDrafts keeps draft PRs, which are excluded by default
+	IncludeDrafts bool `yaml:"include_drafts" env:"PRTOOL_INCLUDE_DRAFTS"`
+	// IncludeFiles fetches the files changed by each PR
+	IncludeFiles bool `yaml:"include_files" env:"PRTOOL_INCLUDE_FILES"`
+	// IncludeCommits fetches the commits of each PR to capture the author's email
+	IncludeCommits bool `yaml:"include_commits" env:"PRTOOL_INCLUDE_COMMITS"`
+	// AuthorDomains keeps only PRs whose author email is in one of these
+	// domains. Needs IncludeCommits.
+	AuthorDomains []string `yaml:"author_domains" env:"PRTOOL_AUTHOR_DOMAINS"`
+	// Paths keeps only PRs changing a file matching one of these globs, and
+	// ExcludePaths drops PRs changing any matching file. Both need IncludeFiles.
+	Paths        []string `yaml:"paths" env:"PRTOOL_PATHS"`
+	ExcludePaths []string `yaml:"exclude_paths" env:"PRTOOL_EXCLUDE_PATHS"`
+	// MinChanges drops PRs with fewer changed lines (additions plus
+	// deletions). Line counts are fetched per PR when it is set.
+	MinChanges int `yaml:"min_changes" env:"PRTOOL_MIN_CHANGES"`
+	// LabelsAny keeps only PRs with at least one of these labels, LabelsAll
+	// only PRs with all of them, and ExcludeLabels drops PRs with any of
+	// them. Labels match case-insensitively after LabelAliases apply.
+	LabelsAny     []string `yaml:"labels_any" env:"PRTOOL_LABELS_ANY"`
+	LabelsAll     []string `yaml:"labels_all" env:"PRTOOL_LABELS_ALL"`
+	ExcludeLabels []string `yaml:"exclude_labels" env:"PRTOOL_EXCLUDE_LABELS"`
+	// Grep keeps only PRs whose title or body contains one of these terms
+	// (ignoring case) or matches one of the GrepRegex expressions
+	Grep      []string `yaml:"grep" env:"PRTOOL_GREP"`
+	GrepRegex []string `yaml:"grep_regex" env:"PRTOOL_GREP_REGEX"`
+	// LabelAliases maps label names (matched case-insensitively) to canonical names
+	LabelAliases map[string]string `yaml:"label_aliases" env:"PRTOOL_LABEL_ALIASES"`
 
 	// LLM configuration
 	LLMProvider string `yaml:"llm_provider" env:"PRTOOL_LLM_PROVIDER"`
-	LLMAPIKey   string `yaml:"llm_api_key" env:"PRTOOL_LLM_API_KEY"`
-	LLMModel    string `yaml:"llm_model" env:"PRTOOL_LLM_MODEL"`
-	Prompt      string `yaml:"prompt" env:"PRTOOL_PROMPT"`
+	// ContextFields limits the PR attributes included in the LLM context
+	// (title, author, repository, merged, labels, body, files; default all)
+	ContextFields []string `yaml:"context_fields" env:"PRTOOL_CONTEXT_FIELDS"`
+	// WeightBy annotates each PR in the LLM context with a relative importance
+	// derived from its size or labels
+	WeightBy string `yaml:"weight_by" env:"PRTOOL_WEIGHT_BY"`
+	// StrictProvider rejects unknown LLM providers instead of using the stub
+	StrictProvider bool   `yaml:"strict_provider" env:"PRTOOL_STRICT_PROVIDER"`
+	LLMAPIKey      string `yaml:"llm_api_key" env:"PRTOOL_LLM_API_KEY"`
+	LLMModel       string `yaml:"llm_model" env:"PRTOOL_LLM_MODEL"`
+	Prompt         string `yaml:"prompt" env:"PRTOOL_PROMPT"`
+	// SummaryModel overrides LLMModel for the final summary call, so chunk
+	// summaries can use a cheaper model
+	SummaryModel string `yaml:"summary_model" env:"PRTOOL_SUMMARY_MODEL"`
+	// SummaryStyle selects prose (default) or bullets for the AI summary
+	SummaryStyle string `yaml:"summary_style" env:"PRTOOL_SUMMARY_STYLE"`
+	// PerAuthorSummary groups the details by author with an AI summary of
+	// each author's PRs
+	PerAuthorSummary bool `yaml:"per_author_summary" env:"PRTOOL_PER_AUTHOR_SUMMARY"`
+	// Anonymize replaces author logins with pseudonyms such as "Contributor A"
+	Anonymize bool `yaml:"anonymize" env:"PRTOOL_ANONYMIZE"`
+	// StripURLs removes PR links from an anonymized report
+	StripURLs bool `yaml:"strip_urls" env:"PRTOOL_STRIP_URLS"`
+	// MaxLLMTokens caps the estimated size of the LLM context (0 means unlimited)
+	MaxLLMTokens int `yaml:"max_llm_tokens" env:"PRTOOL_MAX_LLM_TOKENS"`
+	// LLMChunkTokens splits the PRs into chunks of this many estimated tokens,
+	// summarised separately and then combined (0 disables chunking)
+	LLMChunkTokens int `yaml:"llm_chunk_tokens" env:"PRTOOL_LLM_CHUNK_TOKENS"`
+	// LenientChunks summarises the successful chunks when some chunk calls
+	// fail, instead of failing the whole summary
+	LenientChunks bool `yaml:"lenient_chunks" env:"PRTOOL_LENIENT_CHUNKS"`
+	// LLMTemperature and LLMTopP are the sampling settings of summary
+	// requests, e.g. "0.2" (empty uses the defaults), and LLMMaxTokens caps
+	// the length of each summary (0 uses the provider default)
+	LLMTemperature string `yaml:"llm_temperature" env:"PRTOOL_LLM_TEMPERATURE"`
+	LLMTopP        string `yaml:"llm_top_p" env:"PRTOOL_LLM_TOP_P"`
+	LLMMaxTokens   int    `yaml:"llm_max_tokens" env:"PRTOOL_LLM_MAX_TOKENS"`
+	// Estimate prints the estimated LLM tokens and cost instead of calling the LLM
+	Estimate bool `yaml:"estimate" env:"PRTOOL_ESTIMATE"`
+	// LLMPricePer1K is the price in US dollars per 1,000 input tokens used by
+	// Estimate, e.g. "0.0025" (empty uses the provider's default)
+	LLMPricePer1K string `yaml:"llm_price_per_1k" env:"PRTOOL_LLM_PRICE_PER_1K"`
+	// MinSummaryChars retries a summary shorter than this many characters
+	// once with a stricter prompt (0 disables the check)
+	MinSummaryChars int `yaml:"min_summary_chars" env:"PRTOOL_MIN_SUMMARY_CHARS"`
+	// LLMConcurrency is the maximum number of chunk summaries requested at once
+	LLMConcurrency int `yaml:"llm_concurrency" env:"PRTOOL_LLM_CONCURRENCY"`
+	// NoSummaryCache bypasses the on-disk cache of AI summaries
+	NoSummaryCache bool `yaml:"no_summary_cache" env:"PRTOOL_NO_SUMMARY_CACHE"`
 
 	// Output configuration
-	Output  string `yaml:"output" env:"PRTOOL_OUTPUT"`
-	DryRun  bool   `yaml:"dry_run" env:"PRTOOL_DRY_RUN"`
-	Verbose bool   `yaml:"verbose" env:"PRTOOL_VERBOSE"`
-	CI      bool   `yaml:"ci" env:"PRTOOL_CI"`
+	Output string `yaml:"output" env:"PRTOOL_OUTPUT"`
+	Format string `yaml:"format" env:"PRTOOL_FORMAT"`
+	// Template selects <TemplateDir>/<Template>.tmpl to render the report
+	TemplateDir string `yaml:"template_dir" env:"PRTOOL_TEMPLATE_DIR"`
+	Template    string `yaml:"template" env:"PRTOOL_TEMPLATE"`
+	// JSONCompact emits single-line JSON for the json format
+	JSONCompact bool `yaml:"json_compact" env:"PRTOOL_JSON_COMPACT"`
+	// Badge format settings (zero values use the defaults)
+	BadgeLabel    string `yaml:"badge_label" env:"PRTOOL_BADGE_LABEL"`
+	BadgeYellowAt int    `yaml:"badge_yellow_at" env:"PRTOOL_BADGE_YELLOW_AT"`
+	BadgeGreenAt  int    `yaml:"badge_green_at" env:"PRTOOL_BADGE_GREEN_AT"`
+	// OutputStdoutFormat selects what is printed to stdout when Output is a file
+	OutputStdoutFormat string `yaml:"output_stdout_format" env:"PRTOOL_OUTPUT_STDOUT_FORMAT"`
+	// ReportID identifies the report in its metadata and footer (generated
+	// when empty)
+	ReportID string `yaml:"report_id" env:"PRTOOL_REPORT_ID"`
+	// Routes write the PRs with a label to extra outputs, as label=<name>:<path>
+	Routes []string `yaml:"routes" env:"PRTOOL_ROUTES"`
+	// Append appends the report to the output file instead of replacing it
+	Append bool `yaml:"append" env:"PRTOOL_APPEND"`
+	// MaxOutputSize rotates the output file before an append would grow it
+	// past this size, e.g. "10MB" (empty means no limit)
+	MaxOutputSize string `yaml:"max_output_size" env:"PRTOOL_MAX_OUTPUT_SIZE"`
+	// OutputMode is the octal permission mode of created output files (default 0644)
+	OutputMode string `yaml:"output_mode" env:"PRTOOL_OUTPUT_MODE"`
+	// LineEnding is the newline style of the written output (lf or crlf)
+	LineEnding string `yaml:"line_ending" env:"PRTOOL_LINE_ENDING"`
+	// Manifest is a file listing every output written by the run as JSON
+	Manifest string `yaml:"manifest" env:"PRTOOL_MANIFEST"`
+	// NotifyOnError is a webhook URL that gets a JSON notification when a
+	// run fails
+	NotifyOnError string `yaml:"notify_on_error" env:"PRTOOL_NOTIFY_ON_ERROR"`
+	// CheckOnly compares the report with this existing file instead of
+	// writing it, failing when they differ
+	CheckOnly string `yaml:"check_only" env:"PRTOOL_CHECK_ONLY"`
+	// Force rewrites the output file even when its content is unchanged
+	Force     bool `yaml:"force" env:"PRTOOL_FORCE"`
+	Clipboard bool `yaml:"clipboard" env:"PRTOOL_CLIPBOARD"`
+	// GroupBy groups the PR details (repo, author, label, week, milestone, base, topic, language, none)
+	GroupBy string `yaml:"group_by" env:"PRTOOL_GROUP_BY"`
+	// MinLabelCount hides labels used by fewer PRs from the label breakdown
+	MinLabelCount int `yaml:"min_label_count" env:"PRTOOL_MIN_LABEL_COUNT"`
+	// RepoOrder orders repo groups by name (alpha) or PR count (activity)
+	RepoOrder string `yaml:"repo_order" env:"PRTOOL_REPO_ORDER"`
+	// RepoDescriptions shows each repository's description under its group
+	// heading when grouping by repo
+	RepoDescriptions bool `yaml:"repo_descriptions" env:"PRTOOL_REPO_DESCRIPTIONS"`
+	// HeadingOffset shifts the Markdown report headings down this many levels
+	HeadingOffset int `yaml:"heading_offset" env:"PRTOOL_HEADING_OFFSET"`
+	// NoDetailsBody renders the PR details as one-line entries without descriptions
+	NoDetailsBody bool `yaml:"no_details_body" env:"PRTOOL_NO_DETAILS_BODY"`
+	// LinkStyle writes PR links inline or as numbered references
+	LinkStyle string `yaml:"link_style" env:"PRTOOL_LINK_STYLE"`
+	// StatusIcons prefixes each PR in the details with a state indicator
+	StatusIcons bool `yaml:"status_icons" env:"PRTOOL_STATUS_ICONS"`
+	// DetailsFormat renders each PR in the details as one line from a format
+	// string with {number}, {title}, {author}, {repo}, {merged}, {url}, {labels}
+	DetailsFormat string `yaml:"details_format" env:"PRTOOL_DETAILS_FORMAT"`
+	// DedupeBy collapses PRs with matching titles across repositories (title)
+	DedupeBy string `yaml:"dedupe_by" env:"PRTOOL_DEDUPE_BY"`
+	// ShowEmptySections keeps groups without PRs in the report. By default
+	// they are pruned.
+	ShowEmptySections bool `yaml:"show_empty_sections" env:"PRTOOL_SHOW_EMPTY_SECTIONS"`
+	// IncludeReposWithoutPRs lists every scanned repository in the report
+	// metadata, marking those without PRs. By default only repos with PRs are listed.
+	IncludeReposWithoutPRs bool `yaml:"include_repos_without_prs" env:"PRTOOL_INCLUDE_REPOS_WITHOUT_PRS"`
+	// MergeTarget groups the PR details by the branch each PR was merged into
+	MergeTarget bool `yaml:"merge_target" env:"PRTOOL_MERGE_TARGET"`
+	// FacetTopics groups the PR details by repository topic
+	FacetTopics bool `yaml:"facet_topics" env:"PRTOOL_FACET_TOPICS"`
+	DryRun      bool `yaml:"dry_run" env:"PRTOOL_DRY_RUN"`
+	// Plan prints the resolved repositories and query windows without fetching PRs
+	Plan    bool `yaml:"plan" env:"PRTOOL_PLAN"`
+	Verbose bool `yaml:"verbose" env:"PRTOOL_VERBOSE"`
+	CI      bool `yaml:"ci" env:"PRTOOL_CI"`
 
 	// Logging
 	LogFile string `yaml:"log_file" env:"PRTOOL_LOG_FILE"`
@@ -63,13 +272,9 @@ func LoadFromFile(path string) (*Config, error) {
 		return &Config{}, nil
 	}
 
-	// Expand home directory if needed
-	if !filepath.IsAbs(path) && len(path) > 0 && path[0] == '~' {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
-		}
-		path = filepath.Join(home, path[1:])
+	path, err := ExpandHome(path)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check if file exists
@@ -87,9 +292,54 @@ func LoadFromFile(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse YAML config file %s: %w", path, err)
 	}
 
+	// Catch conflicting scopes here so the error points at the file
+	if scopes := config.Scopes(); len(scopes) > 1 {
+		return nil, fmt.Errorf("config file %s sets %s (only one scope is allowed)", path, describeScopes(scopes))
+	}
+
 	return &config, nil
 }
 
+// ExpandHome replaces a leading ~ in path with the user's home directory
+func ExpandHome(path string) (string, error) {
+	if filepath.IsAbs(path) || len(path) == 0 || path[0] != '~' {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// Scopes returns the names of the scopes set in the configuration, in the
+// order org, team, user, repo
+func (c *Config) Scopes() []string {
+	var scopes []string
+	if c.Org != "" {
+		scopes = append(scopes, "org")
+	}
+	if len(c.Team) > 0 {
+		scopes = append(scopes, "team")
+	}
+	if c.User != "" {
+		scopes = append(scopes, "user")
+	}
+	if c.Repo != "" {
+		scopes = append(scopes, "repo")
+	}
+	return scopes
+}
+
+// describeScopes formats conflicting scopes as "both org and user" or
+// "org, team and user"
+func describeScopes(scopes []string) string {
+	if len(scopes) == 2 {
+		return fmt.Sprintf("both %s and %s", scopes[0], scopes[1])
+	}
+	return strings.Join(scopes[:len(scopes)-1], ", ") + " and " + scopes[len(scopes)-1]
+}
+
 // LoadFromEnv loads configuration from environment variables
 func LoadFromEnv() *Config {
 	teamEnv := os.Getenv("PRTOOL_TEAM")
@@ -99,21 +349,106 @@ func LoadFromEnv() *Config {
 	}
 
 	config := &Config{
-		GitHubToken: os.Getenv("PRTOOL_GITHUB_TOKEN"),
-		Org:         os.Getenv("PRTOOL_ORG"),
-		Team:        teams,
-		User:        os.Getenv("PRTOOL_USER"),
-		Repo:        os.Getenv("PRTOOL_REPO"),
-		Since:       os.Getenv("PRTOOL_SINCE"),
-		LLMProvider: os.Getenv("PRTOOL_LLM_PROVIDER"),
-		LLMAPIKey:   os.Getenv("PRTOOL_LLM_API_KEY"),
-		LLMModel:    os.Getenv("PRTOOL_LLM_MODEL"),
-		Prompt:      os.Getenv("PRTOOL_PROMPT"),
-		Output:      os.Getenv("PRTOOL_OUTPUT"),
-		DryRun:      os.Getenv("PRTOOL_DRY_RUN") == "true",
-		Verbose:     os.Getenv("PRTOOL_VERBOSE") == "true",
-		CI:          os.Getenv("PRTOOL_CI") == "true",
-		LogFile:     os.Getenv("PRTOOL_LOG_FILE"),
+		GitHubToken:            os.Getenv("PRTOOL_GITHUB_TOKEN"),
+		UserAgent:              os.Getenv("PRTOOL_USER_AGENT"),
+		GitHubHeaders:          envLines("PRTOOL_GITHUB_HEADERS"),
+		FixtureDump:            os.Getenv("PRTOOL_FIXTURE_DUMP"),
+		GitHubTimeout:          os.Getenv("PRTOOL_GITHUB_TIMEOUT"),
+		ConfirmThreshold:       envInt("PRTOOL_CONFIRM_THRESHOLD"),
+		Org:                    os.Getenv("PRTOOL_ORG"),
+		Team:                   teams,
+		User:                   os.Getenv("PRTOOL_USER"),
+		Repo:                   os.Getenv("PRTOOL_REPO"),
+		Topic:                  os.Getenv("PRTOOL_TOPIC"),
+		RepoFilterExpr:         os.Getenv("PRTOOL_REPO_FILTER_EXPR"),
+		ExtraRepos:             envList("PRTOOL_EXTRA_REPOS"),
+		RepoLimit:              envInt("PRTOOL_REPO_LIMIT"),
+		Concurrency:            envInt("PRTOOL_CONCURRENCY"),
+		ScanOrder:              os.Getenv("PRTOOL_SCAN_ORDER"),
+		RepoCacheFile:          os.Getenv("PRTOOL_REPO_CACHE_FILE"),
+		RepoCacheTTL:           os.Getenv("PRTOOL_REPO_CACHE_TTL"),
+		RefreshRepos:           os.Getenv("PRTOOL_REFRESH_REPOS") == "true",
+		Since:                  os.Getenv("PRTOOL_SINCE"),
+		Until:                  os.Getenv("PRTOOL_UNTIL"),
+		DateField:              os.Getenv("PRTOOL_DATE_FIELD"),
+		LargeWindowDays:        envInt("PRTOOL_LARGE_WINDOW_DAYS"),
+		AllowLargeWindow:       os.Getenv("PRTOOL_ALLOW_LARGE_WINDOW") == "true",
+		StripPRTemplate:        os.Getenv("PRTOOL_STRIP_PR_TEMPLATE") == "true",
+		IncludeDrafts:          os.Getenv("PRTOOL_INCLUDE_DRAFTS") == "true",
+		IncludeFiles:           os.Getenv("PRTOOL_INCLUDE_FILES") == "true",
+		Paths:                  envList("PRTOOL_PATHS"),
+		ExcludePaths:           envList("PRTOOL_EXCLUDE_PATHS"),
+		MinChanges:             envInt("PRTOOL_MIN_CHANGES"),
+		LabelsAny:              envList("PRTOOL_LABELS_ANY"),
+		LabelsAll:              envList("PRTOOL_LABELS_ALL"),
+		ExcludeLabels:          envList("PRTOOL_EXCLUDE_LABELS"),
+		IncludeCommits:         os.Getenv("PRTOOL_INCLUDE_COMMITS") == "true",
+		AuthorDomains:          envList("PRTOOL_AUTHOR_DOMAINS"),
+		Grep:                   envList("PRTOOL_GREP"),
+		GrepRegex:              envList("PRTOOL_GREP_REGEX"),
+		LabelAliases:           envMap("PRTOOL_LABEL_ALIASES"),
+		LLMProvider:            os.Getenv("PRTOOL_LLM_PROVIDER"),
+		StrictProvider:         os.Getenv("PRTOOL_STRICT_PROVIDER") == "true",
+		ContextFields:          envList("PRTOOL_CONTEXT_FIELDS"),
+		WeightBy:               os.Getenv("PRTOOL_WEIGHT_BY"),
+		LLMAPIKey:              os.Getenv("PRTOOL_LLM_API_KEY"),
+		LLMModel:               os.Getenv("PRTOOL_LLM_MODEL"),
+		SummaryModel:           os.Getenv("PRTOOL_SUMMARY_MODEL"),
+		Prompt:                 os.Getenv("PRTOOL_PROMPT"),
+		SummaryStyle:           os.Getenv("PRTOOL_SUMMARY_STYLE"),
+		PerAuthorSummary:       os.Getenv("PRTOOL_PER_AUTHOR_SUMMARY") == "true",
+		Anonymize:              os.Getenv("PRTOOL_ANONYMIZE") == "true",
+		StripURLs:              os.Getenv("PRTOOL_STRIP_URLS") == "true",
+		MaxLLMTokens:           envInt("PRTOOL_MAX_LLM_TOKENS"),
+		LLMChunkTokens:         envInt("PRTOOL_LLM_CHUNK_TOKENS"),
+		MinSummaryChars:        envInt("PRTOOL_MIN_SUMMARY_CHARS"),
+		LenientChunks:          os.Getenv("PRTOOL_LENIENT_CHUNKS") == "true",
+		LLMTemperature:         os.Getenv("PRTOOL_LLM_TEMPERATURE"),
+		LLMTopP:                os.Getenv("PRTOOL_LLM_TOP_P"),
+		LLMMaxTokens:           envInt("PRTOOL_LLM_MAX_TOKENS"),
+		Estimate:               os.Getenv("PRTOOL_ESTIMATE") == "true",
+		LLMPricePer1K:          os.Getenv("PRTOOL_LLM_PRICE_PER_1K"),
+		LLMConcurrency:         envInt("PRTOOL_LLM_CONCURRENCY"),
+		NoSummaryCache:         os.Getenv("PRTOOL_NO_SUMMARY_CACHE") == "true",
+		Output:                 os.Getenv("PRTOOL_OUTPUT"),
+		OutputStdoutFormat:     os.Getenv("PRTOOL_OUTPUT_STDOUT_FORMAT"),
+		Force:                  os.Getenv("PRTOOL_FORCE") == "true",
+		Append:                 os.Getenv("PRTOOL_APPEND") == "true",
+		Routes:                 envList("PRTOOL_ROUTES"),
+		MaxOutputSize:          os.Getenv("PRTOOL_MAX_OUTPUT_SIZE"),
+		OutputMode:             os.Getenv("PRTOOL_OUTPUT_MODE"),
+		LineEnding:             os.Getenv("PRTOOL_LINE_ENDING"),
+		Manifest:               os.Getenv("PRTOOL_MANIFEST"),
+		NotifyOnError:          os.Getenv("PRTOOL_NOTIFY_ON_ERROR"),
+		CheckOnly:              os.Getenv("PRTOOL_CHECK_ONLY"),
+		ReportID:               os.Getenv("PRTOOL_REPORT_ID"),
+		Clipboard:              os.Getenv("PRTOOL_CLIPBOARD") == "true",
+		GroupBy:                os.Getenv("PRTOOL_GROUP_BY"),
+		MinLabelCount:          envInt("PRTOOL_MIN_LABEL_COUNT"),
+		RepoOrder:              os.Getenv("PRTOOL_REPO_ORDER"),
+		RepoDescriptions:       os.Getenv("PRTOOL_REPO_DESCRIPTIONS") == "true",
+		HeadingOffset:          envInt("PRTOOL_HEADING_OFFSET"),
+		NoDetailsBody:          os.Getenv("PRTOOL_NO_DETAILS_BODY") == "true",
+		ShowEmptySections:      os.Getenv("PRTOOL_SHOW_EMPTY_SECTIONS") == "true",
+		LinkStyle:              os.Getenv("PRTOOL_LINK_STYLE"),
+		StatusIcons:            os.Getenv("PRTOOL_STATUS_ICONS") == "true",
+		DetailsFormat:          os.Getenv("PRTOOL_DETAILS_FORMAT"),
+		DedupeBy:               os.Getenv("PRTOOL_DEDUPE_BY"),
+		IncludeReposWithoutPRs: os.Getenv("PRTOOL_INCLUDE_REPOS_WITHOUT_PRS") == "true",
+		MergeTarget:            os.Getenv("PRTOOL_MERGE_TARGET") == "true",
+		FacetTopics:            os.Getenv("PRTOOL_FACET_TOPICS") == "true",
+		Format:                 os.Getenv("PRTOOL_FORMAT"),
+		TemplateDir:            os.Getenv("PRTOOL_TEMPLATE_DIR"),
+		Template:               os.Getenv("PRTOOL_TEMPLATE"),
+		JSONCompact:            os.Getenv("PRTOOL_JSON_COMPACT") == "true",
+		BadgeLabel:             os.Getenv("PRTOOL_BADGE_LABEL"),
+		BadgeYellowAt:          envInt("PRTOOL_BADGE_YELLOW_AT"),
+		BadgeGreenAt:           envInt("PRTOOL_BADGE_GREEN_AT"),
+		DryRun:                 os.Getenv("PRTOOL_DRY_RUN") == "true",
+		Plan:                   os.Getenv("PRTOOL_PLAN") == "true",
+		Verbose:                os.Getenv("PRTOOL_VERBOSE") == "true",
+		CI:                     os.Getenv("PRTOOL_CI") == "true",
+		LogFile:                os.Getenv("PRTOOL_LOG_FILE"),
 	}
 
 	return config
@@ -135,25 +470,113 @@ func MergeConfig(cliConfig, envConfig, yamlConfig *Config) *Config {
 
 	// GitHub configuration
 	merged.GitHubToken = firstNonEmpty(cliConfig.GitHubToken, envConfig.GitHubToken, yamlConfig.GitHubToken)
+	merged.UserAgent = firstNonEmpty(cliConfig.UserAgent, envConfig.UserAgent, yamlConfig.UserAgent)
+	merged.GitHubHeaders = firstNonEmptySlice(cliConfig.GitHubHeaders, envConfig.GitHubHeaders, yamlConfig.GitHubHeaders)
+	merged.FixtureDump = firstNonEmpty(cliConfig.FixtureDump, envConfig.FixtureDump, yamlConfig.FixtureDump)
+	merged.GitHubTimeout = firstNonEmpty(cliConfig.GitHubTimeout, envConfig.GitHubTimeout, yamlConfig.GitHubTimeout)
+	merged.ConfirmThreshold = firstNonZero(cliConfig.ConfirmThreshold, envConfig.ConfirmThreshold, yamlConfig.ConfirmThreshold)
 
 	// Scope configuration
 	merged.Org = firstNonEmpty(cliConfig.Org, envConfig.Org, yamlConfig.Org)
 	merged.Team = firstNonEmptySlice(cliConfig.Team, envConfig.Team, yamlConfig.Team)
 	merged.User = firstNonEmpty(cliConfig.User, envConfig.User, yamlConfig.User)
 	merged.Repo = firstNonEmpty(cliConfig.Repo, envConfig.Repo, yamlConfig.Repo)
+	merged.Topic = firstNonEmpty(cliConfig.Topic, envConfig.Topic, yamlConfig.Topic)
+	merged.RepoFilterExpr = firstNonEmpty(cliConfig.RepoFilterExpr, envConfig.RepoFilterExpr, yamlConfig.RepoFilterExpr)
+	merged.ExtraRepos = firstNonEmptySlice(cliConfig.ExtraRepos, envConfig.ExtraRepos, yamlConfig.ExtraRepos)
+	merged.RepoLimit = firstNonZero(cliConfig.RepoLimit, envConfig.RepoLimit, yamlConfig.RepoLimit)
+	merged.Concurrency = firstNonZero(cliConfig.Concurrency, envConfig.Concurrency, yamlConfig.Concurrency)
+	merged.ScanOrder = firstNonEmpty(cliConfig.ScanOrder, envConfig.ScanOrder, yamlConfig.ScanOrder)
+	merged.RepoCacheFile = firstNonEmpty(cliConfig.RepoCacheFile, envConfig.RepoCacheFile, yamlConfig.RepoCacheFile)
+	merged.RepoCacheTTL = firstNonEmpty(cliConfig.RepoCacheTTL, envConfig.RepoCacheTTL, yamlConfig.RepoCacheTTL)
+	merged.RefreshRepos = firstBool(cliConfig.RefreshRepos, envConfig.RefreshRepos, yamlConfig.RefreshRepos)
 
 	// Time range
 	merged.Since = firstNonEmpty(cliConfig.Since, envConfig.Since, yamlConfig.Since)
+	merged.Until = firstNonEmpty(cliConfig.Until, envConfig.Until, yamlConfig.Until)
+	merged.DateField = firstNonEmpty(cliConfig.DateField, envConfig.DateField, yamlConfig.DateField)
+	merged.LargeWindowDays = firstNonZero(cliConfig.LargeWindowDays, envConfig.LargeWindowDays, yamlConfig.LargeWindowDays)
+	merged.AllowLargeWindow = firstBool(cliConfig.AllowLargeWindow, envConfig.AllowLargeWindow, yamlConfig.AllowLargeWindow)
+
+	// PR processing
+	merged.StripPRTemplate = firstBool(cliConfig.StripPRTemplate, envConfig.StripPRTemplate, yamlConfig.StripPRTemplate)
+	merged.IncludeDrafts = firstBool(cliConfig.IncludeDrafts, envConfig.IncludeDrafts, yamlConfig.IncludeDrafts)
+	merged.IncludeFiles = firstBool(cliConfig.IncludeFiles, envConfig.IncludeFiles, yamlConfig.IncludeFiles)
+	merged.Paths = firstNonEmptySlice(cliConfig.Paths, envConfig.Paths, yamlConfig.Paths)
+	merged.ExcludePaths = firstNonEmptySlice(cliConfig.ExcludePaths, envConfig.ExcludePaths, yamlConfig.ExcludePaths)
+	merged.MinChanges = firstNonZero(cliConfig.MinChanges, envConfig.MinChanges, yamlConfig.MinChanges)
+	merged.LabelsAny = firstNonEmptySlice(cliConfig.LabelsAny, envConfig.LabelsAny, yamlConfig.LabelsAny)
+	merged.LabelsAll = firstNonEmptySlice(cliConfig.LabelsAll, envConfig.LabelsAll, yamlConfig.LabelsAll)
+	merged.ExcludeLabels = firstNonEmptySlice(cliConfig.ExcludeLabels, envConfig.ExcludeLabels, yamlConfig.ExcludeLabels)
+	merged.IncludeCommits = firstBool(cliConfig.IncludeCommits, envConfig.IncludeCommits, yamlConfig.IncludeCommits)
+	merged.AuthorDomains = firstNonEmptySlice(cliConfig.AuthorDomains, envConfig.AuthorDomains, yamlConfig.AuthorDomains)
+	merged.Grep = firstNonEmptySlice(cliConfig.Grep, envConfig.Grep, yamlConfig.Grep)
+	merged.GrepRegex = firstNonEmptySlice(cliConfig.GrepRegex, envConfig.GrepRegex, yamlConfig.GrepRegex)
+	merged.Hosts = yamlConfig.Hosts
+	merged.LabelAliases = firstNonEmptyMap(cliConfig.LabelAliases, envConfig.LabelAliases, yamlConfig.LabelAliases)
 
 	// LLM configuration
 	merged.LLMProvider = firstNonEmpty(cliConfig.LLMProvider, envConfig.LLMProvider, yamlConfig.LLMProvider)
+	merged.ContextFields = firstNonEmptySlice(cliConfig.ContextFields, envConfig.ContextFields, yamlConfig.ContextFields)
+	merged.WeightBy = firstNonEmpty(cliConfig.WeightBy, envConfig.WeightBy, yamlConfig.WeightBy)
+	merged.StrictProvider = firstBool(cliConfig.StrictProvider, envConfig.StrictProvider, yamlConfig.StrictProvider)
 	merged.LLMAPIKey = firstNonEmpty(cliConfig.LLMAPIKey, envConfig.LLMAPIKey, yamlConfig.LLMAPIKey)
 	merged.LLMModel = firstNonEmpty(cliConfig.LLMModel, envConfig.LLMModel, yamlConfig.LLMModel)
+	merged.SummaryModel = firstNonEmpty(cliConfig.SummaryModel, envConfig.SummaryModel, yamlConfig.SummaryModel)
 	merged.Prompt = firstNonEmpty(cliConfig.Prompt, envConfig.Prompt, yamlConfig.Prompt)
+	merged.SummaryStyle = firstNonEmpty(cliConfig.SummaryStyle, envConfig.SummaryStyle, yamlConfig.SummaryStyle)
+	merged.PerAuthorSummary = firstBool(cliConfig.PerAuthorSummary, envConfig.PerAuthorSummary, yamlConfig.PerAuthorSummary)
+	merged.Anonymize = firstBool(cliConfig.Anonymize, envConfig.Anonymize, yamlConfig.Anonymize)
+	merged.StripURLs = firstBool(cliConfig.StripURLs, envConfig.StripURLs, yamlConfig.StripURLs)
+	merged.MaxLLMTokens = firstNonZero(cliConfig.MaxLLMTokens, envConfig.MaxLLMTokens, yamlConfig.MaxLLMTokens)
+	merged.LLMChunkTokens = firstNonZero(cliConfig.LLMChunkTokens, envConfig.LLMChunkTokens, yamlConfig.LLMChunkTokens)
+	merged.LenientChunks = firstBool(cliConfig.LenientChunks, envConfig.LenientChunks, yamlConfig.LenientChunks)
+	merged.LLMTemperature = firstNonEmpty(cliConfig.LLMTemperature, envConfig.LLMTemperature, yamlConfig.LLMTemperature)
+	merged.LLMTopP = firstNonEmpty(cliConfig.LLMTopP, envConfig.LLMTopP, yamlConfig.LLMTopP)
+	merged.LLMMaxTokens = firstNonZero(cliConfig.LLMMaxTokens, envConfig.LLMMaxTokens, yamlConfig.LLMMaxTokens)
+	merged.Estimate = firstBool(cliConfig.Estimate, envConfig.Estimate, yamlConfig.Estimate)
+	merged.LLMPricePer1K = firstNonEmpty(cliConfig.LLMPricePer1K, envConfig.LLMPricePer1K, yamlConfig.LLMPricePer1K)
+	merged.MinSummaryChars = firstNonZero(cliConfig.MinSummaryChars, envConfig.MinSummaryChars, yamlConfig.MinSummaryChars)
+	merged.LLMConcurrency = firstNonZero(cliConfig.LLMConcurrency, envConfig.LLMConcurrency, yamlConfig.LLMConcurrency)
+	merged.NoSummaryCache = firstBool(cliConfig.NoSummaryCache, envConfig.NoSummaryCache, yamlConfig.NoSummaryCache)
 
 	// Output configuration
 	merged.Output = firstNonEmpty(cliConfig.Output, envConfig.Output, yamlConfig.Output)
+	merged.OutputStdoutFormat = firstNonEmpty(cliConfig.OutputStdoutFormat, envConfig.OutputStdoutFormat, yamlConfig.OutputStdoutFormat)
+	merged.ReportID = firstNonEmpty(cliConfig.ReportID, envConfig.ReportID, yamlConfig.ReportID)
+	merged.Routes = firstNonEmptySlice(cliConfig.Routes, envConfig.Routes, yamlConfig.Routes)
+	merged.Append = firstBool(cliConfig.Append, envConfig.Append, yamlConfig.Append)
+	merged.MaxOutputSize = firstNonEmpty(cliConfig.MaxOutputSize, envConfig.MaxOutputSize, yamlConfig.MaxOutputSize)
+	merged.OutputMode = firstNonEmpty(cliConfig.OutputMode, envConfig.OutputMode, yamlConfig.OutputMode)
+	merged.LineEnding = firstNonEmpty(cliConfig.LineEnding, envConfig.LineEnding, yamlConfig.LineEnding)
+	merged.Manifest = firstNonEmpty(cliConfig.Manifest, envConfig.Manifest, yamlConfig.Manifest)
+	merged.NotifyOnError = firstNonEmpty(cliConfig.NotifyOnError, envConfig.NotifyOnError, yamlConfig.NotifyOnError)
+	merged.CheckOnly = firstNonEmpty(cliConfig.CheckOnly, envConfig.CheckOnly, yamlConfig.CheckOnly)
+	merged.Force = firstBool(cliConfig.Force, envConfig.Force, yamlConfig.Force)
+	merged.Format = firstNonEmpty(cliConfig.Format, envConfig.Format, yamlConfig.Format)
+	merged.TemplateDir = firstNonEmpty(cliConfig.TemplateDir, envConfig.TemplateDir, yamlConfig.TemplateDir)
+	merged.Template = firstNonEmpty(cliConfig.Template, envConfig.Template, yamlConfig.Template)
+	merged.JSONCompact = firstBool(cliConfig.JSONCompact, envConfig.JSONCompact, yamlConfig.JSONCompact)
+	merged.BadgeLabel = firstNonEmpty(cliConfig.BadgeLabel, envConfig.BadgeLabel, yamlConfig.BadgeLabel)
+	merged.BadgeYellowAt = firstNonZero(cliConfig.BadgeYellowAt, envConfig.BadgeYellowAt, yamlConfig.BadgeYellowAt)
+	merged.BadgeGreenAt = firstNonZero(cliConfig.BadgeGreenAt, envConfig.BadgeGreenAt, yamlConfig.BadgeGreenAt)
+	merged.Clipboard = firstBool(cliConfig.Clipboard, envConfig.Clipboard, yamlConfig.Clipboard)
+	merged.GroupBy = firstNonEmpty(cliConfig.GroupBy, envConfig.GroupBy, yamlConfig.GroupBy, "none")
+	merged.MinLabelCount = firstNonZero(cliConfig.MinLabelCount, envConfig.MinLabelCount, yamlConfig.MinLabelCount)
+	merged.RepoOrder = firstNonEmpty(cliConfig.RepoOrder, envConfig.RepoOrder, yamlConfig.RepoOrder)
+	merged.RepoDescriptions = firstBool(cliConfig.RepoDescriptions, envConfig.RepoDescriptions, yamlConfig.RepoDescriptions)
+	merged.HeadingOffset = firstNonZero(cliConfig.HeadingOffset, envConfig.HeadingOffset, yamlConfig.HeadingOffset)
+	merged.NoDetailsBody = firstBool(cliConfig.NoDetailsBody, envConfig.NoDetailsBody, yamlConfig.NoDetailsBody)
+	merged.ShowEmptySections = firstBool(cliConfig.ShowEmptySections, envConfig.ShowEmptySections, yamlConfig.ShowEmptySections)
+	merged.LinkStyle = firstNonEmpty(cliConfig.LinkStyle, envConfig.LinkStyle, yamlConfig.LinkStyle)
+	merged.StatusIcons = firstBool(cliConfig.StatusIcons, envConfig.StatusIcons, yamlConfig.StatusIcons)
+	merged.DetailsFormat = firstNonEmpty(cliConfig.DetailsFormat, envConfig.DetailsFormat, yamlConfig.DetailsFormat)
+	merged.DedupeBy = firstNonEmpty(cliConfig.DedupeBy, envConfig.DedupeBy, yamlConfig.DedupeBy)
+	merged.IncludeReposWithoutPRs = firstBool(cliConfig.IncludeReposWithoutPRs, envConfig.IncludeReposWithoutPRs, yamlConfig.IncludeReposWithoutPRs)
+	merged.MergeTarget = firstBool(cliConfig.MergeTarget, envConfig.MergeTarget, yamlConfig.MergeTarget)
+	merged.FacetTopics = firstBool(cliConfig.FacetTopics, envConfig.FacetTopics, yamlConfig.FacetTopics)
 	merged.DryRun = firstBool(cliConfig.DryRun, envConfig.DryRun, yamlConfig.DryRun)
+	merged.Plan = firstBool(cliConfig.Plan, envConfig.Plan, yamlConfig.Plan)
 	merged.Verbose = firstBool(cliConfig.Verbose, envConfig.Verbose, yamlConfig.Verbose)
 	merged.CI = firstBool(cliConfig.CI, envConfig.CI, yamlConfig.CI)
 
@@ -175,6 +598,58 @@ func parseTeams(teamStr string) []string {
 	return teams
 }
 
+// envList reads a comma-separated environment variable, returning nil if unset
+func envList(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	items := strings.Split(value, ",")
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
+
+// envLines reads a newline-separated environment variable, skipping blank
+// lines and returning nil if unset
+func envLines(key string) []string {
+	var items []string
+	for _, line := range strings.Split(os.Getenv(key), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			items = append(items, line)
+		}
+	}
+	return items
+}
+
+// envMap reads a comma-separated list of key=value pairs from an environment
+// variable, returning nil if unset. Malformed pairs are ignored.
+func envMap(key string) map[string]string {
+	var result map[string]string
+	for _, item := range envList(key) {
+		k, v, ok := strings.Cut(item, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[k] = strings.TrimSpace(v)
+	}
+	return result
+}
+
+// envInt reads an integer environment variable, returning 0 if unset or invalid
+func envInt(key string) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
 // firstNonEmpty returns the first non-empty string from the given values
 func firstNonEmpty(values ...string) string {
 	for _, v := range values {
@@ -185,6 +660,16 @@ func firstNonEmpty(values ...string) string {
 	return ""
 }
 
+// firstNonZero returns the first non-zero integer from the given values
+func firstNonZero(values ...int) int {
+	for _, v := range values {
+		if v != 0 {
+			return v
+		}
+	}
+	return 0
+}
+
 // firstBool returns the first true boolean or the last boolean if none are true
 func firstBool(values ...bool) bool {
 	for i, v := range values {
@@ -195,6 +680,16 @@ func firstBool(values ...bool) bool {
 	return false
 }
 
+// firstNonEmptyMap returns the first non-empty map from the given values
+func firstNonEmptyMap(values ...map[string]string) map[string]string {
+	for _, v := range values {
+		if len(v) > 0 {
+			return v
+		}
+	}
+	return nil
+}
+
 // firstNonEmptySlice returns the first non-empty slice from the given values
 func firstNonEmptySlice(values ...[]string) []string {
 	for _, v := range values {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -280,3 +281,84 @@ func configsEqual(a, b *Config) bool {
 		a.CI == b.CI &&
 		a.LogFile == b.LogFile
 }
+
+func TestMaxLLMTokens(t *testing.T) {
+	t.Setenv("PRTOOL_MAX_LLM_TOKENS", "4000")
+	if got := LoadFromEnv().MaxLLMTokens; got != 4000 {
+		t.Errorf("Expected MaxLLMTokens 4000 from env, got %d", got)
+	}
+
+	t.Setenv("PRTOOL_MAX_LLM_TOKENS", "lots")
+	if got := LoadFromEnv().MaxLLMTokens; got != 0 {
+		t.Errorf("Expected invalid MaxLLMTokens to be ignored, got %d", got)
+	}
+
+	merged := MergeConfig(&Config{}, &Config{MaxLLMTokens: 2000}, &Config{MaxLLMTokens: 1000})
+	if merged.MaxLLMTokens != 2000 {
+		t.Errorf("Expected env MaxLLMTokens to win over YAML, got %d", merged.MaxLLMTokens)
+	}
+}
+
+func TestGitHubHeadersEnv(t *testing.T) {
+	t.Setenv("PRTOOL_GITHUB_HEADERS", "Accept=application/json, text/plain\n\n X-Internal-Auth=abc \n")
+	expected := []string{"Accept=application/json, text/plain", "X-Internal-Auth=abc"}
+	if got := LoadFromEnv().GitHubHeaders; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected newline-separated headers %q, got %q", expected, got)
+	}
+
+	t.Setenv("PRTOOL_GITHUB_HEADERS", "")
+	if got := LoadFromEnv().GitHubHeaders; got != nil {
+		t.Errorf("Expected no headers when unset, got %q", got)
+	}
+}
+
+func TestGroupByDefault(t *testing.T) {
+	merged := MergeConfig(&Config{}, &Config{}, &Config{})
+	if merged.GroupBy != "none" {
+		t.Errorf("Expected GroupBy to default to none, got %q", merged.GroupBy)
+	}
+
+	merged = MergeConfig(&Config{}, &Config{}, &Config{GroupBy: "repo"})
+	if merged.GroupBy != "repo" {
+		t.Errorf("Expected YAML GroupBy to be used, got %q", merged.GroupBy)
+	}
+}
+
+func TestLoadFromFile_ScopeConflict(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "org and user",
+			content:  "org: acme\nuser: octocat\n",
+			expected: "sets both org and user",
+		},
+		{
+			name:     "three scopes",
+			content:  "org: acme\nteam: acme/core\nrepo: acme/api\n",
+			expected: "sets org, team and repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "conflict.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write test config file: %v", err)
+			}
+
+			_, err := LoadFromFile(configPath)
+			if err == nil {
+				t.Fatal("Expected error for conflicting scopes")
+			}
+			if !strings.Contains(err.Error(), configPath) {
+				t.Errorf("Expected error to mention %s, got %q", configPath, err.Error())
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error to contain %q, got %q", tt.expected, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// configMigration upgrades a deprecated form of a top-level config key. It
+// receives the key's scalar value and returns the replacement lines, or false
+// when the value is already in its current form.
+type configMigration struct {
+	key     string
+	migrate func(value string) ([]string, string, bool)
+}
+
+// configMigrations lists the upgrades applied by Migrate, in order
+var configMigrations = []configMigration{
+	{key: "team", migrate: migrateTeamScalar},
+}
+
+// topLevelScalar matches a top-level "key: value" line with a scalar value,
+// keeping any trailing comment
+var topLevelScalar = regexp.MustCompile(`^([A-Za-z_]+):[ \t]+([^#\[{\s][^#]*?|"[^"]*"|'[^']*')([ \t]+#.*)?$`)
+
+// Migrate upgrades deprecated keys in the YAML config content to their current
+// form. Lines are rewritten in place, so comments and the order of keys are
+// kept. It returns the migrated content and a description of each change,
+// none when the config is already current.
+func Migrate(content []byte) ([]byte, []string, error) {
+	var probe map[string]interface{}
+	if err := yaml.Unmarshal(content, &probe); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse YAML config: %w", err)
+	}
+
+	var changes []string
+	lines := strings.Split(string(content), "\n")
+	var migrated []string
+	for _, line := range lines {
+		matches := topLevelScalar.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		replaced := false
+		for _, m := range configMigrations {
+			if matches == nil || matches[1] != m.key {
+				continue
+			}
+			var value string
+			if err := yaml.Unmarshal([]byte(matches[2]), &value); err != nil {
+				continue
+			}
+			upgraded, change, ok := m.migrate(value)
+			if !ok {
+				continue
+			}
+			upgraded[0] += matches[3]
+			migrated = append(migrated, upgraded...)
+			changes = append(changes, change)
+			replaced = true
+			break
+		}
+		if !replaced {
+			migrated = append(migrated, line)
+		}
+	}
+
+	return []byte(strings.Join(migrated, "\n")), changes, nil
+}
+
+// migrateTeamScalar turns a single or comma-separated team string into a
+// list of teams, matching how --team and PRTOOL_TEAM split teams
+func migrateTeamScalar(value string) ([]string, string, bool) {
+	teams := parseTeams(value)
+	var kept []string
+	for _, team := range teams {
+		if team != "" {
+			kept = append(kept, team)
+		}
+	}
+	if len(kept) == 0 {
+		return nil, "", false
+	}
+
+	lines := []string{"team:"}
+	for _, team := range kept {
+		lines = append(lines, "  - "+team)
+	}
+	return lines, fmt.Sprintf("team: converted %q to a list of %d team(s)", value, len(kept)), true
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		changes  int
+	}{
+		{
+			name:     "single team string becomes a list",
+			input:    "# Team scope\nteam: acme/backend # the main team\nsince: -7d\n",
+			expected: "# Team scope\nteam: # the main team\n  - acme/backend\nsince: -7d\n",
+			changes:  1,
+		},
+		{
+			name:     "comma-separated quoted team string is split",
+			input:    "team: \"acme/backend, acme/frontend\"\n",
+			expected: "team:\n  - acme/backend\n  - acme/frontend\n",
+			changes:  1,
+		},
+		{
+			name:     "team list is current",
+			input:    "team:\n  - acme/backend\n",
+			expected: "team:\n  - acme/backend\n",
+		},
+		{
+			name:     "flow team list is current",
+			input:    "team: [acme/backend]\n",
+			expected: "team: [acme/backend]\n",
+		},
+		{
+			name:     "empty team is left alone",
+			input:    "team: \"\"\norg: acme\n",
+			expected: "team: \"\"\norg: acme\n",
+		},
+		{
+			name:     "nested team keys are left alone",
+			input:    "label_aliases:\n  team: squad\n",
+			expected: "label_aliases:\n  team: squad\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changes, err := Migrate([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(got) != tt.expected {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+			if len(changes) != tt.changes {
+				t.Errorf("Expected %d change(s), got %v", tt.changes, changes)
+			}
+		})
+	}
+}
+
+func TestMigrate_LoadsAsList(t *testing.T) {
+	got, _, err := Migrate([]byte("team: acme/backend,acme/frontend\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(got, &cfg); err != nil {
+		t.Fatalf("Migrated config does not parse: %v", err)
+	}
+	if expected := (TeamList{"acme/backend", "acme/frontend"}); !reflect.DeepEqual(cfg.Team, expected) {
+		t.Errorf("Expected teams %v, got %v", expected, cfg.Team)
+	}
+}
+
+func TestMigrate_InvalidYAML(t *testing.T) {
+	if _, _, err := Migrate([]byte("team: [unclosed\n")); err == nil || !strings.Contains(err.Error(), "failed to parse") {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// redacted replaces secret values in printed configuration
+const redacted = "<redacted>"
+
+// Sources of a merged configuration value
+const (
+	SourceCLI     = "cli"
+	SourceEnv     = "env"
+	SourceYAML    = "yaml"
+	SourceDefault = "default"
+)
+
+// ValueSources reports, by YAML key, which configuration layer each merged
+// value came from. A value that none of the layers set, or that differs from
+// all of them, is a default.
+func ValueSources(cliConfig, envConfig, yamlConfig, merged *Config) map[string]string {
+	layers := []struct {
+		source string
+		config *Config
+	}{{SourceCLI, cliConfig}, {SourceEnv, envConfig}, {SourceYAML, yamlConfig}}
+
+	sources := make(map[string]string)
+	mergedValue := reflect.ValueOf(merged).Elem()
+	configType := mergedValue.Type()
+	for i := 0; i < configType.NumField(); i++ {
+		key := yamlKey(configType.Field(i))
+		if key == "" {
+			continue
+		}
+
+		value := mergedValue.Field(i)
+		sources[key] = SourceDefault
+		if value.IsZero() {
+			continue
+		}
+		for _, layer := range layers {
+			if layer.config == nil {
+				continue
+			}
+			if reflect.DeepEqual(reflect.ValueOf(layer.config).Elem().Field(i).Interface(), value.Interface()) {
+				sources[key] = layer.source
+				break
+			}
+		}
+	}
+
+	return sources
+}
+
+// FormatEffective renders the merged configuration as YAML with secrets
+// redacted, marking each top-level key with the layer its value came from
+func FormatEffective(cliConfig, envConfig, yamlConfig, merged *Config) (string, error) {
+	printed := *merged
+	if printed.GitHubToken != "" {
+		printed.GitHubToken = redacted
+	}
+	if printed.LLMAPIKey != "" {
+		printed.LLMAPIKey = redacted
+	}
+	// Webhook URLs such as Slack's embed their secret
+	if printed.NotifyOnError != "" {
+		printed.NotifyOnError = redacted
+	}
+	if len(printed.Hosts) > 0 {
+		printed.Hosts = append([]HostProfile(nil), merged.Hosts...)
+		for i := range printed.Hosts {
+			if printed.Hosts[i].Token != "" {
+				printed.Hosts[i].Token = redacted
+			}
+		}
+	}
+	// Header values often carry credentials
+	if len(printed.GitHubHeaders) > 0 {
+		printed.GitHubHeaders = make([]string, len(merged.GitHubHeaders))
+		for i, header := range merged.GitHubHeaders {
+			name, _, _ := strings.Cut(header, "=")
+			printed.GitHubHeaders[i] = name + "=" + redacted
+		}
+	}
+
+	data, err := yaml.Marshal(&printed)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	sources := ValueSources(cliConfig, envConfig, yamlConfig, merged)
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	for i, line := range lines {
+		if line == "" || line[0] == ' ' || line[0] == '-' {
+			continue
+		}
+		key, _, ok := strings.Cut(line, ":")
+		if source, known := sources[key]; ok && known {
+			lines[i] = fmt.Sprintf("%s # %s", line, source)
+		}
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// yamlKey returns the YAML key of a Config field, or "" when it has none
+func yamlKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if key == "-" {
+		return ""
+	}
+	return key
+}
@@ -3,6 +3,9 @@ package gh
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,25 +21,191 @@ type GitHubClient interface {
 
 	// ListPRs returns pull requests for a given repository since a specific time
 	ListPRs(repo string, since time.Time) ([]*model.PR, error)
+
+	// ListClosedPRs returns the pull requests of a repository closed after
+	// since, whether or not they were merged
+	ListClosedPRs(repo string, since time.Time) ([]*model.PR, error)
+
+	// CurrentUser returns the login of the authenticated user
+	CurrentUser() (string, error)
+
+	// LatestReleaseDate returns when the latest release of a repository was
+	// published, or nil if the repository has no releases
+	LatestReleaseDate(repo string) (*time.Time, error)
+
+	// GetPR returns a single pull request, including its changed files
+	GetPR(repo string, number int) (*model.PR, error)
+
+	// GetPRStats returns the lines added and deleted by a pull request
+	GetPRStats(repo string, number int) (additions, deletions int, err error)
+
+	// ListPRFiles returns the paths of the files changed by a pull request
+	ListPRFiles(repo string, number int) ([]string, error)
+
+	// ListTeams returns the teams of an organization visible to the token
+	ListTeams(org string) ([]*github.Team, error)
+
+	// ListPRCommits returns the commits of a pull request
+	ListPRCommits(repo string, number int) ([]*github.RepositoryCommit, error)
 }
 
 // RestClient implements GitHubClient using the GitHub REST API
 type RestClient struct {
 	client *github.Client
 	ctx    context.Context
+	login  string
+}
+
+// DefaultUserAgent is the User-Agent sent on GitHub requests when none is configured
+const DefaultUserAgent = "prtool"
+
+// DefaultTimeout bounds each GitHub request when no timeout is configured
+const DefaultTimeout = 30 * time.Second
+
+// clientOptions holds optional settings for NewRestClient
+type clientOptions struct {
+	userAgent  string
+	ctx        context.Context
+	headers    http.Header
+	fixtureDir string
+	replayDir  string
+	httpClient *http.Client
+	timeout    *time.Duration
+	baseURL    string
+}
+
+// ClientOption configures optional behaviour of a RestClient
+type ClientOption func(*clientOptions)
+
+// WithUserAgent sets the User-Agent header sent on every GitHub request
+func WithUserAgent(userAgent string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithContext sets the context used for GitHub requests, so cancelling it
+// aborts in-flight calls
+func WithContext(ctx context.Context) ClientOption {
+	return func(o *clientOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithHeader adds a header sent on every GitHub request, for example to
+// satisfy an API gateway in front of GitHub Enterprise
+func WithHeader(key, value string) ClientOption {
+	return func(o *clientOptions) {
+		if o.headers == nil {
+			o.headers = make(http.Header)
+		}
+		o.headers.Add(key, value)
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for GitHub requests, for example to
+// tune timeouts or to serve responses from a test transport. The client is
+// copied, and headers and fixture recording wrap its transport.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.httpClient = hc
+	}
+}
+
+// WithTimeout sets the timeout of each GitHub request, overriding
+// DefaultTimeout and the timeout of a client set with WithHTTPClient. Zero
+// disables the timeout.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = &timeout
+	}
+}
+
+// WithBaseURL points the client at a GitHub Enterprise server's API, e.g.
+// https://ghe.example.com/api/v3/. Empty keeps github.com.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(o *clientOptions) {
+		o.baseURL = baseURL
+	}
+}
+
+// headerTransport adds fixed headers to every request
+type headerTransport struct {
+	headers http.Header
+	base    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	for key, values := range t.headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	return base.RoundTrip(req)
 }
 
 // NewRestClient creates a new GitHub REST client with PAT authentication
-func NewRestClient(token string) (*RestClient, error) {
+func NewRestClient(token string, opts ...ClientOption) (*RestClient, error) {
 	if token == "" {
 		return nil, fmt.Errorf("GitHub token is required")
 	}
 
-	client := github.NewClient(nil).WithAuthToken(token)
+	options := clientOptions{userAgent: DefaultUserAgent, ctx: context.Background()}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// A nil transport uses http.DefaultTransport
+	var transport http.RoundTripper
+	if options.httpClient != nil {
+		transport = options.httpClient.Transport
+	}
+	if options.replayDir != "" {
+		transport = &replayTransport{dir: options.replayDir}
+	} else if options.fixtureDir != "" {
+		transport = &recordingTransport{dir: options.fixtureDir, base: transport}
+	}
+	if len(options.headers) > 0 {
+		transport = &headerTransport{headers: options.headers, base: transport}
+	}
+
+	httpClient := &http.Client{Transport: transport, Timeout: DefaultTimeout}
+	if options.httpClient != nil {
+		custom := *options.httpClient
+		custom.Transport = transport
+		if custom.Timeout == 0 {
+			custom.Timeout = DefaultTimeout
+		}
+		httpClient = &custom
+	}
+	if options.timeout != nil {
+		httpClient.Timeout = *options.timeout
+	}
+
+	client := github.NewClient(httpClient).WithAuthToken(token)
+	if options.baseURL != "" {
+		enterprise, err := client.WithEnterpriseURLs(options.baseURL, options.baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub base URL '%s': %w", options.baseURL, err)
+		}
+		client = enterprise
+	}
+	if options.userAgent != "" {
+		client.UserAgent = options.userAgent
+	}
 
 	// Test authentication by making a simple API call
-	ctx := context.Background()
-	_, _, err := client.Users.Get(ctx, "")
+	ctx := options.ctx
+	authUser, _, err := client.Users.Get(ctx, "")
 	if err != nil {
 		return nil, fmt.Errorf("GitHub authentication failed: %w", err)
 	}
@@ -44,9 +213,25 @@ func NewRestClient(token string) (*RestClient, error) {
 	return &RestClient{
 		client: client,
 		ctx:    ctx,
+		login:  authUser.GetLogin(),
 	}, nil
 }
 
+// CurrentUser returns the login of the authenticated user
+func (c *RestClient) CurrentUser() (string, error) {
+	if c.login != "" {
+		return c.login, nil
+	}
+
+	authUser, _, err := c.client.Users.Get(c.ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+	c.login = authUser.GetLogin()
+
+	return c.login, nil
+}
+
 // ListRepos returns repositories based on the scope configuration
 func (c *RestClient) ListRepos(scope *config.Config) ([]*github.Repository, error) {
 	if scope == nil {
@@ -67,8 +252,28 @@ func (c *RestClient) ListRepos(scope *config.Config) ([]*github.Repository, erro
 	return nil, fmt.Errorf("no valid scope specified (org, user, repo, or team required)")
 }
 
-// ListPRs returns pull requests for a repository since a specific time
+// ListPRs returns pull requests for a repository since a specific time. It
+// pages through the pull request listing, which unlike the search API is not
+// capped at 1000 results, so busy repositories are never truncated.
 func (c *RestClient) ListPRs(repo string, since time.Time) ([]*model.PR, error) {
+	// Only include merged PRs that were merged after the since time
+	return c.listClosedPRs(repo, since, func(pr *github.PullRequest) bool {
+		return pr.MergedAt != nil && pr.MergedAt.After(since)
+	})
+}
+
+// ListClosedPRs returns the pull requests of a repository closed after since,
+// merged or not
+func (c *RestClient) ListClosedPRs(repo string, since time.Time) ([]*model.PR, error) {
+	return c.listClosedPRs(repo, since, func(pr *github.PullRequest) bool {
+		return pr.ClosedAt != nil && pr.ClosedAt.After(since)
+	})
+}
+
+// listClosedPRs pages through the closed pull requests of a repository,
+// keeping those accepted by keep, until a page ends with a PR last updated
+// before since
+func (c *RestClient) listClosedPRs(repo string, since time.Time, keep func(*github.PullRequest) bool) ([]*model.PR, error) {
 	if repo == "" {
 		return nil, fmt.Errorf("repository name is required")
 	}
@@ -81,8 +286,9 @@ func (c *RestClient) ListPRs(repo string, since time.Time) ([]*model.PR, error)
 	owner, repoName := parts[0], parts[1]
 
 	opts := &github.PullRequestListOptions{
-		State: "closed", // We want merged PRs which are in closed state
-		Sort:  "updated",
+		State:     "closed", // We want merged PRs which are in closed state
+		Sort:      "updated",
+		Direction: "desc",
 		ListOptions: github.ListOptions{
 			PerPage: 100,
 		},
@@ -97,13 +303,21 @@ func (c *RestClient) ListPRs(repo string, since time.Time) ([]*model.PR, error)
 		}
 
 		for _, pr := range prs {
-			// Only include merged PRs that were merged after the since time
-			if pr.MergedAt != nil && pr.MergedAt.After(since) {
+			if keep(pr) {
 				modelPR := c.convertToModelPR(pr, repo)
 				allPRs = append(allPRs, modelPR)
 			}
 		}
 
+		// PRs are listed most recently updated first and a PR is updated when
+		// it is merged or closed, so later pages hold no PRs merged or closed
+		// after since
+		if len(prs) > 0 {
+			if updated := prs[len(prs)-1].UpdatedAt; updated != nil && updated.Before(since) {
+				break
+			}
+		}
+
 		if resp.NextPage == 0 {
 			break
 		}
@@ -113,6 +327,113 @@ func (c *RestClient) ListPRs(repo string, since time.Time) ([]*model.PR, error)
 	return allPRs, nil
 }
 
+// LatestReleaseDate returns when the latest release of a repository was published
+func (c *RestClient) LatestReleaseDate(repo string) (*time.Time, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("repository must be in format 'owner/repo'")
+	}
+
+	release, resp, err := c.client.Repositories.GetLatestRelease(c.ctx, parts[0], parts[1])
+	if err != nil {
+		// GitHub returns 404 when a repository has no published releases
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest release for %s: %w", repo, err)
+	}
+
+	return safeTimestampPtr(release.PublishedAt), nil
+}
+
+// GetPR returns a single pull request, including its changed files
+func (c *RestClient) GetPR(repo string, number int) (*model.PR, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("repository must be in format 'owner/repo'")
+	}
+
+	pr, _, err := c.client.PullRequests.Get(c.ctx, parts[0], parts[1], number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PR %s#%d: %w", repo, number, err)
+	}
+
+	modelPR := c.convertToModelPR(pr, repo)
+
+	modelPR.FilePaths, err = c.ListPRFiles(repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return modelPR, nil
+}
+
+// GetPRStats returns the lines added and deleted by a pull request with a
+// single request, without listing its files
+func (c *RestClient) GetPRStats(repo string, number int) (int, int, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("repository must be in format 'owner/repo'")
+	}
+
+	pr, _, err := c.client.PullRequests.Get(c.ctx, parts[0], parts[1], number)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get PR %s#%d: %w", repo, number, err)
+	}
+
+	return pr.GetAdditions(), pr.GetDeletions(), nil
+}
+
+// ListPRFiles returns the paths of the files changed by a pull request
+func (c *RestClient) ListPRFiles(repo string, number int) ([]string, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("repository must be in format 'owner/repo'")
+	}
+
+	var paths []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		files, resp, err := c.client.PullRequests.ListFiles(c.ctx, parts[0], parts[1], number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files for PR %s#%d: %w", repo, number, err)
+		}
+
+		for _, file := range files {
+			paths = append(paths, file.GetFilename())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return paths, nil
+}
+
+// ParsePRURL extracts the owner/repo name and PR number from a pull request
+// URL such as https://github.com/owner/repo/pull/123
+func ParsePRURL(rawURL string) (string, int, error) {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || parsed.Host == "" {
+		return "", 0, fmt.Errorf("invalid PR URL '%s'", rawURL)
+	}
+
+	// owner/repo/pull/123, optionally followed by /files, /commits, ...
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(segments) < 4 || segments[2] != "pull" || segments[0] == "" || segments[1] == "" {
+		return "", 0, fmt.Errorf("invalid PR URL '%s' (expected https://github.com/owner/repo/pull/123)", rawURL)
+	}
+
+	number, err := strconv.Atoi(segments[3])
+	if err != nil || number <= 0 {
+		return "", 0, fmt.Errorf("invalid PR number '%s' in URL '%s'", segments[3], rawURL)
+	}
+
+	return segments[0] + "/" + segments[1], number, nil
+}
+
 // Helper methods for different scope types
 func (c *RestClient) listOrgRepos(org string) ([]*github.Repository, error) {
 	opts := &github.RepositoryListByOrgOptions{
@@ -175,6 +496,55 @@ func (c *RestClient) getSingleRepo(repo string) ([]*github.Repository, error) {
 	return []*github.Repository{repository}, nil
 }
 
+// ListPRCommits returns the commits of a pull request
+func (c *RestClient) ListPRCommits(repo string, number int) ([]*github.RepositoryCommit, error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("repository must be in format 'owner/repo'")
+	}
+
+	var allCommits []*github.RepositoryCommit
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		commits, resp, err := c.client.PullRequests.ListCommits(c.ctx, parts[0], parts[1], number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits for PR %s#%d: %w", repo, number, err)
+		}
+		allCommits = append(allCommits, commits...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allCommits, nil
+}
+
+// ListTeams returns the teams of an organization visible to the token
+func (c *RestClient) ListTeams(org string) ([]*github.Team, error) {
+	if org == "" {
+		return nil, fmt.Errorf("organization name is required")
+	}
+
+	var allTeams []*github.Team
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		teams, resp, err := c.client.Teams.ListTeams(c.ctx, org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list teams for organization %s: %w", org, err)
+		}
+		allTeams = append(allTeams, teams...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allTeams, nil
+}
+
 func (c *RestClient) listTeamsRepos(teams []string) ([]*github.Repository, error) {
 	repoMap := make(map[string]*github.Repository)
 
@@ -218,15 +588,23 @@ func (c *RestClient) listTeamsRepos(teams []string) ([]*github.Repository, error
 // convertToModelPR converts a GitHub API PR to our internal model
 func (c *RestClient) convertToModelPR(pr *github.PullRequest, repo string) *model.PR {
 	modelPR := &model.PR{
-		Title:      safeString(pr.Title),
-		Body:       safeString(pr.Body),
-		Author:     safeString(pr.User.Login),
-		CreatedAt:  safeTimestamp(pr.CreatedAt),
-		MergedAt:   safeTimestampPtr(pr.MergedAt),
-		HTMLURL:    safeString(pr.HTMLURL),
-		Number:     safeInt(pr.Number),
-		Repository: repo,
-		State:      safeString(pr.State),
+		Title:          safeString(pr.Title),
+		Body:           safeString(pr.Body),
+		Author:         safeString(pr.User.Login),
+		CreatedAt:      safeTimestamp(pr.CreatedAt),
+		MergedAt:       safeTimestampPtr(pr.MergedAt),
+		ClosedAt:       safeTimestampPtr(pr.ClosedAt),
+		HTMLURL:        safeString(pr.HTMLURL),
+		Number:         safeInt(pr.Number),
+		Repository:     repo,
+		State:          safeString(pr.State),
+		IsDraft:        pr.GetDraft(),
+		BaseBranch:     pr.GetBase().GetRef(),
+		Milestone:      pr.GetMilestone().GetTitle(),
+		MergeCommitSHA: pr.GetMergeCommitSHA(),
+		// Only set on single PR responses, not in PR listings
+		Additions: pr.GetAdditions(),
+		Deletions: pr.GetDeletions(),
 	}
 
 	// Extract labels
@@ -1,7 +1,13 @@
 package gh
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -48,6 +54,359 @@ func TestNewRestClient(t *testing.T) {
 	}
 }
 
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// stubDefaultTransport replaces http.DefaultTransport for the duration of a test
+func stubDefaultTransport(t *testing.T, rt http.RoundTripper) {
+	t.Helper()
+	original := http.DefaultTransport
+	http.DefaultTransport = rt
+	t.Cleanup(func() { http.DefaultTransport = original })
+}
+
+// jsonResponse builds a canned JSON HTTP response
+func jsonResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+func TestNewRestClient_UserAgent(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []ClientOption
+		expected string
+	}{
+		{
+			name:     "default user agent",
+			expected: DefaultUserAgent,
+		},
+		{
+			name:     "custom user agent",
+			opts:     []ClientOption{WithUserAgent("prtool/1.2.3")},
+			expected: "prtool/1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var userAgents []string
+			stubDefaultTransport(t, roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				userAgents = append(userAgents, req.Header.Get("User-Agent"))
+				return jsonResponse(req, http.StatusOK, `{"login":"octocat"}`), nil
+			}))
+
+			client, err := NewRestClient("test-token", tt.opts...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if client == nil {
+				t.Fatal("Expected non-nil client")
+			}
+
+			if len(userAgents) == 0 {
+				t.Fatal("Expected at least one request")
+			}
+			for _, ua := range userAgents {
+				if ua != tt.expected {
+					t.Errorf("Expected User-Agent %q, got %q", tt.expected, ua)
+				}
+			}
+		})
+	}
+}
+
+func TestNewRestClient_Headers(t *testing.T) {
+	var requests []*http.Request
+	stubDefaultTransport(t, roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req)
+		if strings.Contains(req.URL.Path, "/pulls") {
+			return jsonResponse(req, http.StatusOK, `[]`), nil
+		}
+		return jsonResponse(req, http.StatusOK, `{"login":"octocat"}`), nil
+	}))
+
+	client, err := NewRestClient("test-token", WithHeader("X-Internal-Auth", "secret"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := client.ListPRs("octo/repo", time.Now()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(requests) < 2 {
+		t.Fatalf("Expected requests for auth and PRs, got %d", len(requests))
+	}
+	for _, req := range requests {
+		if got := req.Header.Get("X-Internal-Auth"); got != "secret" {
+			t.Errorf("Expected X-Internal-Auth on %s, got %q", req.URL.Path, got)
+		}
+		if got := req.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Expected the token to still be sent on %s, got %q", req.URL.Path, got)
+		}
+	}
+}
+
+// serverTransport sends every request to an httptest server
+type serverTransport struct {
+	server *httptest.Server
+}
+
+func (t *serverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, _ := url.Parse(t.server.URL)
+	req = req.Clone(req.Context())
+	req.URL.Scheme, req.URL.Host = target.Scheme, target.Host
+	return t.server.Client().Transport.RoundTrip(req)
+}
+
+func TestRestClient_ListPRsStopsAtOldPages(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	old := time.Now().AddDate(0, 0, -30).UTC().Format(time.RFC3339)
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/user":
+			fmt.Fprint(w, `{"login":"octocat"}`)
+		case "/repos/octo/repo/pulls":
+			pages = append(pages, r.URL.Query().Get("page"))
+			w.Header().Set("Link", `<https://api.github.com/repos/octo/repo/pulls?page=2>; rel="next"`)
+			fmt.Fprintf(w, `[{"number":1,"title":"New","state":"closed","user":{"login":"octocat"},"merged_at":%q,"updated_at":%q},{"number":2,"title":"Old","state":"closed","user":{"login":"octocat"},"merged_at":%q,"updated_at":%q}]`, recent, recent, old, old)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRestClient("test-token", WithHTTPClient(&http.Client{Transport: &serverTransport{server: server}}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	prs, err := client.ListPRs("octo/repo", time.Now().AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prs) != 1 || prs[0].Number != 1 {
+		t.Errorf("Expected only PR 1, got %d PR(s)", len(prs))
+	}
+	if len(pages) != 1 {
+		t.Errorf("Expected paging to stop after the first page, got pages %q", pages)
+	}
+}
+
+func TestRestClient_ListClosedPRs(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	old := time.Now().AddDate(0, 0, -30).UTC().Format(time.RFC3339)
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/user":
+			fmt.Fprint(w, `{"login":"octocat"}`)
+		case "/repos/octo/repo/pulls":
+			pages = append(pages, r.URL.Query().Get("page"))
+			w.Header().Set("Link", `<https://api.github.com/repos/octo/repo/pulls?page=2>; rel="next"`)
+			fmt.Fprintf(w, `[{"number":1,"title":"Merged","state":"closed","user":{"login":"octocat"},"merged_at":%q,"closed_at":%q,"updated_at":%q},{"number":2,"title":"Abandoned","state":"closed","user":{"login":"octocat"},"closed_at":%q,"updated_at":%q},{"number":3,"title":"Old","state":"closed","user":{"login":"octocat"},"closed_at":%q,"updated_at":%q}]`, recent, recent, recent, recent, recent, old, old)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRestClient("test-token", WithHTTPClient(&http.Client{Transport: &serverTransport{server: server}}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	prs, err := client.ListClosedPRs("octo/repo", time.Now().AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var numbers []int
+	for _, pr := range prs {
+		numbers = append(numbers, pr.Number)
+	}
+	if fmt.Sprint(numbers) != "[1 2]" {
+		t.Errorf("Expected the merged and unmerged PRs closed in the window, got %v", numbers)
+	}
+	if len(pages) != 1 {
+		t.Errorf("Expected paging to stop after the first page, got pages %q", pages)
+	}
+}
+
+func TestRestClient_GetPRStats(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/user":
+			fmt.Fprint(w, `{"login":"octocat"}`)
+		case "/repos/octo/repo/pulls/7":
+			paths = append(paths, r.URL.Path)
+			fmt.Fprint(w, `{"number":7,"title":"Add billing","additions":420,"deletions":35}`)
+		default:
+			paths = append(paths, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewRestClient("test-token", WithHTTPClient(&http.Client{Transport: &serverTransport{server: server}}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	additions, deletions, err := client.GetPRStats("octo/repo", 7)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if additions != 420 || deletions != 35 {
+		t.Errorf("Expected +420/-35, got +%d/-%d", additions, deletions)
+	}
+	if len(paths) != 1 {
+		t.Errorf("Expected a single PR request without listing files, got %q", paths)
+	}
+}
+
+func TestNewRestClient_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"login":"octocat"}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		opts     []ClientOption
+		expected time.Duration
+	}{
+		{name: "default", expected: DefaultTimeout},
+		{name: "configured", opts: []ClientOption{WithTimeout(5 * time.Second)}, expected: 5 * time.Second},
+		{name: "disabled", opts: []ClientOption{WithTimeout(0)}, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hc := &http.Client{Transport: &serverTransport{server: server}}
+			client, err := NewRestClient("test-token", append([]ClientOption{WithHTTPClient(hc)}, tt.opts...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if timeout := client.client.Client().Timeout; timeout != tt.expected {
+				t.Errorf("Expected timeout %v, got %v", tt.expected, timeout)
+			}
+		})
+	}
+}
+
+func TestNewRestClient_HTTPClientPagination(t *testing.T) {
+	merged := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/user":
+			fmt.Fprint(w, `{"login":"octocat"}`)
+		case "/repos/octo/repo/pulls":
+			page := r.URL.Query().Get("page")
+			pages = append(pages, page)
+			switch page {
+			case "", "1":
+				w.Header().Set("Link", `<https://api.github.com/repos/octo/repo/pulls?page=2>; rel="next", <https://api.github.com/repos/octo/repo/pulls?page=3>; rel="last"`)
+				fmt.Fprintf(w, `[{"number":1,"title":"One","state":"closed","user":{"login":"octocat"},"merged_at":%q}]`, merged)
+			case "2":
+				w.Header().Set("Link", `<https://api.github.com/repos/octo/repo/pulls?page=3>; rel="next", <https://api.github.com/repos/octo/repo/pulls?page=3>; rel="last"`)
+				fmt.Fprintf(w, `[{"number":2,"title":"Two","state":"closed","user":{"login":"octocat"},"merged_at":%q}]`, merged)
+			default:
+				fmt.Fprintf(w, `[{"number":3,"title":"Three","state":"closed","user":{"login":"octocat"},"merged_at":%q}]`, merged)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	hc := &http.Client{Transport: &serverTransport{server: server}, Timeout: 5 * time.Second}
+	client, err := NewRestClient("test-token", WithHTTPClient(hc), WithHeader("X-Test", "1"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	prs, err := client.ListPRs("octo/repo", time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prs) != 3 || prs[0].Number != 1 || prs[2].Number != 3 {
+		t.Errorf("Expected PRs from all three pages, got %d", len(prs))
+	}
+	if strings.Join(pages, ",") != ",2,3" {
+		t.Errorf("Expected pages 1 to 3 to be requested in order, got %q", pages)
+	}
+	if _, ok := hc.Transport.(*serverTransport); !ok {
+		t.Error("Expected the injected client not to be modified")
+	}
+}
+
+func TestConvertToModelPR_Draft(t *testing.T) {
+	client := &RestClient{}
+	pr := &github.PullRequest{
+		Title: github.String("WIP: new parser"),
+		User:  &github.User{Login: github.String("octocat")},
+		Draft: github.Bool(true),
+	}
+
+	modelPR := client.convertToModelPR(pr, "octo/repo")
+	if !modelPR.IsDraft {
+		t.Error("Expected draft status to be carried into the model")
+	}
+}
+
+func TestConvertToModelPR_BaseBranch(t *testing.T) {
+	client := &RestClient{}
+	pr := &github.PullRequest{
+		Title: github.String("Backport fix"),
+		User:  &github.User{Login: github.String("octocat")},
+		Base:  &github.PullRequestBranch{Ref: github.String("release/1.2")},
+	}
+
+	modelPR := client.convertToModelPR(pr, "octo/repo")
+	if modelPR.BaseBranch != "release/1.2" {
+		t.Errorf("Expected base branch release/1.2, got %q", modelPR.BaseBranch)
+	}
+}
+
+func TestConvertToModelPR_ClosedAt(t *testing.T) {
+	client := &RestClient{}
+	closedAt := time.Date(2024, 1, 14, 15, 20, 0, 0, time.UTC)
+	pr := &github.PullRequest{
+		Title:    github.String("Merged change"),
+		User:     &github.User{Login: github.String("octocat")},
+		MergedAt: &github.Timestamp{Time: closedAt},
+		ClosedAt: &github.Timestamp{Time: closedAt},
+	}
+
+	modelPR := client.convertToModelPR(pr, "octo/repo")
+	if modelPR.ClosedAt == nil || !modelPR.ClosedAt.Equal(closedAt) {
+		t.Fatalf("Expected ClosedAt %v, got %v", closedAt, modelPR.ClosedAt)
+	}
+
+	data, err := json.Marshal(modelPR)
+	if err != nil {
+		t.Fatalf("Failed to marshal PR: %v", err)
+	}
+	if !strings.Contains(string(data), `"closed_at":"2024-01-14T15:20:00Z"`) {
+		t.Errorf("Expected closed_at in JSON, got %s", data)
+	}
+}
+
 func TestMockClient_ListRepos(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -306,6 +665,46 @@ func TestMockClient_ListPRs(t *testing.T) {
 	}
 }
 
+func TestMockClient_ListPRsPaging(t *testing.T) {
+	now := time.Now()
+	ago := func(days int) *time.Time {
+		t := now.AddDate(0, 0, -days)
+		return &t
+	}
+
+	mock := NewMockClient()
+	mock.SetPageSize(2)
+	mock.SetMockPRs([]*model.PR{
+		{Number: 1, MergedAt: ago(1), Repository: "owner/repo"},
+		{Number: 2, MergedAt: ago(30), Repository: "owner/repo"},
+		{Number: 3, MergedAt: ago(3), Repository: "owner/repo"},
+		{Number: 4, MergedAt: ago(40), Repository: "owner/repo"},
+		{Number: 5, MergedAt: ago(9), Repository: "owner/repo"},
+		{Number: 6, MergedAt: ago(50), Repository: "owner/repo"},
+	})
+
+	prs, err := mock.ListPRs("owner/repo", now.AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prs) != 2 || prs[0].Number != 1 || prs[1].Number != 3 {
+		t.Errorf("Expected PRs 1 and 3, got %v", prs)
+	}
+
+	// Pages hold PRs 1 and 3, then 5 and 2, then 4 and 6. The second page
+	// ends before since, so the third is never requested.
+	var pages []string
+	for _, call := range mock.GetCallLog() {
+		if strings.HasPrefix(call, "ListPRsPage") {
+			pages = append(pages, call)
+		}
+	}
+	expected := []string{"ListPRsPage(owner/repo, 1)", "ListPRsPage(owner/repo, 2)"}
+	if fmt.Sprint(pages) != fmt.Sprint(expected) {
+		t.Errorf("Expected pages %v, got %v", expected, pages)
+	}
+}
+
 func TestMockClient_CallLog(t *testing.T) {
 	mock := NewMockClient()
 
@@ -357,3 +756,37 @@ func TestMockClient_CallLog(t *testing.T) {
 func containsString(s, substr string) bool {
 	return len(s) >= len(substr) && s[:len(substr)] == substr
 }
+
+func TestParsePRURL(t *testing.T) {
+	tests := []struct {
+		url        string
+		wantRepo   string
+		wantNumber int
+		wantErr    bool
+	}{
+		{url: "https://github.com/acme/api/pull/42", wantRepo: "acme/api", wantNumber: 42},
+		{url: "https://github.com/acme/api/pull/42/files", wantRepo: "acme/api", wantNumber: 42},
+		{url: "https://github.example.com/acme/api/pull/7#discussion", wantRepo: "acme/api", wantNumber: 7},
+		{url: "https://github.com/acme/api/issues/42", wantErr: true},
+		{url: "https://github.com/acme/api/pull/abc", wantErr: true},
+		{url: "acme/api/pull/42", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			repo, number, err := ParsePRURL(tt.url)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got repo %q number %d", repo, number)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if repo != tt.wantRepo || number != tt.wantNumber {
+				t.Errorf("Expected %s#%d, got %s#%d", tt.wantRepo, tt.wantNumber, repo, number)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package gh
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// fixture is a recorded GitHub API response as stored on disk. Body holds the
+// raw JSON returned by the API; Link is kept so pagination replays correctly.
+type fixture struct {
+	Status int             `json:"status"`
+	Link   string          `json:"link,omitempty"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// unsafeFixtureChars matches characters not used in fixture file names
+var unsafeFixtureChars = regexp.MustCompile(`[^A-Za-z0-9._=&-]+`)
+
+// fixturePath returns the file a response to req is recorded in. The name is
+// derived from the method, path and query, so the host and auth do not matter.
+func fixturePath(dir string, req *http.Request) string {
+	name := req.Method + " " + req.URL.Path
+	if query := req.URL.Query().Encode(); query != "" {
+		name += " " + query
+	}
+	return filepath.Join(dir, unsafeFixtureChars.ReplaceAllString(name, "_")+".json")
+}
+
+// recordingTransport writes every JSON response it sees to a fixture directory
+type recordingTransport struct {
+	dir  string
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response for fixture: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !json.Valid(body) {
+		return resp, nil
+	}
+
+	data, err := json.MarshalIndent(fixture{
+		Status: resp.StatusCode,
+		Link:   resp.Header.Get("Link"),
+		Body:   body,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(fixturePath(t.dir, req), append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write fixture: %w", err)
+	}
+
+	return resp, nil
+}
+
+// replayTransport serves responses from a fixture directory without any
+// network access
+type replayTransport struct {
+	dir string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := fixturePath(t.dir, req)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no fixture for %s %s: %w", req.Method, req.URL.RequestURI(), err)
+	}
+
+	var recorded fixture
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+
+	header := http.Header{"Content-Type": []string{"application/json"}}
+	if recorded.Link != "" {
+		header.Set("Link", recorded.Link)
+	}
+
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode: recorded.Status,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(recorded.Body)),
+		Request:    req,
+	}, nil
+}
+
+// WithFixtureDump records the raw JSON of every GitHub API response to files
+// under dir, for replay with NewReplayClient
+func WithFixtureDump(dir string) ClientOption {
+	return func(o *clientOptions) {
+		o.fixtureDir = dir
+	}
+}
+
+// NewReplayClient creates a RestClient that serves responses recorded with
+// WithFixtureDump from dir instead of calling GitHub
+func NewReplayClient(dir string, opts ...ClientOption) (*RestClient, error) {
+	opts = append(opts, func(o *clientOptions) {
+		o.replayDir = dir
+	})
+	return NewRestClient("replay", opts...)
+}
@@ -0,0 +1,77 @@
+package gh
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFixtureDump_RecordAndReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	page1 := `[{"number":1,"title":"First","state":"closed","merged_at":"2024-01-02T00:00:00Z","user":{"login":"alice"},"labels":[{"name":"bug"}]}]`
+	page2 := `[{"number":2,"title":"Second","state":"closed","merged_at":"2024-01-03T00:00:00Z","user":{"login":"bob"},"base":{"ref":"main"}}]`
+
+	stubDefaultTransport(t, roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case req.URL.Path == "/user":
+			return jsonResponse(req, http.StatusOK, `{"login":"octocat"}`), nil
+		case req.URL.Path == "/repos/acme/api/pulls" && req.URL.Query().Get("page") == "":
+			resp := jsonResponse(req, http.StatusOK, page1)
+			resp.Header.Set("Link", `<https://api.github.com/repos/acme/api/pulls?page=2>; rel="next"`)
+			return resp, nil
+		case req.URL.Path == "/repos/acme/api/pulls":
+			return jsonResponse(req, http.StatusOK, page2), nil
+		}
+		return jsonResponse(req, http.StatusNotFound, `{"message":"Not Found"}`), nil
+	}))
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	recorder, err := NewRestClient("test-token", WithFixtureDump(dir))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	recorded, err := recorder.ListPRs("acme/api", since)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(recorded) != 2 {
+		t.Fatalf("Expected 2 PRs across both pages, got %d", len(recorded))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read fixture dir: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("Expected fixtures for the user and both PR pages, got %d files", len(entries))
+	}
+
+	// Replay must not touch the network
+	stubDefaultTransport(t, roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, fmt.Errorf("unexpected network call to %s", req.URL)
+	}))
+
+	replayer, err := NewReplayClient(dir)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	replayed, err := replayer.ListPRs("acme/api", since)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(recorded, replayed) {
+		t.Errorf("Expected replayed PRs to match recorded PRs\nrecorded: %+v\nreplayed: %+v", recorded, replayed)
+	}
+}
+
+func TestNewReplayClient_MissingFixture(t *testing.T) {
+	if _, err := NewReplayClient(t.TempDir()); err == nil {
+		t.Error("Expected error when no fixture exists for the auth check")
+	}
+}
@@ -2,6 +2,8 @@ package gh
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/v55/github"
@@ -17,6 +19,27 @@ type MockClient struct {
 	// MockPRs can be set to control what ListPRs returns
 	MockPRs []*model.PR
 
+	// MockLogin can be set to control what CurrentUser returns
+	MockLogin string
+
+	// MockReleases maps repository names to their latest release date
+	MockReleases map[string]time.Time
+
+	// MockTeams maps organization names to what ListTeams returns
+	MockTeams map[string][]*github.Team
+
+	// MockCommits maps "owner/repo#number" to what ListPRCommits returns
+	MockCommits map[string][]*github.RepositoryCommit
+
+	// PageSize splits the PRs ListPRs returns into pages of this many PRs,
+	// most recently active first, and stops paging early like
+	// RestClient.ListPRs. Zero returns every PR at once.
+	PageSize int
+
+	// MockTeamRepos maps "org/team" to the repositories ListRepos returns for
+	// that team. When unset, team scopes return MockRepos.
+	MockTeamRepos map[string][]*github.Repository
+
 	// AuthError can be set to simulate authentication failures
 	AuthError error
 
@@ -28,6 +51,9 @@ type MockClient struct {
 
 	// CallLog tracks method calls for verification in tests
 	CallLog []string
+
+	// mu guards CallLog, as the fetcher lists repositories concurrently
+	mu sync.Mutex
 }
 
 // NewMockClient creates a new mock GitHub client
@@ -39,7 +65,7 @@ func NewMockClient() *MockClient {
 
 // ListRepos implements GitHubClient.ListRepos for testing
 func (m *MockClient) ListRepos(scope *config.Config) ([]*github.Repository, error) {
-	m.CallLog = append(m.CallLog, fmt.Sprintf("ListRepos(%+v)", scope))
+	m.logCall(fmt.Sprintf("ListRepos(%+v)", scope))
 
 	if m.AuthError != nil {
 		return nil, m.AuthError
@@ -76,13 +102,37 @@ func (m *MockClient) ListRepos(scope *config.Config) ([]*github.Repository, erro
 		return nil, fmt.Errorf("multiple scopes specified, only one allowed")
 	}
 
+	if len(scope.Team) > 0 && m.MockTeamRepos != nil {
+		var repos []*github.Repository
+		for _, team := range scope.Team {
+			repos = append(repos, m.MockTeamRepos[team]...)
+		}
+		return repos, nil
+	}
+
 	return m.MockRepos, nil
 }
 
-// ListPRs implements GitHubClient.ListPRs for testing
+// ListPRs implements GitHubClient.ListPRs for testing, returning the PRs
+// merged after since
 func (m *MockClient) ListPRs(repo string, since time.Time) ([]*model.PR, error) {
-	m.CallLog = append(m.CallLog, fmt.Sprintf("ListPRs(%s, %s)", repo, since.Format("2006-01-02")))
+	m.logCall(fmt.Sprintf("ListPRs(%s, %s)", repo, since.Format("2006-01-02")))
+	return m.listPRs(repo, since, func(pr *model.PR) bool {
+		return pr.MergedAt != nil && pr.MergedAt.After(since)
+	})
+}
+
+// ListClosedPRs implements GitHubClient.ListClosedPRs for testing, returning
+// the PRs closed after since, merged or not
+func (m *MockClient) ListClosedPRs(repo string, since time.Time) ([]*model.PR, error) {
+	m.logCall(fmt.Sprintf("ListClosedPRs(%s, %s)", repo, since.Format("2006-01-02")))
+	return m.listPRs(repo, since, func(pr *model.PR) bool {
+		return pr.ClosedAt != nil && pr.ClosedAt.After(since)
+	})
+}
 
+// listPRs returns the repository's PRs accepted by keep
+func (m *MockClient) listPRs(repo string, since time.Time, keep func(*model.PR) bool) ([]*model.PR, error) {
 	if m.AuthError != nil {
 		return nil, m.AuthError
 	}
@@ -95,6 +145,10 @@ func (m *MockClient) ListPRs(repo string, since time.Time) ([]*model.PR, error)
 		return nil, fmt.Errorf("repository name is required")
 	}
 
+	if m.PageSize > 0 {
+		return m.listPRPages(repo, since, keep), nil
+	}
+
 	// Filter PRs by repository and since date
 	var filteredPRs []*model.PR
 	for _, pr := range m.MockPRs {
@@ -102,8 +156,7 @@ func (m *MockClient) ListPRs(repo string, since time.Time) ([]*model.PR, error)
 		if pr.Repository != "" && pr.Repository != repo {
 			continue
 		}
-		// Filter by since date (only return PRs merged after since)
-		if pr.MergedAt != nil && pr.MergedAt.After(since) {
+		if keep(pr) {
 			filteredPRs = append(filteredPRs, pr)
 		}
 	}
@@ -111,6 +164,206 @@ func (m *MockClient) ListPRs(repo string, since time.Time) ([]*model.PR, error)
 	return filteredPRs, nil
 }
 
+// CurrentUser implements GitHubClient.CurrentUser for testing
+func (m *MockClient) CurrentUser() (string, error) {
+	m.logCall("CurrentUser()")
+
+	if m.AuthError != nil {
+		return "", m.AuthError
+	}
+
+	if m.MockLogin == "" {
+		return "", fmt.Errorf("no authenticated user")
+	}
+
+	return m.MockLogin, nil
+}
+
+// LatestReleaseDate implements GitHubClient.LatestReleaseDate for testing
+func (m *MockClient) LatestReleaseDate(repo string) (*time.Time, error) {
+	m.logCall(fmt.Sprintf("LatestReleaseDate(%s)", repo))
+
+	if m.AuthError != nil {
+		return nil, m.AuthError
+	}
+
+	released, ok := m.MockReleases[repo]
+	if !ok {
+		return nil, nil
+	}
+
+	return &released, nil
+}
+
+// GetPR implements GitHubClient.GetPR for testing, returning the matching PR
+// from MockPRs
+func (m *MockClient) GetPR(repo string, number int) (*model.PR, error) {
+	m.logCall(fmt.Sprintf("GetPR(%s, %d)", repo, number))
+
+	if m.AuthError != nil {
+		return nil, m.AuthError
+	}
+
+	if m.PRError != nil {
+		return nil, m.PRError
+	}
+
+	for _, pr := range m.MockPRs {
+		if pr.Repository == repo && pr.Number == number {
+			return pr, nil
+		}
+	}
+
+	return nil, fmt.Errorf("PR %s#%d not found", repo, number)
+}
+
+// GetPRStats implements GitHubClient.GetPRStats for testing, returning the
+// Additions and Deletions of the matching PR from MockPRs
+func (m *MockClient) GetPRStats(repo string, number int) (int, int, error) {
+	m.logCall(fmt.Sprintf("GetPRStats(%s, %d)", repo, number))
+
+	if m.AuthError != nil {
+		return 0, 0, m.AuthError
+	}
+
+	if m.PRError != nil {
+		return 0, 0, m.PRError
+	}
+
+	for _, pr := range m.MockPRs {
+		if pr.Repository == repo && pr.Number == number {
+			return pr.Additions, pr.Deletions, nil
+		}
+	}
+
+	return 0, 0, fmt.Errorf("PR %s#%d not found", repo, number)
+}
+
+// ListPRFiles implements GitHubClient.ListPRFiles for testing, returning the
+// FilePaths of the matching PR from MockPRs
+func (m *MockClient) ListPRFiles(repo string, number int) ([]string, error) {
+	m.logCall(fmt.Sprintf("ListPRFiles(%s, %d)", repo, number))
+
+	if m.AuthError != nil {
+		return nil, m.AuthError
+	}
+
+	if m.PRError != nil {
+		return nil, m.PRError
+	}
+
+	for _, pr := range m.MockPRs {
+		if pr.Repository == repo && pr.Number == number {
+			return pr.FilePaths, nil
+		}
+	}
+
+	return nil, fmt.Errorf("PR %s#%d not found", repo, number)
+}
+
+// ListTeams implements GitHubClient.ListTeams for testing
+func (m *MockClient) ListTeams(org string) ([]*github.Team, error) {
+	m.logCall(fmt.Sprintf("ListTeams(%s)", org))
+
+	if m.AuthError != nil {
+		return nil, m.AuthError
+	}
+
+	if m.RepoError != nil {
+		return nil, m.RepoError
+	}
+
+	return m.MockTeams[org], nil
+}
+
+// SetMockTeams sets the teams ListTeams returns for an organization
+func (m *MockClient) SetMockTeams(org string, teams []*github.Team) {
+	if m.MockTeams == nil {
+		m.MockTeams = make(map[string][]*github.Team)
+	}
+	m.MockTeams[org] = teams
+}
+
+// ListPRCommits implements GitHubClient.ListPRCommits for testing
+func (m *MockClient) ListPRCommits(repo string, number int) ([]*github.RepositoryCommit, error) {
+	m.logCall(fmt.Sprintf("ListPRCommits(%s, %d)", repo, number))
+
+	if m.AuthError != nil {
+		return nil, m.AuthError
+	}
+
+	if m.PRError != nil {
+		return nil, m.PRError
+	}
+
+	return m.MockCommits[fmt.Sprintf("%s#%d", repo, number)], nil
+}
+
+// SetMockCommits sets the commits ListPRCommits returns for a PR
+func (m *MockClient) SetMockCommits(repo string, number int, commits []*github.RepositoryCommit) {
+	if m.MockCommits == nil {
+		m.MockCommits = make(map[string][]*github.RepositoryCommit)
+	}
+	m.MockCommits[fmt.Sprintf("%s#%d", repo, number)] = commits
+}
+
+// listPRPages serves the repository's PRs in pages of PageSize, most recently
+// active first, recording each page in the call log. Like RestClient.ListPRs
+// it stops after the first page that ends before since.
+func (m *MockClient) listPRPages(repo string, since time.Time, keep func(*model.PR) bool) []*model.PR {
+	var prs []*model.PR
+	for _, pr := range m.MockPRs {
+		if pr.Repository == "" || pr.Repository == repo {
+			prs = append(prs, pr)
+		}
+	}
+	sort.SliceStable(prs, func(i, j int) bool {
+		return lastActivity(prs[i]).After(lastActivity(prs[j]))
+	})
+
+	var filteredPRs []*model.PR
+	for start, page := 0, 1; start < len(prs); start, page = start+m.PageSize, page+1 {
+		end := min(start+m.PageSize, len(prs))
+		m.logCall(fmt.Sprintf("ListPRsPage(%s, %d)", repo, page))
+		for _, pr := range prs[start:end] {
+			if keep(pr) {
+				filteredPRs = append(filteredPRs, pr)
+			}
+		}
+		if lastActivity(prs[end-1]).Before(since) {
+			break
+		}
+	}
+
+	return filteredPRs
+}
+
+// lastActivity approximates when a mock PR was last updated, which the mock
+// PRs do not record
+func lastActivity(pr *model.PR) time.Time {
+	latest := pr.CreatedAt
+	for _, t := range []*time.Time{pr.MergedAt, pr.ClosedAt} {
+		if t != nil && t.After(latest) {
+			latest = *t
+		}
+	}
+	return latest
+}
+
+// SetPageSize makes ListPRs serve PRs in pages of size, most recently active
+// first, so tests exercise the early end of paging
+func (m *MockClient) SetPageSize(size int) {
+	m.PageSize = size
+}
+
+// SetMockTeamRepos sets the repositories ListRepos returns for a team
+func (m *MockClient) SetMockTeamRepos(team string, repos []*github.Repository) {
+	if m.MockTeamRepos == nil {
+		m.MockTeamRepos = make(map[string][]*github.Repository)
+	}
+	m.MockTeamRepos[team] = repos
+}
+
 // SetMockRepos sets the mock repositories for testing
 func (m *MockClient) SetMockRepos(repos []*github.Repository) {
 	m.MockRepos = repos
@@ -121,6 +374,19 @@ func (m *MockClient) SetMockPRs(prs []*model.PR) {
 	m.MockPRs = prs
 }
 
+// SetMockLogin sets the authenticated user login for testing
+func (m *MockClient) SetMockLogin(login string) {
+	m.MockLogin = login
+}
+
+// SetMockRelease sets the latest release date of a repository for testing
+func (m *MockClient) SetMockRelease(repo string, published time.Time) {
+	if m.MockReleases == nil {
+		m.MockReleases = make(map[string]time.Time)
+	}
+	m.MockReleases[repo] = published
+}
+
 // SetAuthError sets an authentication error for testing
 func (m *MockClient) SetAuthError(err error) {
 	m.AuthError = err
@@ -138,10 +404,21 @@ func (m *MockClient) SetPRError(err error) {
 
 // GetCallLog returns the log of method calls for verification
 func (m *MockClient) GetCallLog() []string {
-	return m.CallLog
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.CallLog...)
 }
 
 // ClearCallLog clears the call log
 func (m *MockClient) ClearCallLog() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.CallLog = make([]string, 0)
 }
+
+// logCall records a method call in CallLog
+func (m *MockClient) logCall(call string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CallLog = append(m.CallLog, call)
+}
@@ -0,0 +1,105 @@
+package gh
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/willis7/prtool/internal/config"
+)
+
+// DefaultRepoCacheTTL is how long a cached repository listing is used when no
+// TTL is configured
+const DefaultRepoCacheTTL = 24 * time.Hour
+
+// RepoCache wraps a GitHubClient and persists its repository listings to a
+// file, so runs within the TTL reuse the listing instead of listing every
+// repository again. The cache is best-effort: an unreadable file is treated
+// as empty and write failures are ignored.
+type RepoCache struct {
+	GitHubClient
+	path    string
+	ttl     time.Duration
+	refresh bool
+	now     func() time.Time
+}
+
+// repoCacheEntry is a cached listing for one scope
+type repoCacheEntry struct {
+	FetchedAt time.Time            `json:"fetched_at"`
+	Repos     []*github.Repository `json:"repos"`
+}
+
+// NewRepoCache creates a repository listing cache in the file at path around
+// client. Listings older than ttl are refreshed, and refresh ignores the
+// cached listings altogether.
+func NewRepoCache(client GitHubClient, path string, ttl time.Duration, refresh bool) *RepoCache {
+	return &RepoCache{
+		GitHubClient: client,
+		path:         path,
+		ttl:          ttl,
+		refresh:      refresh,
+		now:          time.Now,
+	}
+}
+
+// ListRepos implements GitHubClient.ListRepos, returning the cached listing
+// for the scope while it is fresh
+func (c *RepoCache) ListRepos(scope *config.Config) ([]*github.Repository, error) {
+	if scope == nil {
+		return c.GitHubClient.ListRepos(scope)
+	}
+
+	key := repoCacheKey(scope)
+	entries := c.load()
+	if entry, ok := entries[key]; ok && !c.refresh && c.now().Sub(entry.FetchedAt) < c.ttl {
+		return entry.Repos, nil
+	}
+
+	repos, err := c.GitHubClient.ListRepos(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	entries[key] = repoCacheEntry{FetchedAt: c.now().UTC(), Repos: repos}
+	c.save(entries)
+	return repos, nil
+}
+
+// load reads the cached listings, returning an empty set when the file is
+// missing or unreadable
+func (c *RepoCache) load() map[string]repoCacheEntry {
+	entries := make(map[string]repoCacheEntry)
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return entries
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return make(map[string]repoCacheEntry)
+	}
+	return entries
+}
+
+// save writes the cached listings, ignoring failures
+func (c *RepoCache) save(entries map[string]repoCacheEntry) {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return
+	}
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return
+		}
+	}
+	_ = os.WriteFile(c.path, data, 0644)
+}
+
+// repoCacheKey identifies the scope a listing was made for
+func repoCacheKey(scope *config.Config) string {
+	return fmt.Sprintf("org=%s team=%s user=%s repo=%s",
+		scope.Org, strings.Join(scope.Team, ","), scope.User, scope.Repo)
+}
@@ -0,0 +1,90 @@
+package gh
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/willis7/prtool/internal/config"
+)
+
+func TestRepoCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "repos.json")
+	scope := &config.Config{Org: "test-org"}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	listCalls := func(m *MockClient) int {
+		n := 0
+		for _, call := range m.CallLog {
+			if strings.HasPrefix(call, "ListRepos(") {
+				n++
+			}
+		}
+		return n
+	}
+	newCache := func(m *MockClient, at time.Time, refresh bool) *RepoCache {
+		cache := NewRepoCache(m, path, time.Hour, refresh)
+		cache.now = func() time.Time { return at }
+		return cache
+	}
+
+	// A cold cache lists the scope and persists it
+	cold := NewMockClient()
+	cold.SetMockRepos([]*github.Repository{
+		{FullName: github.String("test-org/api"), Topics: []string{"backend"}, StargazersCount: github.Int(7)},
+	})
+	if _, err := newCache(cold, now, false).ListRepos(scope); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if listCalls(cold) != 1 {
+		t.Fatalf("Expected a cold cache to list repositories once, got %d calls", listCalls(cold))
+	}
+
+	t.Run("fresh cache skips listing", func(t *testing.T) {
+		warm := NewMockClient()
+		repos, err := newCache(warm, now.Add(30*time.Minute), false).ListRepos(scope)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if listCalls(warm) != 0 {
+			t.Errorf("Expected no ListRepos call with a fresh cache, got %v", warm.CallLog)
+		}
+		if len(repos) != 1 || repos[0].GetFullName() != "test-org/api" || repos[0].GetStargazersCount() != 7 || len(repos[0].Topics) != 1 {
+			t.Errorf("Expected the cached repository with its metadata, got %+v", repos)
+		}
+	})
+
+	t.Run("other scope is listed", func(t *testing.T) {
+		other := NewMockClient()
+		if _, err := newCache(other, now, false).ListRepos(&config.Config{User: "octocat"}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if listCalls(other) != 1 {
+			t.Errorf("Expected a scope missing from the cache to be listed, got %d calls", listCalls(other))
+		}
+	})
+
+	t.Run("refresh lists again", func(t *testing.T) {
+		refreshed := NewMockClient()
+		if _, err := newCache(refreshed, now, true).ListRepos(scope); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if listCalls(refreshed) != 1 {
+			t.Errorf("Expected --refresh-repos to list repositories, got %d calls", listCalls(refreshed))
+		}
+	})
+
+	t.Run("stale cache lists again", func(t *testing.T) {
+		stale := NewMockClient()
+		stale.SetMockRepos([]*github.Repository{{FullName: github.String("test-org/web")}})
+		repos, err := newCache(stale, now.Add(2*time.Hour), false).ListRepos(scope)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if listCalls(stale) != 1 || len(repos) != 1 || repos[0].GetFullName() != "test-org/web" {
+			t.Errorf("Expected a stale cache to be refreshed, got %+v after %v", repos, stale.CallLog)
+		}
+	})
+}
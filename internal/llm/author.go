@@ -0,0 +1,50 @@
+package llm
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// unknownAuthor names the group of PRs without an author, matching the
+// author groups of the rendered report
+const unknownAuthor = "unknown"
+
+// authorInstruction asks the LLM for a short blurb about one author's PRs
+const authorInstruction = "All of these pull requests were merged by %s. Summarize what they worked on in two or three sentences."
+
+// SummariseByAuthor summarises the PRs of each author separately, with up to
+// concurrency calls in flight, and returns the summaries keyed by author
+func SummariseByAuthor(client LLM, prs []*model.PR, concurrency int) (map[string]string, error) {
+	byAuthor := make(map[string][]*model.PR)
+	for _, pr := range prs {
+		author := pr.Author
+		if author == "" {
+			author = unknownAuthor
+		}
+		byAuthor[author] = append(byAuthor[author], pr)
+	}
+
+	authors := make([]string, 0, len(byAuthor))
+	for author := range byAuthor {
+		authors = append(authors, author)
+	}
+	sort.Strings(authors)
+
+	contexts := make([]string, len(authors))
+	for i, author := range authors {
+		contexts[i] = BuildContext(byAuthor[author]) + "\n" + fmt.Sprintf(authorInstruction, author) + "\n"
+	}
+
+	summaries, err := SummariseChunks(client, contexts, concurrency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarise PRs by author: %w", err)
+	}
+
+	byName := make(map[string]string, len(authors))
+	for i, author := range authors {
+		byName[author] = summaries[i]
+	}
+	return byName, nil
+}
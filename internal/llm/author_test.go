@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// authorLLM answers each call with the author named in the context, and
+// records the calls; it is safe for concurrent use
+type authorLLM struct {
+	mu       sync.Mutex
+	contexts []string
+}
+
+func (a *authorLLM) Summarise(context string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.contexts = append(a.contexts, context)
+	for _, author := range []string{"alice", "bob", "unknown"} {
+		if strings.Contains(context, "merged by "+author+".") {
+			return author + " shipped things", nil
+		}
+	}
+	return "", nil
+}
+
+func TestSummariseByAuthor(t *testing.T) {
+	prs := []*model.PR{
+		{Title: "Add retries", Author: "alice", Number: 1},
+		{Title: "Fix login", Author: "bob", Number: 2},
+		{Title: "Add metrics", Author: "alice", Number: 3},
+		{Title: "Ghost PR", Number: 4},
+	}
+
+	client := &authorLLM{}
+	summaries, err := SummariseByAuthor(client, prs, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(client.contexts) != 3 {
+		t.Errorf("Expected one LLM call per author, got %d", len(client.contexts))
+	}
+	for _, author := range []string{"alice", "bob", "unknown"} {
+		if summaries[author] != author+" shipped things" {
+			t.Errorf("Expected the summary for %s, got %q", author, summaries[author])
+		}
+	}
+
+	for _, context := range client.contexts {
+		if strings.Contains(context, "merged by alice.") {
+			if !strings.Contains(context, "Add retries") || !strings.Contains(context, "Add metrics") || strings.Contains(context, "Fix login") {
+				t.Errorf("Expected only alice's PRs in her context, got:\n%s", context)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package llm
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// EstimateTokens returns a rough token count for text using the chars/4 heuristic
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// BuildContextWithBudget builds the LLM context, trimming it until its
+// estimated token count fits within maxTokens. PR descriptions are dropped
+// first, then whole PRs, least recently merged first. It returns the context
+// and a description of everything that was trimmed. A maxTokens of 0 or less
+// disables trimming. The input PRs are never modified.
+func BuildContextWithBudget(prs []*model.PR, maxTokens int) (string, []string) {
+	context := BuildContext(prs)
+	if maxTokens <= 0 || EstimateTokens(context) <= maxTokens {
+		return context, nil
+	}
+
+	// Work on copies so rendering still sees the full PRs
+	working := make([]*model.PR, len(prs))
+	for i, pr := range prs {
+		prCopy := *pr
+		working[i] = &prCopy
+	}
+
+	// Trim candidates in order of least recent first
+	oldestFirst := make([]*model.PR, len(working))
+	copy(oldestFirst, working)
+	sort.SliceStable(oldestFirst, func(i, j int) bool {
+		return mergedTime(oldestFirst[i]).Before(mergedTime(oldestFirst[j]))
+	})
+
+	var trimmed []string
+
+	// Drop descriptions first
+	for _, pr := range oldestFirst {
+		if pr.Body == "" {
+			continue
+		}
+		pr.Body = ""
+		trimmed = append(trimmed, fmt.Sprintf("description of %s", pr.SlugRef()))
+
+		context = BuildContext(working)
+		if EstimateTokens(context) <= maxTokens {
+			return context, trimmed
+		}
+	}
+
+	// Then drop whole PRs
+	dropped := make(map[*model.PR]bool)
+	for _, pr := range oldestFirst {
+		dropped[pr] = true
+		trimmed = append(trimmed, pr.SlugRef())
+
+		var remaining []*model.PR
+		for _, candidate := range working {
+			if !dropped[candidate] {
+				remaining = append(remaining, candidate)
+			}
+		}
+
+		context = BuildContext(remaining)
+		if EstimateTokens(context) <= maxTokens {
+			break
+		}
+	}
+
+	return context, trimmed
+}
+
+// mergedTime returns the merge time of a PR, or the zero time if it is unmerged
+func mergedTime(pr *model.PR) time.Time {
+	if pr.MergedAt == nil {
+		return time.Time{}
+	}
+	return *pr.MergedAt
+}
@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"abc", 1},
+		{"abcd", 1},
+		{"abcde", 2},
+		{strings.Repeat("a", 400), 100},
+	}
+
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.expected {
+			t.Errorf("EstimateTokens(%d chars) = %d, want %d", len(tt.text), got, tt.expected)
+		}
+	}
+}
+
+func TestBuildContextWithBudget(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	makePRs := func() []*model.PR {
+		var prs []*model.PR
+		for i := 0; i < 20; i++ {
+			mergedAt := base.AddDate(0, 0, i)
+			prs = append(prs, &model.PR{
+				Title:      fmt.Sprintf("Change number %d", i),
+				Author:     "dev",
+				Repository: "acme/api",
+				Number:     i + 1,
+				MergedAt:   &mergedAt,
+				Body:       strings.Repeat("Lots of detail about this change. ", 10),
+			})
+		}
+		return prs
+	}
+
+	t.Run("under budget is untouched", func(t *testing.T) {
+		prs := makePRs()
+		context, trimmed := BuildContextWithBudget(prs, 1000000)
+		if len(trimmed) != 0 {
+			t.Errorf("Expected nothing trimmed, got %v", trimmed)
+		}
+		if context != BuildContext(prs) {
+			t.Error("Expected context to match BuildContext output")
+		}
+	})
+
+	t.Run("zero budget disables trimming", func(t *testing.T) {
+		_, trimmed := BuildContextWithBudget(makePRs(), 0)
+		if len(trimmed) != 0 {
+			t.Errorf("Expected nothing trimmed, got %v", trimmed)
+		}
+	})
+
+	t.Run("descriptions are dropped oldest first", func(t *testing.T) {
+		prs := makePRs()
+		full := EstimateTokens(BuildContext(prs))
+		budget := full - 100
+
+		context, trimmed := BuildContextWithBudget(prs, budget)
+		if EstimateTokens(context) > budget {
+			t.Errorf("Expected context under %d tokens, got %d", budget, EstimateTokens(context))
+		}
+		if len(trimmed) == 0 {
+			t.Fatal("Expected some content to be trimmed")
+		}
+		if trimmed[0] != "description of acme/api#1" {
+			t.Errorf("Expected the oldest description to be trimmed first, got %q", trimmed[0])
+		}
+		if !strings.Contains(context, "Change number 0") {
+			t.Error("Expected all PR titles to remain when dropping descriptions suffices")
+		}
+		if !strings.Contains(context, "Description: Lots of detail") {
+			t.Error("Expected recent descriptions to remain")
+		}
+	})
+
+	t.Run("whole PRs are dropped when descriptions are not enough", func(t *testing.T) {
+		prs := makePRs()
+		budget := 200
+
+		context, trimmed := BuildContextWithBudget(prs, budget)
+		if EstimateTokens(context) > budget {
+			t.Errorf("Expected context under %d tokens, got %d", budget, EstimateTokens(context))
+		}
+		if strings.Contains(context, "Change number 0\n") {
+			t.Error("Expected the oldest PR to be dropped")
+		}
+		if !strings.Contains(context, "Change number 19") {
+			t.Error("Expected the most recent PR to be kept")
+		}
+		if !strings.Contains(strings.Join(trimmed, ","), "acme/api#1,") {
+			t.Errorf("Expected the oldest PR to be listed as trimmed, got %v", trimmed)
+		}
+	})
+
+	t.Run("input PRs are not modified", func(t *testing.T) {
+		prs := makePRs()
+		BuildContextWithBudget(prs, 50)
+		for _, pr := range prs {
+			if pr.Body == "" {
+				t.Fatal("Expected original PR bodies to be preserved")
+			}
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CachedLLM wraps an LLM and stores its summaries on disk, keyed by a hash of
+// the context, so identical contexts reuse the earlier summary instead of
+// calling the provider again. The cache is best-effort: read and write
+// failures fall through to the wrapped client.
+type CachedLLM struct {
+	client LLM
+	dir    string
+	key    string
+}
+
+// NewCachedLLM creates a summary cache in dir around client. key identifies
+// the provider and model, so switching either does not reuse summaries.
+func NewCachedLLM(client LLM, dir, key string) *CachedLLM {
+	return &CachedLLM{
+		client: client,
+		dir:    dir,
+		key:    key,
+	}
+}
+
+// Summarise implements the LLM interface, returning a cached summary when the
+// same context was summarised before
+func (c *CachedLLM) Summarise(context string) (string, error) {
+	path := c.path(context)
+	if cached, err := os.ReadFile(path); err == nil {
+		return string(cached), nil
+	}
+
+	summary, err := c.client.Summarise(context)
+	if err != nil {
+		return "", err
+	}
+
+	// Truncated summaries are not cached so a later run can do better
+	if !strings.HasSuffix(summary, TruncatedMarker) {
+		if err := os.MkdirAll(c.dir, 0755); err == nil {
+			_ = os.WriteFile(path, []byte(summary), 0644)
+		}
+	}
+
+	return summary, nil
+}
+
+// path returns the cache file for a context
+func (c *CachedLLM) path(context string) string {
+	sum := sha256.Sum256([]byte(c.key + "\x00" + context))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".txt")
+}
@@ -0,0 +1,77 @@
+package llm
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// countingLLM records how many summaries were requested
+type countingLLM struct {
+	calls   int
+	summary string
+	err     error
+}
+
+func (c *countingLLM) Summarise(context string) (string, error) {
+	c.calls++
+	return c.summary, c.err
+}
+
+func TestCachedLLM(t *testing.T) {
+	dir := t.TempDir()
+	client := &countingLLM{summary: "Weekly summary"}
+	context := BuildContext(nil)
+
+	first, err := NewCachedLLM(client, dir, "openai/gpt-4").Summarise(context)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// A second run with identical PRs reuses the summary
+	second, err := NewCachedLLM(client, dir, "openai/gpt-4").Summarise(context)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first != "Weekly summary" || second != first {
+		t.Errorf("Expected the cached summary, got %q and %q", first, second)
+	}
+	if client.calls != 1 {
+		t.Errorf("Expected 1 LLM call, got %d", client.calls)
+	}
+
+	// A different context or model misses the cache
+	if _, err := NewCachedLLM(client, dir, "openai/gpt-4").Summarise(context + "changed"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := NewCachedLLM(client, dir, "ollama/llama3.2").Summarise(context); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if client.calls != 3 {
+		t.Errorf("Expected 3 LLM calls, got %d", client.calls)
+	}
+}
+
+func TestCachedLLM_SkipsErrorsAndTruncatedSummaries(t *testing.T) {
+	dir := t.TempDir()
+
+	failing := &countingLLM{err: errors.New("rate limited")}
+	if _, err := NewCachedLLM(failing, dir, "openai/").Summarise("ctx"); err == nil {
+		t.Fatal("Expected the LLM error to be returned")
+	}
+
+	truncated := &countingLLM{summary: "Cut short " + TruncatedMarker}
+	cache := NewCachedLLM(truncated, dir, "openai/")
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Summarise("ctx"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if truncated.calls != 2 {
+		t.Errorf("Expected truncated summaries not to be cached, got %d call(s)", truncated.calls)
+	}
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected an empty cache, got %d file(s)", len(entries))
+	}
+}
@@ -0,0 +1,156 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// ChunkPRs splits prs into consecutive chunks whose context fits within
+// chunkTokens estimated tokens. A PR that does not fit on its own gets a
+// chunk of its own. A chunkTokens of 0 or less returns a single chunk.
+func ChunkPRs(prs []*model.PR, chunkTokens int) [][]*model.PR {
+	if len(prs) == 0 {
+		return nil
+	}
+	if chunkTokens <= 0 {
+		return [][]*model.PR{prs}
+	}
+
+	var chunks [][]*model.PR
+	var current []*model.PR
+	for _, pr := range prs {
+		candidate := append(current[:len(current):len(current)], pr)
+		if len(current) > 0 && EstimateTokens(BuildContext(candidate)) > chunkTokens {
+			chunks = append(chunks, current)
+			candidate = []*model.PR{pr}
+		}
+		current = candidate
+	}
+	return append(chunks, current)
+}
+
+// SummariseChunks summarises each context with client, running at most
+// concurrency calls at once. Summaries are returned in the order of contexts.
+// If any call fails, the error of the earliest failing chunk is returned.
+func SummariseChunks(client LLM, contexts []string, concurrency int) ([]string, error) {
+	summaries, errs := summariseEach(client, contexts, concurrency)
+	for i, err := range errs {
+		if err != nil {
+			return nil, chunkError(i, len(contexts), err)
+		}
+	}
+	return summaries, nil
+}
+
+// SummariseChunksLenient is SummariseChunks that skips failed chunks instead
+// of failing. It returns the successful summaries in order and the number of
+// chunks skipped, and only fails when every chunk fails.
+func SummariseChunksLenient(client LLM, contexts []string, concurrency int) ([]string, int, error) {
+	summaries, errs := summariseEach(client, contexts, concurrency)
+	var kept []string
+	var firstErr error
+	for i, err := range errs {
+		if err != nil {
+			if firstErr == nil {
+				firstErr = chunkError(i, len(contexts), err)
+			}
+			continue
+		}
+		kept = append(kept, summaries[i])
+	}
+	if len(kept) == 0 && firstErr != nil {
+		return nil, 0, firstErr
+	}
+	return kept, len(contexts) - len(kept), nil
+}
+
+// summariseEach summarises each context with client, running at most
+// concurrency calls at once, and returns the summaries and errors in order
+func summariseEach(client LLM, contexts []string, concurrency int) ([]string, []error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	summaries := make([]string, len(contexts))
+	errs := make([]error, len(contexts))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, context := range contexts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, context string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			summaries[i], errs[i] = client.Summarise(context)
+		}(i, context)
+	}
+	wg.Wait()
+
+	return summaries, errs
+}
+
+// chunkError describes the failure of the chunk at index i
+func chunkError(i, total int, err error) error {
+	return fmt.Errorf("failed to summarise chunk %d of %d: %w", i+1, total, err)
+}
+
+// skippedChunksNote ends a summary that left out failed chunks
+const skippedChunksNote = "_Note: %d of %d chunks of pull requests could not be summarised and are missing from this summary._"
+
+// SummariseChunked summarises prs in chunks of at most chunkTokens estimated
+// tokens, with up to concurrency chunk calls in flight, then reduces the
+// chunk summaries into one summary with a final call. style is applied to
+// the final call only. A single chunk is summarised directly.
+func SummariseChunked(client LLM, prs []*model.PR, chunkTokens, concurrency int, style string) (string, error) {
+	return SummariseChunkedWith(client, client, prs, chunkTokens, concurrency, style, false)
+}
+
+// SummariseChunkedWith is SummariseChunked with separate clients for the
+// chunk summaries and the final call, e.g. a cheaper model for the chunks. A
+// single chunk is summarised directly with reduceClient. When lenient, failed
+// chunks are left out and a note saying how many were skipped ends the
+// summary.
+func SummariseChunkedWith(chunkClient, reduceClient LLM, prs []*model.PR, chunkTokens, concurrency int, style string, lenient bool) (string, error) {
+	chunks := ChunkPRs(prs, chunkTokens)
+	if len(chunks) <= 1 {
+		return reduceClient.Summarise(ApplySummaryStyle(BuildContext(prs), style))
+	}
+
+	contexts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		contexts[i] = BuildContext(chunk)
+	}
+
+	if !lenient {
+		summaries, err := SummariseChunks(chunkClient, contexts, concurrency)
+		if err != nil {
+			return "", err
+		}
+		return reduceClient.Summarise(ApplySummaryStyle(BuildReduceContext(summaries), style))
+	}
+
+	summaries, skipped, err := SummariseChunksLenient(chunkClient, contexts, concurrency)
+	if err != nil {
+		return "", err
+	}
+	summary, err := reduceClient.Summarise(ApplySummaryStyle(BuildReduceContext(summaries), style))
+	if err != nil || skipped == 0 {
+		return summary, err
+	}
+	return strings.TrimRight(summary, "\n") + "\n\n" + fmt.Sprintf(skippedChunksNote, skipped, len(contexts)), nil
+}
+
+// BuildReduceContext combines chunk summaries, in order, into the context
+// for the final summary call
+func BuildReduceContext(summaries []string) string {
+	var b strings.Builder
+	b.WriteString("The pull requests were summarised in parts. Combine these partial summaries into a single summary:\n\n")
+	for i, summary := range summaries {
+		fmt.Fprintf(&b, "Part %d:\n%s\n\n", i+1, strings.TrimSpace(summary))
+	}
+	return b.String()
+}
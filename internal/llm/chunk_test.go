@@ -0,0 +1,203 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// concurrencyLLM echoes the first line of each context and records how many
+// calls were in flight at once
+type concurrencyLLM struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	calls       []string
+}
+
+func (c *concurrencyLLM) Summarise(context string) (string, error) {
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.maxInFlight {
+		c.maxInFlight = c.inFlight
+	}
+	c.calls = append(c.calls, context)
+	c.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+
+	return "summary of " + strings.SplitN(context, "\n", 2)[0], nil
+}
+
+func TestChunkPRs(t *testing.T) {
+	var prs []*model.PR
+	for i := 1; i <= 5; i++ {
+		prs = append(prs, &model.PR{Title: fmt.Sprintf("PR %d", i), Author: "alice", Repository: "acme/api"})
+	}
+
+	if chunks := ChunkPRs(prs, 0); len(chunks) != 1 || len(chunks[0]) != 5 {
+		t.Errorf("Expected a single chunk without a limit, got %d", len(chunks))
+	}
+
+	// Size the limit so exactly two PRs fit per chunk
+	limit := EstimateTokens(BuildContext(prs[:2]))
+	chunks := ChunkPRs(prs, limit)
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d", len(chunks))
+	}
+	var order []string
+	for _, chunk := range chunks {
+		if EstimateTokens(BuildContext(chunk)) > limit {
+			t.Errorf("Expected chunk to fit within %d tokens", limit)
+		}
+		for _, pr := range chunk {
+			order = append(order, pr.Title)
+		}
+	}
+	if got := strings.Join(order, ","); got != "PR 1,PR 2,PR 3,PR 4,PR 5" {
+		t.Errorf("Expected PRs to keep their order across chunks, got %s", got)
+	}
+
+	// A PR larger than the limit still gets a chunk
+	if chunks := ChunkPRs(prs[:1], 1); len(chunks) != 1 || len(chunks[0]) != 1 {
+		t.Errorf("Expected an oversized PR in its own chunk, got %v", chunks)
+	}
+}
+
+func TestSummariseChunks_ConcurrentAndOrdered(t *testing.T) {
+	var contexts []string
+	for i := 1; i <= 8; i++ {
+		contexts = append(contexts, fmt.Sprintf("chunk %d\ndetails", i))
+	}
+
+	client := &concurrencyLLM{}
+	summaries, err := SummariseChunks(client, contexts, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i, summary := range summaries {
+		if want := fmt.Sprintf("summary of chunk %d", i+1); summary != want {
+			t.Errorf("Expected summary %d to be %q, got %q", i, want, summary)
+		}
+	}
+	if client.maxInFlight > 3 {
+		t.Errorf("Expected at most 3 concurrent calls, got %d", client.maxInFlight)
+	}
+	if client.maxInFlight < 2 {
+		t.Errorf("Expected chunk calls to run concurrently, got %d in flight", client.maxInFlight)
+	}
+}
+
+func TestSummariseChunks_Error(t *testing.T) {
+	_, err := SummariseChunks(NewStubLLMWithError(fmt.Errorf("rate limited")), []string{"a", "b"}, 2)
+	if err == nil || !strings.Contains(err.Error(), "chunk 1 of 2") {
+		t.Errorf("Expected error naming the first chunk, got %v", err)
+	}
+}
+
+func TestSummariseChunked(t *testing.T) {
+	var prs []*model.PR
+	for i := 1; i <= 4; i++ {
+		prs = append(prs, &model.PR{Title: fmt.Sprintf("PR %d", i), Author: "alice", Repository: "acme/api"})
+	}
+
+	client := &concurrencyLLM{}
+	summary, err := SummariseChunked(client, prs, EstimateTokens(BuildContext(prs[:1])), 4, SummaryStyleBullets)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Four chunk calls plus the reduce call
+	if len(client.calls) != 5 {
+		t.Fatalf("Expected 5 LLM calls, got %d", len(client.calls))
+	}
+	reduce := client.calls[4]
+	if !strings.Contains(reduce, bulletInstruction) {
+		t.Error("Expected the summary style to be applied to the reduce call")
+	}
+	if !strings.HasPrefix(summary, "summary of The pull requests were summarised in parts") {
+		t.Errorf("Expected the final summary to come from the reduce call, got %q", summary)
+	}
+	last := -1
+	for i := 1; i <= 4; i++ {
+		idx := strings.Index(reduce, fmt.Sprintf("Part %d:\n", i))
+		if idx <= last {
+			t.Errorf("Expected part %d to follow part %d in the reduce context", i, i-1)
+		}
+		last = idx
+	}
+}
+
+func TestSummariseChunkedWith(t *testing.T) {
+	var prs []*model.PR
+	for i := 1; i <= 3; i++ {
+		prs = append(prs, &model.PR{Title: fmt.Sprintf("PR %d", i), Author: "alice", Repository: "acme/api"})
+	}
+
+	chunkClient, reduceClient := &concurrencyLLM{}, &concurrencyLLM{}
+	if _, err := SummariseChunkedWith(chunkClient, reduceClient, prs, EstimateTokens(BuildContext(prs[:1])), 2, "", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(chunkClient.calls) != 3 {
+		t.Errorf("Expected the chunk client to summarise 3 chunks, got %d call(s)", len(chunkClient.calls))
+	}
+	for _, call := range chunkClient.calls {
+		if strings.Contains(call, "summarised in parts") {
+			t.Error("Expected the chunk client not to make the reduce call")
+		}
+	}
+	if len(reduceClient.calls) != 1 || !strings.Contains(reduceClient.calls[0], "summarised in parts") {
+		t.Errorf("Expected the reduce client to make only the reduce call, got %q", reduceClient.calls)
+	}
+}
+
+// failingChunkLLM fails for contexts containing fail and echoes the others
+type failingChunkLLM struct {
+	fail string
+}
+
+func (f *failingChunkLLM) Summarise(context string) (string, error) {
+	if strings.Contains(context, f.fail) {
+		return "", fmt.Errorf("rate limited")
+	}
+	return "summary of " + strings.SplitN(context, "\n", 2)[0], nil
+}
+
+func TestSummariseChunkedWith_Lenient(t *testing.T) {
+	var prs []*model.PR
+	for i := 1; i <= 3; i++ {
+		prs = append(prs, &model.PR{Title: fmt.Sprintf("PR %d", i), Author: "alice", Repository: "acme/api"})
+	}
+	client := &failingChunkLLM{fail: "PR 2"}
+	chunkTokens := EstimateTokens(BuildContext(prs[:1]))
+
+	if _, err := SummariseChunkedWith(client, client, prs, chunkTokens, 1, "", false); err == nil || !strings.Contains(err.Error(), "chunk 2 of 3") {
+		t.Errorf("Expected the strict mode to fail on chunk 2, got %v", err)
+	}
+
+	summary, err := SummariseChunkedWith(client, client, prs, chunkTokens, 1, "", true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(summary, "summary of The pull requests were summarised in parts") {
+		t.Errorf("Expected a summary from the reduce call, got %q", summary)
+	}
+	if !strings.HasSuffix(summary, "_Note: 1 of 3 chunks of pull requests could not be summarised and are missing from this summary._") {
+		t.Errorf("Expected a note on the skipped chunk, got %q", summary)
+	}
+
+	// Every chunk failing still fails
+	if _, err := SummariseChunkedWith(&failingChunkLLM{fail: "PR"}, client, prs, chunkTokens, 1, "", true); err == nil {
+		t.Error("Expected an error when every chunk fails")
+	}
+}
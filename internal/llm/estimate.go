@@ -0,0 +1,34 @@
+package llm
+
+// DefaultPricesPer1K are the approximate input prices, in US dollars per 1,000
+// tokens, used for cost estimates when no price is configured. Local and stub
+// providers are free.
+var DefaultPricesPer1K = map[string]float64{
+	ProviderOpenAI: 0.0025,
+	ProviderOllama: 0,
+	ProviderStub:   0,
+}
+
+// Estimate is the approximate size and cost of the LLM input for a report
+type Estimate struct {
+	PRs    int
+	Tokens int
+	// PricePer1K is the price per 1,000 tokens the cost is based on
+	PricePer1K float64
+	Cost       float64
+}
+
+// EstimateUsage estimates the input tokens and cost of summarising contexts,
+// one per LLM call, at pricePer1K dollars per 1,000 tokens
+func EstimateUsage(prCount int, contexts []string, pricePer1K float64) Estimate {
+	tokens := 0
+	for _, context := range contexts {
+		tokens += EstimateTokens(context)
+	}
+	return Estimate{
+		PRs:        prCount,
+		Tokens:     tokens,
+		PricePer1K: pricePer1K,
+		Cost:       float64(tokens) / 1000 * pricePer1K,
+	}
+}
@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// PR attributes that can be included in the LLM context
+const (
+	ContextFieldTitle      = "title"
+	ContextFieldAuthor     = "author"
+	ContextFieldRepository = "repository"
+	ContextFieldMerged     = "merged"
+	ContextFieldLabels     = "labels"
+	ContextFieldBody       = "body"
+	ContextFieldFiles      = "files"
+)
+
+// ContextFields lists the PR attributes that can be included in the LLM
+// context. All of them are included by default.
+var ContextFields = []string{
+	ContextFieldTitle, ContextFieldAuthor, ContextFieldRepository, ContextFieldMerged,
+	ContextFieldLabels, ContextFieldBody, ContextFieldFiles,
+}
+
+// ValidateContextFields checks that every field is a supported context field
+func ValidateContextFields(fields []string) error {
+	for _, field := range fields {
+		if !isContextField(field) {
+			return fmt.Errorf("invalid context field '%s' (supported: %s)", field, strings.Join(ContextFields, ", "))
+		}
+	}
+	return nil
+}
+
+func isContextField(field string) bool {
+	for _, f := range ContextFields {
+		if field == f {
+			return true
+		}
+	}
+	return false
+}
+
+// SelectContextFields returns copies of prs with every attribute not in
+// fields cleared, so BuildContext leaves it out. With no fields the PRs are
+// returned unchanged. The input PRs are never modified.
+func SelectContextFields(prs []*model.PR, fields []string) []*model.PR {
+	if len(fields) == 0 {
+		return prs
+	}
+
+	keep := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		keep[field] = true
+	}
+
+	selected := make([]*model.PR, len(prs))
+	for i, pr := range prs {
+		prCopy := *pr
+		if !keep[ContextFieldTitle] {
+			prCopy.Title = ""
+		}
+		if !keep[ContextFieldAuthor] {
+			prCopy.Author = ""
+		}
+		if !keep[ContextFieldRepository] {
+			prCopy.Repository = ""
+		}
+		if !keep[ContextFieldMerged] {
+			prCopy.MergedAt = nil
+		}
+		if !keep[ContextFieldLabels] {
+			prCopy.Labels = nil
+		}
+		if !keep[ContextFieldBody] {
+			prCopy.Body = ""
+		}
+		if !keep[ContextFieldFiles] {
+			prCopy.FilePaths = nil
+		}
+		selected[i] = &prCopy
+	}
+
+	return selected
+}
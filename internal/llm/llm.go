@@ -5,11 +5,13 @@ import (
 	stdcontext "context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"os"
 	"strings"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/willis7/prtool/internal/logger"
 	"github.com/willis7/prtool/internal/model"
 )
 
@@ -19,6 +21,30 @@ type LLM interface {
 	Summarise(context string) (string, error)
 }
 
+// Supported LLM providers
+const (
+	ProviderOpenAI = "openai"
+	ProviderOllama = "ollama"
+	ProviderStub   = "stub"
+)
+
+// Providers lists the supported LLM providers
+var Providers = []string{ProviderOpenAI, ProviderOllama, ProviderStub}
+
+// IsValidProvider reports whether provider is a supported LLM provider. The
+// empty string selects the stub.
+func IsValidProvider(provider string) bool {
+	if provider == "" {
+		return true
+	}
+	for _, p := range Providers {
+		if provider == p {
+			return true
+		}
+	}
+	return false
+}
+
 // StubLLM is a test implementation that returns a fixed summary
 type StubLLM struct {
 	summary string
@@ -54,6 +80,9 @@ func (s *StubLLM) Summarise(context string) (string, error) {
 	return s.summary, nil
 }
 
+// maxContextFiles caps how many changed files of a PR are listed in the context
+const maxContextFiles = 20
+
 // BuildContext creates a context string from PR data suitable for LLM processing
 func BuildContext(prs []*model.PR) string {
 	if len(prs) == 0 {
@@ -62,14 +91,29 @@ func BuildContext(prs []*model.PR) string {
 
 	var context string
 	context += "Pull Request Summary:\n\n"
+	if prs[0].Importance != "" {
+		context += "Each PR has a relative importance. Emphasize high importance PRs and mention low importance ones briefly, if at all.\n\n"
+	}
 
 	for i, pr := range prs {
-		context += fmt.Sprintf("%d. %s\n", i+1, pr.Title)
-		context += fmt.Sprintf("   Author: %s\n", pr.Author)
-		context += fmt.Sprintf("   Repository: %s\n", pr.Repository)
+		// Fields left out with SelectContextFields are empty
+		if pr.Title != "" {
+			context += fmt.Sprintf("%d. %s\n", i+1, pr.Title)
+		} else {
+			context += fmt.Sprintf("%d. PR #%d\n", i+1, pr.Number)
+		}
+		if pr.Author != "" {
+			context += fmt.Sprintf("   Author: %s\n", pr.Author)
+		}
+		if pr.Repository != "" {
+			context += fmt.Sprintf("   Repository: %s\n", pr.Repository)
+		}
+		if pr.Importance != "" {
+			context += fmt.Sprintf("   Importance: %s\n", importanceHint(pr))
+		}
 
-		if pr.MergedAt != nil {
-			context += fmt.Sprintf("   Merged: %s\n", pr.MergedAt.Format("2006-01-02"))
+		if pr.IsMerged() {
+			context += fmt.Sprintf("   Merged: %s\n", pr.MergedDate())
 		}
 
 		if len(pr.Labels) > 0 {
@@ -78,23 +122,62 @@ func BuildContext(prs []*model.PR) string {
 
 		if pr.Body != "" {
 			// Truncate body for context to avoid overly long prompts
-			body := pr.Body
-			if len(body) > 200 {
-				body = body[:200] + "..."
+			context += fmt.Sprintf("   Description: %s\n", pr.ShortBody(200))
+		}
+
+		if len(pr.FilePaths) > 0 {
+			files := pr.FilePaths
+			if len(files) > maxContextFiles {
+				files = append(files[:maxContextFiles:maxContextFiles], fmt.Sprintf("and %d more", len(pr.FilePaths)-maxContextFiles))
 			}
-			context += fmt.Sprintf("   Description: %s\n", body)
+			context += fmt.Sprintf("   Files: %s\n", strings.Join(files, ", "))
 		}
 
 		context += "\n"
 	}
 
-	return context
+	return sanitize(context)
+}
+
+// TruncatedMarker is appended to summaries that the provider cut off at its
+// output token limit
+const TruncatedMarker = "…(truncated)"
+
+// markTruncated warns that a summary was cut off and appends TruncatedMarker
+func markTruncated(log *logger.Logger, provider, summary string) string {
+	log.Warn("%s summary hit the output token limit and may be truncated", provider)
+	return summary + " " + TruncatedMarker
+}
+
+// DefaultTemperature keeps summaries close to deterministic, so reruns over
+// the same PRs read alike
+const DefaultTemperature = 0.2
+
+// defaultOpenAIMaxTokens caps OpenAI summaries when no maximum is configured
+const defaultOpenAIMaxTokens = 500
+
+// Sampling holds the generation settings sent with each summary request
+type Sampling struct {
+	// Temperature controls randomness; 0 is the most deterministic
+	Temperature float32
+	// MaxTokens caps the length of the summary (0 uses the provider default)
+	MaxTokens int
+	// TopP limits sampling to the most likely tokens with this cumulative
+	// probability (0 uses the provider default)
+	TopP float32
+}
+
+// DefaultSampling returns the sampling settings used when none are configured
+func DefaultSampling() Sampling {
+	return Sampling{Temperature: DefaultTemperature}
 }
 
 // OpenAILLM implements the LLM interface using OpenAI's API
 type OpenAILLM struct {
-	client *openai.Client
-	model  string
+	client   *openai.Client
+	model    string
+	sampling Sampling
+	log      *logger.Logger
 }
 
 // NewOpenAILLM creates a new OpenAI LLM client
@@ -106,18 +189,34 @@ func NewOpenAILLM(apiKey, model string) *OpenAILLM {
 	client := openai.NewClient(apiKey)
 
 	return &OpenAILLM{
-		client: client,
-		model:  model,
+		client:   client,
+		model:    model,
+		sampling: DefaultSampling(),
 	}
 }
 
+// SetSampling sets the generation settings of summary requests
+func (o *OpenAILLM) SetSampling(sampling Sampling) {
+	o.sampling = sampling
+}
+
 // Summarise implements the LLM interface for OpenAI
 func (o *OpenAILLM) Summarise(context string) (string, error) {
 	prompt := fmt.Sprintf(`Please provide a concise summary of the following pull requests. Focus on the key changes, impact, and any notable patterns or themes:
 
 %s
 
-Please provide a summary in 2-3 paragraphs that would be useful for a development team's periodic report.`, context)
+Unless a different format is requested above, please provide a summary in 2-3 paragraphs that would be useful for a development team's periodic report.`, context)
+
+	maxTokens := o.sampling.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = defaultOpenAIMaxTokens
+	}
+	// The client omits a zero temperature, which the API reads as 1
+	temperature := o.sampling.Temperature
+	if temperature == 0 {
+		temperature = math.SmallestNonzeroFloat32
+	}
 
 	resp, err := o.client.CreateChatCompletion(
 		stdcontext.Background(),
@@ -129,8 +228,9 @@ Please provide a summary in 2-3 paragraphs that would be useful for a developmen
 					Content: prompt,
 				},
 			},
-			MaxTokens:   500,
-			Temperature: 0.7,
+			MaxTokens:   maxTokens,
+			Temperature: temperature,
+			TopP:        o.sampling.TopP,
 		},
 	)
 	if err != nil {
@@ -141,28 +241,51 @@ Please provide a summary in 2-3 paragraphs that would be useful for a developmen
 		return "", fmt.Errorf("no response from OpenAI")
 	}
 
-	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+	summary := strings.TrimSpace(resp.Choices[0].Message.Content)
+	if resp.Choices[0].FinishReason == openai.FinishReasonLength {
+		summary = markTruncated(o.log, "OpenAI", summary)
+	}
+
+	return summary, nil
+}
+
+// SetLogger sets the logger used to warn about truncated summaries
+func (o *OpenAILLM) SetLogger(log *logger.Logger) {
+	o.log = log
 }
 
 // OllamaLLM implements the LLM interface using Ollama's local API
 type OllamaLLM struct {
-	baseURL string
-	model   string
-	client  *http.Client
+	baseURL  string
+	model    string
+	client   *http.Client
+	sampling Sampling
+	log      *logger.Logger
 }
 
 // OllamaRequest represents the request structure for Ollama API
 type OllamaRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Stream bool   `json:"stream"`
+	Model   string        `json:"model"`
+	Prompt  string        `json:"prompt"`
+	Stream  bool          `json:"stream"`
+	Options OllamaOptions `json:"options"`
+}
+
+// OllamaOptions are the generation options of an Ollama request. Zero
+// NumPredict and TopP use the model's defaults.
+type OllamaOptions struct {
+	Temperature float32 `json:"temperature"`
+	NumPredict  int     `json:"num_predict,omitempty"`
+	TopP        float32 `json:"top_p,omitempty"`
 }
 
 // OllamaResponse represents the response structure from Ollama API
 type OllamaResponse struct {
 	Response string `json:"response"`
 	Done     bool   `json:"done"`
-	Error    string `json:"error,omitempty"`
+	// DoneReason is "length" when generation stopped at the token limit
+	DoneReason string `json:"done_reason,omitempty"`
+	Error      string `json:"error,omitempty"`
 }
 
 // NewOllamaLLM creates a new Ollama LLM client
@@ -175,24 +298,35 @@ func NewOllamaLLM(baseURL, model string) *OllamaLLM {
 	}
 
 	return &OllamaLLM{
-		baseURL: baseURL,
-		model:   model,
-		client:  &http.Client{},
+		baseURL:  baseURL,
+		model:    model,
+		client:   &http.Client{},
+		sampling: DefaultSampling(),
 	}
 }
 
+// SetSampling sets the generation settings of summary requests
+func (o *OllamaLLM) SetSampling(sampling Sampling) {
+	o.sampling = sampling
+}
+
 // Summarise implements the LLM interface for Ollama
 func (o *OllamaLLM) Summarise(context string) (string, error) {
 	prompt := fmt.Sprintf(`Please provide a concise summary of the following pull requests. Focus on the key changes, impact, and any notable patterns or themes:
 
 %s
 
-Please provide a summary in 2-3 paragraphs that would be useful for a development team's periodic report.`, context)
+Unless a different format is requested above, please provide a summary in 2-3 paragraphs that would be useful for a development team's periodic report.`, context)
 
 	reqBody := OllamaRequest{
 		Model:  o.model,
 		Prompt: prompt,
 		Stream: false,
+		Options: OllamaOptions{
+			Temperature: o.sampling.Temperature,
+			NumPredict:  o.sampling.MaxTokens,
+			TopP:        o.sampling.TopP,
+		},
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -225,5 +359,15 @@ Please provide a summary in 2-3 paragraphs that would be useful for a developmen
 		return "", fmt.Errorf("ollama error: %s", ollamaResp.Error)
 	}
 
-	return strings.TrimSpace(ollamaResp.Response), nil
+	summary := strings.TrimSpace(ollamaResp.Response)
+	if !ollamaResp.Done || ollamaResp.DoneReason == "length" {
+		summary = markTruncated(o.log, "Ollama", summary)
+	}
+
+	return summary, nil
+}
+
+// SetLogger sets the logger used to warn about truncated summaries
+func (o *OllamaLLM) SetLogger(log *logger.Logger) {
+	o.log = log
 }
@@ -433,3 +433,85 @@ func TestOpenAILLM_Summarise_Integration(t *testing.T) {
 
 	t.Logf("OpenAI summary: %s", summary)
 }
+
+func TestBuildContext_ContextFields(t *testing.T) {
+	merged := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	prs := []*model.PR{{
+		Title:      "Add rate limiting",
+		Body:       "Limits login attempts per IP",
+		Author:     "alice",
+		Repository: "acme/api",
+		Number:     7,
+		MergedAt:   &merged,
+		Labels:     []string{"security"},
+		FilePaths:  []string{"limiter.go"},
+	}}
+
+	context := BuildContext(SelectContextFields(prs, []string{ContextFieldTitle, ContextFieldAuthor}))
+	for _, want := range []string{"1. Add rate limiting", "Author: alice"} {
+		if !strings.Contains(context, want) {
+			t.Errorf("Expected %q in context, got:\n%s", want, context)
+		}
+	}
+	for _, unwanted := range []string{"Limits login attempts", "security", "limiter.go", "acme/api", "Merged:"} {
+		if strings.Contains(context, unwanted) {
+			t.Errorf("Expected %q to be left out of the context, got:\n%s", unwanted, context)
+		}
+	}
+
+	if prs[0].Body == "" || len(prs[0].Labels) == 0 {
+		t.Error("Expected the original PRs to be unchanged")
+	}
+	if got := BuildContext(SelectContextFields(prs, nil)); got != BuildContext(prs) {
+		t.Error("Expected no fields to keep the full context")
+	}
+	if got := BuildContext(SelectContextFields(prs, []string{ContextFieldBody})); !strings.Contains(got, "1. PR #7") {
+		t.Errorf("Expected a PR number when the title is left out, got:\n%s", got)
+	}
+
+	if err := ValidateContextFields([]string{"title", "diff"}); err == nil || !strings.Contains(err.Error(), "'diff'") {
+		t.Errorf("Expected an invalid field error, got %v", err)
+	}
+}
+
+func TestBuildContext_WeightBy(t *testing.T) {
+	prs := []*model.PR{
+		{Title: "Fix typo", Number: 1, Additions: 1, Deletions: 1, Labels: []string{"docs"}},
+		{Title: "Rewrite scheduler", Number: 2, Additions: 900, Deletions: 400, Labels: []string{"Security"}},
+		{Title: "Add retries", Number: 3, Additions: 60, Deletions: 5},
+	}
+
+	bySize := BuildContext(WeightPRs(prs, WeightBySize))
+	for _, want := range []string{
+		"Emphasize high importance PRs",
+		"1. Fix typo\n   Importance: low (+1/-1 lines)",
+		"2. Rewrite scheduler\n   Importance: high (+900/-400 lines)",
+		"3. Add retries\n   Importance: medium (+60/-5 lines)",
+	} {
+		if !strings.Contains(bySize, want) {
+			t.Errorf("Expected %q in context, got:\n%s", want, bySize)
+		}
+	}
+
+	byLabels := BuildContext(WeightPRs(prs, WeightByLabels))
+	for _, want := range []string{
+		"1. Fix typo\n   Importance: low",
+		"2. Rewrite scheduler\n   Importance: high",
+		"3. Add retries\n   Importance: medium",
+	} {
+		if !strings.Contains(byLabels, want) {
+			t.Errorf("Expected %q in context, got:\n%s", want, byLabels)
+		}
+	}
+
+	if prs[0].Importance != "" {
+		t.Error("Expected the original PRs to be unchanged")
+	}
+	if got := BuildContext(WeightPRs(prs, "")); strings.Contains(got, "Importance") {
+		t.Errorf("Expected no importance hints without weighting, got:\n%s", got)
+	}
+	unsized := []*model.PR{{Title: "Add retries", Number: 3}}
+	if got := BuildContext(WeightPRs(unsized, WeightBySize)); strings.Contains(got, "Importance") {
+		t.Errorf("Expected no size hints without line counts, got:\n%s", got)
+	}
+}
@@ -0,0 +1,37 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// lengthInstruction is added to the context when retrying a summary that was
+// too short
+const lengthInstruction = "Your previous summary was too short. Write a complete summary of at least %d characters that covers the main changes, their themes and their impact."
+
+// SummariseWithMinLength asks client for a summary of context and, when it is
+// shorter than minChars characters, retries once with an explicit length
+// instruction. The retried summary is kept even when it is still too short,
+// in which case short is true. A minChars of 0 or less disables the check.
+func SummariseWithMinLength(client LLM, context string, minChars int) (summary string, short bool, err error) {
+	summary, err = client.Summarise(context)
+	if err != nil || minChars <= 0 || summaryLength(summary) >= minChars {
+		return summary, false, err
+	}
+
+	retried, err := client.Summarise(context + "\n" + fmt.Sprintf(lengthInstruction, minChars) + "\n")
+	if err != nil {
+		return "", false, err
+	}
+	if summaryLength(retried) < summaryLength(summary) {
+		retried = summary
+	}
+	return retried, summaryLength(retried) < minChars, nil
+}
+
+// summaryLength counts the characters of a summary, ignoring surrounding
+// whitespace
+func summaryLength(summary string) int {
+	return utf8.RuneCountInString(strings.TrimSpace(summary))
+}
@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+// sequenceLLM returns its responses in turn and records each context
+type sequenceLLM struct {
+	responses []string
+	contexts  []string
+}
+
+func (s *sequenceLLM) Summarise(context string) (string, error) {
+	s.contexts = append(s.contexts, context)
+	response := s.responses[0]
+	if len(s.responses) > 1 {
+		s.responses = s.responses[1:]
+	}
+	return response, nil
+}
+
+func TestSummariseWithMinLength(t *testing.T) {
+	adequate := "The team shipped rate limiting for the API and fixed two login bugs."
+
+	tests := []struct {
+		name      string
+		responses []string
+		minChars  int
+		expected  string
+		calls     int
+		short     bool
+	}{
+		{name: "adequate first time", responses: []string{adequate}, minChars: 20, expected: adequate, calls: 1},
+		{name: "short then adequate", responses: []string{"Done.", adequate}, minChars: 20, expected: adequate, calls: 2},
+		{name: "still short", responses: []string{"Done.", "Fixes."}, minChars: 20, expected: "Fixes.", calls: 2, short: true},
+		{name: "disabled", responses: []string{"Done."}, expected: "Done.", calls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &sequenceLLM{responses: tt.responses}
+			summary, short, err := SummariseWithMinLength(client, "PR context\n", tt.minChars)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if summary != tt.expected || short != tt.short {
+				t.Errorf("Expected (%q, %v), got (%q, %v)", tt.expected, tt.short, summary, short)
+			}
+			if len(client.contexts) != tt.calls {
+				t.Fatalf("Expected %d call(s), got %d", tt.calls, len(client.contexts))
+			}
+			if tt.calls == 2 && !strings.Contains(client.contexts[1], "at least 20 characters") {
+				t.Errorf("Expected the retry to ask for a longer summary, got %q", client.contexts[1])
+			}
+		})
+	}
+}
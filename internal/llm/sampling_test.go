@@ -0,0 +1,72 @@
+package llm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+func TestOpenAILLM_Summarise_Sampling(t *testing.T) {
+	var request openai.ChatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"The team shipped"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	config := openai.DefaultConfig("test-key")
+	config.BaseURL = server.URL + "/v1"
+	client := &OpenAILLM{client: openai.NewClientWithConfig(config), model: "gpt-test", sampling: DefaultSampling()}
+
+	if _, err := client.Summarise("context"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.Temperature != DefaultTemperature || request.MaxTokens != defaultOpenAIMaxTokens || request.TopP != 0 {
+		t.Errorf("Expected the default sampling, got temperature %g, max tokens %d, top_p %g", request.Temperature, request.MaxTokens, request.TopP)
+	}
+
+	client.SetSampling(Sampling{Temperature: 0.1, MaxTokens: 1200, TopP: 0.9})
+	if _, err := client.Summarise("context"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.Temperature != 0.1 || request.MaxTokens != 1200 || request.TopP != 0.9 {
+		t.Errorf("Expected the configured sampling, got temperature %g, max tokens %d, top_p %g", request.Temperature, request.MaxTokens, request.TopP)
+	}
+
+	// A zero temperature must still be sent
+	client.SetSampling(Sampling{Temperature: 0})
+	if _, err := client.Summarise("context"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if request.Temperature <= 0 || request.Temperature > 1e-6 {
+		t.Errorf("Expected a near-zero temperature to be sent, got %g", request.Temperature)
+	}
+}
+
+func TestOllamaLLM_Summarise_Sampling(t *testing.T) {
+	var request OllamaRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		_, _ = w.Write([]byte(`{"response":"The team shipped","done":true,"done_reason":"stop"}`))
+	}))
+	defer server.Close()
+
+	client := NewOllamaLLM(server.URL, "llama3.2")
+	client.SetSampling(Sampling{Temperature: 0, MaxTokens: 800, TopP: 0.5})
+
+	if _, err := client.Summarise("context"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := OllamaOptions{Temperature: 0, NumPredict: 800, TopP: 0.5}
+	if request.Options != expected {
+		t.Errorf("Expected options %+v, got %+v", expected, request.Options)
+	}
+}
@@ -0,0 +1,22 @@
+package llm
+
+import (
+	"strings"
+	"unicode"
+)
+
+// sanitize makes text safe to send to LLM APIs: invalid UTF-8 sequences are
+// replaced with U+FFFD and control characters other than newlines and tabs
+// are removed
+func sanitize(text string) string {
+	text = strings.ToValidUTF8(text, "�")
+	return strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' {
+			return r
+		}
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+}
@@ -0,0 +1,68 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "plain text unchanged", input: "Fix bug\n\tin parser", expected: "Fix bug\n\tin parser"},
+		{name: "NUL byte removed", input: "before\x00after", expected: "beforeafter"},
+		{name: "ANSI escape and bell removed", input: "\x1b[31mred\x1b[0m\a", expected: "[31mred[0m"},
+		{name: "carriage return removed", input: "line\r\n", expected: "line\n"},
+		{name: "invalid UTF-8 replaced", input: "bad \xff\xfe bytes", expected: "bad � bytes"},
+		{name: "valid multibyte kept", input: "café ✓", expected: "café ✓"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitize(tt.input); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBuildContext_Sanitizes(t *testing.T) {
+	prs := []*model.PR{
+		{
+			Title:      "Handle\x00 terminal output",
+			Author:     "alice",
+			Repository: "acme/api",
+			Body:       "$ make\x00\x07\nerror: \xc3\x28 invalid",
+		},
+	}
+
+	context := BuildContext(prs)
+
+	if !utf8.ValidString(context) {
+		t.Error("Expected context to be valid UTF-8")
+	}
+	if strings.ContainsAny(context, "\x00\x07") {
+		t.Errorf("Expected control characters to be stripped, got %q", context)
+	}
+	if !strings.Contains(context, "Handle terminal output") {
+		t.Errorf("Expected title text to be kept, got %q", context)
+	}
+	if !strings.Contains(context, "$ make\nerror: �( invalid") {
+		t.Errorf("Expected body newlines kept and invalid bytes replaced, got %q", context)
+	}
+}
+
+func TestBuildContext_TruncationKeepsValidUTF8(t *testing.T) {
+	// Truncating the body at 200 bytes would otherwise split the rune
+	body := strings.Repeat("a", 199) + "é" + strings.Repeat("b", 10)
+	context := BuildContext([]*model.PR{{Title: "T", Author: "a", Repository: "r", Body: body}})
+
+	if !utf8.ValidString(context) {
+		t.Error("Expected truncated context to be valid UTF-8")
+	}
+}
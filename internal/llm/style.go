@@ -0,0 +1,45 @@
+package llm
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Supported summary styles
+const (
+	SummaryStyleProse   = "prose"
+	SummaryStyleBullets = "bullets"
+)
+
+// bulletInstruction asks the LLM for a Markdown bullet list
+const bulletInstruction = "Format the summary as a Markdown bullet list: one \"- \" item per key change or theme, with no introductory or closing paragraphs."
+
+// ApplySummaryStyle adds the formatting instruction for style to an LLM
+// context. Prose (or an empty style) leaves the context unchanged.
+func ApplySummaryStyle(context, style string) string {
+	if style != SummaryStyleBullets {
+		return context
+	}
+	return context + "\n" + bulletInstruction + "\n"
+}
+
+// listMarker matches common list markers the LLM may use instead of "- "
+var listMarker = regexp.MustCompile(`^(?:[*+•]|\d+[.)])\s+`)
+
+// FormatBullets normalizes an LLM summary into a Markdown list so it renders
+// as bullets even when the model uses other markers or plain lines
+func FormatBullets(summary string) string {
+	var items []string
+	for _, line := range strings.Split(summary, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "- ") {
+			items = append(items, line)
+			continue
+		}
+		items = append(items, "- "+listMarker.ReplaceAllString(line, ""))
+	}
+	return strings.Join(items, "\n")
+}
@@ -0,0 +1,56 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplySummaryStyle(t *testing.T) {
+	context := BuildContext(nil)
+
+	bullets := ApplySummaryStyle(context, SummaryStyleBullets)
+	if !strings.HasPrefix(bullets, context) {
+		t.Error("Expected the PR context to be preserved")
+	}
+	if !strings.Contains(bullets, "Markdown bullet list") {
+		t.Errorf("Expected bullet instruction in context, got %q", bullets)
+	}
+
+	for _, style := range []string{"", SummaryStyleProse} {
+		if got := ApplySummaryStyle(context, style); got != context {
+			t.Errorf("Expected style %q to leave the context unchanged, got %q", style, got)
+		}
+	}
+}
+
+func TestFormatBullets(t *testing.T) {
+	tests := []struct {
+		name     string
+		summary  string
+		expected string
+	}{
+		{
+			name:     "already a list",
+			summary:  "- Added OAuth\n- Fixed login",
+			expected: "- Added OAuth\n- Fixed login",
+		},
+		{
+			name:     "other markers",
+			summary:  "* Added OAuth\n\n1. Fixed login\n• Faster queries",
+			expected: "- Added OAuth\n- Fixed login\n- Faster queries",
+		},
+		{
+			name:     "plain lines",
+			summary:  "Added OAuth\n  Fixed login  ",
+			expected: "- Added OAuth\n- Fixed login",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatBullets(tt.summary); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
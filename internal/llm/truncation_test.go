@@ -0,0 +1,108 @@
+package llm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/willis7/prtool/internal/logger"
+)
+
+// newTestLogger returns a logger writing to a file, and a func reading it back
+func newTestLogger(t *testing.T) (*logger.Logger, func() string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "prtool.log")
+	log, err := logger.New(false, true, path)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return log, func() string {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read log: %v", err)
+		}
+		return string(data)
+	}
+}
+
+func TestOpenAILLM_Summarise_Truncated(t *testing.T) {
+	tests := []struct {
+		name          string
+		finishReason  string
+		expectWarning bool
+	}{
+		{name: "stopped at length", finishReason: "length", expectWarning: true},
+		{name: "stopped normally", finishReason: "stop"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"The team shipped"},"finish_reason":"` + tt.finishReason + `"}]}`))
+			}))
+			defer server.Close()
+
+			config := openai.DefaultConfig("test-key")
+			config.BaseURL = server.URL + "/v1"
+			client := &OpenAILLM{client: openai.NewClientWithConfig(config), model: "gpt-test"}
+			log, readLog := newTestLogger(t)
+			client.SetLogger(log)
+
+			summary, err := client.Summarise("context")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			warned := strings.Contains(readLog(), "Warning: OpenAI summary hit the output token limit")
+			if warned != tt.expectWarning {
+				t.Errorf("Expected warning=%v, log was %q", tt.expectWarning, readLog())
+			}
+			if marked := strings.HasSuffix(summary, TruncatedMarker); marked != tt.expectWarning {
+				t.Errorf("Expected truncation marker=%v, got summary %q", tt.expectWarning, summary)
+			}
+		})
+	}
+}
+
+func TestOllamaLLM_Summarise_Truncated(t *testing.T) {
+	tests := []struct {
+		name          string
+		response      string
+		expectWarning bool
+	}{
+		{name: "done by length", response: `{"response":"The team shipped","done":true,"done_reason":"length"}`, expectWarning: true},
+		{name: "not done", response: `{"response":"The team shipped","done":false}`, expectWarning: true},
+		{name: "done normally", response: `{"response":"The team shipped","done":true,"done_reason":"stop"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client := NewOllamaLLM(server.URL, "")
+			log, readLog := newTestLogger(t)
+			client.SetLogger(log)
+
+			summary, err := client.Summarise("context")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			warned := strings.Contains(readLog(), "Warning: Ollama summary hit the output token limit")
+			if warned != tt.expectWarning {
+				t.Errorf("Expected warning=%v, log was %q", tt.expectWarning, readLog())
+			}
+			if marked := strings.HasSuffix(summary, TruncatedMarker); marked != tt.expectWarning {
+				t.Errorf("Expected truncation marker=%v, got summary %q", tt.expectWarning, summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package llm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// Ways of weighting PRs in the LLM context
+const (
+	WeightBySize   = "size"
+	WeightByLabels = "labels"
+)
+
+// WeightByValues lists the supported --weight-by values
+var WeightByValues = []string{WeightBySize, WeightByLabels}
+
+// Importance hints added to the LLM context
+const (
+	ImportanceHigh   = "high"
+	ImportanceMedium = "medium"
+	ImportanceLow    = "low"
+)
+
+// highPriorityLabels and lowPriorityLabels rank PRs for WeightByLabels.
+// Labels are compared ignoring case.
+var (
+	highPriorityLabels = []string{"breaking", "breaking-change", "security", "critical", "priority: high", "p0", "p1"}
+	lowPriorityLabels  = []string{"chore", "docs", "documentation", "dependencies", "typo", "trivial", "priority: low"}
+)
+
+// IsValidWeightBy reports whether weightBy is a supported value. Empty
+// disables weighting.
+func IsValidWeightBy(weightBy string) bool {
+	if weightBy == "" {
+		return true
+	}
+	for _, v := range WeightByValues {
+		if weightBy == v {
+			return true
+		}
+	}
+	return false
+}
+
+// WeightPRs returns copies of prs annotated with a relative Importance for
+// BuildContext. With size, the largest third of the PRs by changed lines is
+// high and the smallest third low; with labels, priority labels decide. The
+// input PRs are never modified.
+func WeightPRs(prs []*model.PR, weightBy string) []*model.PR {
+	if weightBy == "" || len(prs) == 0 {
+		return prs
+	}
+
+	weighted := make([]*model.PR, len(prs))
+	for i, pr := range prs {
+		prCopy := *pr
+		weighted[i] = &prCopy
+	}
+
+	switch weightBy {
+	case WeightBySize:
+		bySize := append([]*model.PR(nil), weighted...)
+		sort.SliceStable(bySize, func(i, j int) bool {
+			return changedLines(bySize[i]) > changedLines(bySize[j])
+		})
+		if changedLines(bySize[0]) == 0 {
+			// No line counts were fetched
+			return prs
+		}
+		high, low := (len(bySize)+2)/3, len(bySize)/3
+		for rank, pr := range bySize {
+			switch {
+			case rank < high:
+				pr.Importance = ImportanceHigh
+			case rank >= len(bySize)-low:
+				pr.Importance = ImportanceLow
+			default:
+				pr.Importance = ImportanceMedium
+			}
+		}
+	case WeightByLabels:
+		for _, pr := range weighted {
+			switch {
+			case hasAnyLabel(pr, highPriorityLabels):
+				pr.Importance = ImportanceHigh
+			case hasAnyLabel(pr, lowPriorityLabels):
+				pr.Importance = ImportanceLow
+			default:
+				pr.Importance = ImportanceMedium
+			}
+		}
+	}
+
+	return weighted
+}
+
+// importanceHint describes a PR's importance in the LLM context
+func importanceHint(pr *model.PR) string {
+	if pr.Additions > 0 || pr.Deletions > 0 {
+		return fmt.Sprintf("%s (+%d/-%d lines)", pr.Importance, pr.Additions, pr.Deletions)
+	}
+	return pr.Importance
+}
+
+func changedLines(pr *model.PR) int {
+	return pr.Additions + pr.Deletions
+}
+
+func hasAnyLabel(pr *model.PR, labels []string) bool {
+	for _, label := range pr.Labels {
+		for _, candidate := range labels {
+			if strings.EqualFold(label, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -7,7 +7,8 @@ import (
 	"os"
 )
 
-// Logger provides structured logging for prtool
+// Logger provides structured logging for prtool.
+// A nil *Logger is valid and discards all log messages.
 type Logger struct {
 	infoLogger  *log.Logger
 	errorLogger *log.Logger
@@ -44,19 +45,30 @@ func New(verbose, ci bool, logFile string) (*Logger, error) {
 
 // Info logs an informational message (only if verbose is enabled)
 func (l *Logger) Info(format string, args ...interface{}) {
-	if l.verbose {
+	if l != nil && l.verbose {
 		l.infoLogger.Printf(format, args...)
 	}
 }
 
+// Warn logs a warning message (always shown, to the log destination)
+func (l *Logger) Warn(format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
+	l.infoLogger.Printf("Warning: "+format, args...)
+}
+
 // Error logs an error message (always shown)
 func (l *Logger) Error(format string, args ...interface{}) {
+	if l == nil {
+		return
+	}
 	l.errorLogger.Printf(format, args...)
 }
 
 // Progress logs a progress message (suppressed in CI mode)
 func (l *Logger) Progress(format string, args ...interface{}) {
-	if !l.ci {
+	if l != nil && !l.ci {
 		fmt.Fprintf(os.Stderr, format+"\n", args...)
 	}
 }
@@ -207,3 +207,47 @@ func TestLogger_Output(t *testing.T) {
 		t.Errorf("Expected output to contain '%s', got: %s", expected, output)
 	}
 }
+
+func TestLogger_Warn(t *testing.T) {
+	// Capture stderr
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	// Warnings are shown even without verbose logging
+	logger, _ := New(false, false, "")
+	logger.Warn("window is %s", "large")
+
+	// Restore stderr
+	_ = w.Close()
+	os.Stderr = oldStderr
+
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r) // Ignore error in test
+	if !strings.Contains(buf.String(), "Warning: window is large") {
+		t.Errorf("Expected warning output, got: %s", buf.String())
+	}
+}
+
+func TestLogger_NilDiscards(t *testing.T) {
+	// Capture stderr
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	var logger *Logger
+	logger.Info("info %s", "message")
+	logger.Warn("warn %s", "message")
+	logger.Error("error %s", "message")
+	logger.Progress("progress %s", "message")
+
+	// Restore stderr
+	_ = w.Close()
+	os.Stderr = oldStderr
+
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r) // Ignore error in test
+	if buf.Len() != 0 {
+		t.Errorf("Expected nil logger to discard messages, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+)
+
+// spinnerFrames are drawn in turn, one per progress update
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// ProgressBar reports progress through a known number of steps. On a
+// terminal it redraws a single spinner line in place; otherwise it prints
+// one plain line per step. It is silent in CI mode.
+// A nil *ProgressBar is valid and discards all updates.
+type ProgressBar struct {
+	label string
+	total int
+	tty   bool
+	frame int
+	drawn bool
+}
+
+// NewProgressBar starts reporting progress through total steps, e.g.
+// "Fetching repo" shows "Fetching repo 3/12". It returns nil in CI mode.
+func (l *Logger) NewProgressBar(label string, total int) *ProgressBar {
+	if l == nil || l.ci {
+		return nil
+	}
+	return &ProgressBar{label: label, total: total, tty: isTerminal(os.Stderr)}
+}
+
+// Update reports that step n (1-based) is in progress, with an optional
+// detail such as the repository name
+func (p *ProgressBar) Update(n int, detail string) {
+	if p == nil {
+		return
+	}
+
+	line := fmt.Sprintf("%s %d/%d", p.label, n, p.total)
+	if detail != "" {
+		line += ": " + detail
+	}
+
+	if !p.tty {
+		fmt.Fprintln(os.Stderr, line)
+		return
+	}
+
+	// Return to the start of the line and clear it before redrawing
+	fmt.Fprintf(os.Stderr, "\r\033[K%s %s", spinnerFrames[p.frame%len(spinnerFrames)], line)
+	p.frame++
+	p.drawn = true
+}
+
+// Done clears the spinner line so later output starts on a clean line
+func (p *ProgressBar) Done() {
+	if p == nil || !p.drawn {
+		return
+	}
+	fmt.Fprint(os.Stderr, "\r\033[K")
+	p.drawn = false
+}
+
+// isTerminal reports whether f is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
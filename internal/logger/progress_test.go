@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestProgressBar_NotTerminal(t *testing.T) {
+	// Capture stderr; a pipe is not a terminal
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+
+	logger, _ := New(false, false, "")
+	progress := logger.NewProgressBar("Fetching repo", 2)
+	progress.Update(1, "acme/api")
+	progress.Update(2, "acme/web")
+	progress.Done()
+
+	// Restore stderr
+	_ = w.Close()
+	os.Stderr = oldStderr
+
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r) // Ignore error in test
+	output := buf.String()
+
+	if output != "Fetching repo 1/2: acme/api\nFetching repo 2/2: acme/web\n" {
+		t.Errorf("Expected plain progress lines, got: %q", output)
+	}
+	if strings.ContainsAny(output, "\r\033") {
+		t.Errorf("Expected no spinner escape codes, got: %q", output)
+	}
+}
+
+func TestProgressBar_SilentInCI(t *testing.T) {
+	logger, _ := New(false, true, "")
+	if progress := logger.NewProgressBar("Fetching repo", 2); progress != nil {
+		t.Error("Expected no progress bar in CI mode")
+	}
+
+	// A nil progress bar discards updates
+	var progress *ProgressBar
+	progress.Update(1, "acme/api")
+	progress.Done()
+}
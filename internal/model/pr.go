@@ -1,18 +1,89 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // PR represents a GitHub pull request with the essential fields we need
 type PR struct {
-	Title      string
-	Body       string
-	Author     string
-	CreatedAt  time.Time
-	MergedAt   *time.Time
-	Labels     []string
-	FilePaths  []string
-	HTMLURL    string
-	Number     int
-	Repository string
-	State      string
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	Author     string     `json:"author"`
+	CreatedAt  time.Time  `json:"created_at"`
+	MergedAt   *time.Time `json:"merged_at"`
+	ClosedAt   *time.Time `json:"closed_at"`
+	Labels     []string   `json:"labels"`
+	FilePaths  []string   `json:"file_paths"`
+	HTMLURL    string     `json:"html_url"`
+	Number     int        `json:"number"`
+	Repository string     `json:"repository"`
+	State      string     `json:"state"`
+	IsDraft    bool       `json:"is_draft"`
+	BaseBranch string     `json:"base_branch"`
+	RepoTopics []string   `json:"repo_topics"`
+	// RepoLanguage is the primary language GitHub reports for the repository
+	RepoLanguage string `json:"repo_language"`
+	// RepoDescription is the repository's description, empty when it has none
+	RepoDescription string `json:"repo_description,omitempty"`
+	// Scope is the team the PR's repository came from when several teams
+	// are in scope, or the name of the GitHub host profile it was fetched from
+	Scope     string `json:"scope,omitempty"`
+	Milestone string `json:"milestone"`
+	// AuthorEmail is the author's commit email, captured best effort when
+	// commits are fetched
+	AuthorEmail string `json:"author_email,omitempty"`
+	// MergeCommitSHA is the commit the PR was merged as
+	MergeCommitSHA string `json:"merge_commit_sha,omitempty"`
+	// RevertedBy is the number of a later PR that reverted this one
+	RevertedBy int `json:"reverted_by,omitempty"`
+	// Additions and Deletions count the changed lines. They are only fetched
+	// when needed, e.g. for --weight-by size.
+	Additions int `json:"additions,omitempty"`
+	Deletions int `json:"deletions,omitempty"`
+	// Importance is a relative importance hint (high, medium, low) for the
+	// LLM context. It is never rendered.
+	Importance string `json:"-"`
+}
+
+// IsMerged reports whether the PR has been merged
+func (pr *PR) IsMerged() bool {
+	return pr.MergedAt != nil
+}
+
+// MergedDate returns the merge date as YYYY-MM-DD, or "N/A" when the PR has
+// not been merged
+func (pr *PR) MergedDate() string {
+	if pr.MergedAt == nil {
+		return "N/A"
+	}
+	return pr.MergedAt.Format("2006-01-02")
+}
+
+// SlugRef returns the short owner/repo#number reference for the PR
+func (pr *PR) SlugRef() string {
+	return fmt.Sprintf("%s#%d", pr.Repository, pr.Number)
+}
+
+// ShortTitle returns the title cut to at most n characters, ending in "..."
+// when it was cut. Multibyte characters are never split.
+func (pr *PR) ShortTitle(n int) string {
+	return truncate(pr.Title, n)
+}
+
+// ShortBody returns the body cut to at most n characters like ShortTitle
+func (pr *PR) ShortBody(n int) string {
+	return truncate(pr.Body, n)
+}
+
+// truncate cuts s to at most n runes, ending in "..." when it was cut
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	if n <= 3 {
+		return string(runes[:max(n, 0)])
+	}
+	return string(runes[:n-3]) + "..."
 }
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPR_IsMerged(t *testing.T) {
+	merged := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if !(&PR{MergedAt: &merged}).IsMerged() {
+		t.Error("Expected a PR with MergedAt to be merged")
+	}
+	if (&PR{State: "closed"}).IsMerged() {
+		t.Error("Expected a PR without MergedAt not to be merged")
+	}
+}
+
+func TestPR_MergedDate(t *testing.T) {
+	merged := time.Date(2024, 3, 1, 23, 59, 0, 0, time.UTC)
+
+	if got := (&PR{MergedAt: &merged}).MergedDate(); got != "2024-03-01" {
+		t.Errorf("Expected 2024-03-01, got %q", got)
+	}
+	if got := (&PR{}).MergedDate(); got != "N/A" {
+		t.Errorf("Expected N/A for a nil MergedAt, got %q", got)
+	}
+}
+
+func TestPR_SlugRef(t *testing.T) {
+	pr := &PR{Repository: "acme/api", Number: 42}
+	if got := pr.SlugRef(); got != "acme/api#42" {
+		t.Errorf("Expected acme/api#42, got %q", got)
+	}
+}
+
+func TestPR_ShortTitle(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		n        int
+		expected string
+	}{
+		{name: "short title unchanged", title: "Fix bug", n: 40, expected: "Fix bug"},
+		{name: "exact length unchanged", title: "0123456789", n: 10, expected: "0123456789"},
+		{name: "long title cut", title: "Add support for custom key bindings", n: 20, expected: "Add support for c..."},
+		{name: "multibyte title", title: "Käse für Müller und Söhne", n: 10, expected: "Käse fü..."},
+		{name: "emoji title", title: "🚀🚀🚀🚀🚀 launch", n: 6, expected: "🚀🚀🚀..."},
+		{name: "no room for ellipsis", title: "Refactor", n: 3, expected: "Ref"},
+		{name: "zero length", title: "Refactor", n: 0, expected: ""},
+		{name: "empty title", title: "", n: 10, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (&PR{Title: tt.title}).ShortTitle(tt.n); got != tt.expected {
+				t.Errorf("ShortTitle(%d) = %q, expected %q", tt.n, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPR_ShortBody(t *testing.T) {
+	pr := &PR{Body: "Käse für Müller und Söhne"}
+	if got := pr.ShortBody(10); got != "Käse fü..." {
+		t.Errorf("ShortBody(10) = %q, expected %q", got, "Käse fü...")
+	}
+	if got := pr.ShortBody(100); got != pr.Body {
+		t.Errorf("ShortBody(100) = %q, expected the body unchanged", got)
+	}
+}
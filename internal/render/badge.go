@@ -0,0 +1,64 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Default badge settings used when Options leaves them unset
+const (
+	DefaultBadgeLabel    = "PRs this week"
+	DefaultBadgeYellowAt = 1
+	DefaultBadgeGreenAt  = 10
+)
+
+// Badge is a shields.io endpoint badge document
+// (see https://shields.io/badges/endpoint-badge)
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// RenderBadge generates a shields.io endpoint JSON document showing the total
+// PR count. The color is red below BadgeYellowAt, yellow below BadgeGreenAt
+// and green otherwise.
+func RenderBadge(meta Metadata, opts Options) (string, error) {
+	label := opts.BadgeLabel
+	if label == "" {
+		label = DefaultBadgeLabel
+	}
+
+	yellowAt := opts.BadgeYellowAt
+	if yellowAt <= 0 {
+		yellowAt = DefaultBadgeYellowAt
+	}
+	greenAt := opts.BadgeGreenAt
+	if greenAt <= 0 {
+		greenAt = DefaultBadgeGreenAt
+	}
+
+	color := "green"
+	switch {
+	case meta.TotalPRs < yellowAt:
+		color = "red"
+	case meta.TotalPRs < greenAt:
+		color = "yellow"
+	}
+
+	badge := Badge{
+		SchemaVersion: 1,
+		Label:         label,
+		Message:       strconv.Itoa(meta.TotalPRs),
+		Color:         color,
+	}
+
+	data, err := json.Marshal(badge)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal badge: %w", err)
+	}
+
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,70 @@
+package render
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func TestRenderBadge(t *testing.T) {
+	tests := []struct {
+		name          string
+		totalPRs      int
+		opts          Options
+		expectedLabel string
+		expectedColor string
+	}{
+		{
+			name:          "default thresholds green",
+			totalPRs:      42,
+			expectedLabel: "PRs this week",
+			expectedColor: "green",
+		},
+		{
+			name:          "default thresholds yellow",
+			totalPRs:      3,
+			expectedLabel: "PRs this week",
+			expectedColor: "yellow",
+		},
+		{
+			name:          "no PRs is red",
+			totalPRs:      0,
+			expectedLabel: "PRs this week",
+			expectedColor: "red",
+		},
+		{
+			name:          "custom label and thresholds",
+			totalPRs:      42,
+			opts:          Options{BadgeLabel: "PRs this month", BadgeYellowAt: 20, BadgeGreenAt: 50},
+			expectedLabel: "PRs this month",
+			expectedColor: "yellow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := RenderBadge(Metadata{TotalPRs: tt.totalPRs}, tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var badge map[string]interface{}
+			if err := json.Unmarshal([]byte(output), &badge); err != nil {
+				t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+			}
+
+			if badge["schemaVersion"] != float64(1) {
+				t.Errorf("Expected schemaVersion 1, got %v", badge["schemaVersion"])
+			}
+			if badge["message"] != strconv.Itoa(tt.totalPRs) {
+				t.Errorf("Expected message %q, got %v", strconv.Itoa(tt.totalPRs), badge["message"])
+			}
+			if badge["label"] != tt.expectedLabel {
+				t.Errorf("Expected label %q, got %v", tt.expectedLabel, badge["label"])
+			}
+			if badge["color"] != tt.expectedColor {
+				t.Errorf("Expected color %q, got %v", tt.expectedColor, badge["color"])
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// DedupeByTitle collapses PRs whose titles match after normalization into a
+// single details entry listing every repository
+const DedupeByTitle = "title"
+
+// IsValidDedupeBy reports whether dedupeBy is a supported value. Empty
+// disables deduplication.
+func IsValidDedupeBy(dedupeBy string) bool {
+	return dedupeBy == "" || dedupeBy == DedupeByTitle
+}
+
+// dedupeByTitle returns the first PR of each set of PRs with the same
+// normalized title, in their original order, and the later PRs collapsed into
+// each of them
+func dedupeByTitle(prs []*model.PR) ([]*model.PR, map[*model.PR][]*model.PR) {
+	first := make(map[string]*model.PR)
+	dupes := make(map[*model.PR][]*model.PR)
+	var unique []*model.PR
+	for _, pr := range prs {
+		key := normalizeTitle(pr.Title)
+		if kept, ok := first[key]; ok {
+			dupes[kept] = append(dupes[kept], pr)
+			continue
+		}
+		first[key] = pr
+		unique = append(unique, pr)
+	}
+	return unique, dupes
+}
+
+// normalizeTitle lowercases a title and collapses whitespace and trailing
+// periods, so "Bump logging lib" and "bump  logging lib." match
+func normalizeTitle(title string) string {
+	return strings.TrimRight(strings.ToLower(strings.Join(strings.Fields(title), " ")), ".")
+}
+
+// prRefs returns repo#number references for pr and its duplicates
+func prRefs(pr *model.PR, dupes []*model.PR) string {
+	refs := []string{pr.SlugRef()}
+	for _, dupe := range dupes {
+		refs = append(refs, dupe.SlugRef())
+	}
+	return strings.Join(refs, ", ")
+}
@@ -0,0 +1,58 @@
+package render
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// DetailsPlaceholders lists the placeholders supported in
+// Options.DetailsFormat
+var DetailsPlaceholders = []string{"{number}", "{title}", "{author}", "{repo}", "{merged}", "{url}", "{labels}", "{scope}"}
+
+var placeholderPattern = regexp.MustCompile(`\{[a-z]+\}`)
+
+// ValidateDetailsFormat checks that format only uses supported placeholders
+func ValidateDetailsFormat(format string) error {
+	for _, placeholder := range placeholderPattern.FindAllString(format, -1) {
+		supported := false
+		for _, p := range DetailsPlaceholders {
+			if placeholder == p {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return fmt.Errorf("invalid details format placeholder '%s' (supported: %s)", placeholder, strings.Join(DetailsPlaceholders, ", "))
+		}
+	}
+	return nil
+}
+
+// expandDetailsFormat fills the placeholders of format with the PR's fields.
+// Titles and labels are escaped; URLs are left raw.
+func expandDetailsFormat(format string, pr *model.PR) string {
+	merged := ""
+	if pr.IsMerged() {
+		merged = pr.MergedDate()
+	}
+
+	labels := make([]string, len(pr.Labels))
+	for i, label := range pr.Labels {
+		labels[i] = escapeInline(label)
+	}
+
+	return strings.NewReplacer(
+		"{number}", strconv.Itoa(pr.Number),
+		"{title}", escapeInline(pr.Title),
+		"{author}", pr.Author,
+		"{repo}", pr.Repository,
+		"{merged}", merged,
+		"{url}", pr.HTMLURL,
+		"{labels}", strings.Join(labels, ", "),
+		"{scope}", pr.Scope,
+	).Replace(format)
+}
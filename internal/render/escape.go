@@ -0,0 +1,37 @@
+package render
+
+import "strings"
+
+// tableCellReplacer escapes characters that would break a Markdown table row
+var tableCellReplacer = strings.NewReplacer(
+	"|", `\|`,
+	"\r\n", " ",
+	"\n", " ",
+	"\r", " ",
+)
+
+// inlineReplacer escapes Markdown control characters in single-line text
+// such as headings and label lists
+var inlineReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"\r\n", " ",
+	"\n", " ",
+	"\r", " ",
+)
+
+// escapeTableCell makes text safe to place inside a Markdown table cell
+func escapeTableCell(text string) string {
+	return tableCellReplacer.Replace(text)
+}
+
+// escapeInline makes text render literally in a Markdown heading or list item
+func escapeInline(text string) string {
+	return inlineReplacer.Replace(text)
+}
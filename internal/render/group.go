@@ -0,0 +1,241 @@
+package render
+
+import (
+	"sort"
+	"time"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// Supported GroupBy values
+const (
+	// GroupByNone disables grouping
+	GroupByNone = "none"
+	// GroupByRepo groups PRs by repository
+	GroupByRepo = "repo"
+	// GroupByAuthor groups PRs by author
+	GroupByAuthor = "author"
+	// GroupByLabel groups PRs by label. A PR appears under each of its labels.
+	GroupByLabel = "label"
+	// GroupByWeek groups PRs by the week (starting Monday) they were merged in
+	GroupByWeek = "week"
+	// GroupByMilestone groups PRs by milestone
+	GroupByMilestone = "milestone"
+	// GroupByBase groups PRs by the branch they were merged into
+	GroupByBase = "base"
+	// GroupByTopic groups PRs by the topics of their repository. A PR appears
+	// under each of its repository's topics.
+	GroupByTopic = "topic"
+	// GroupByLanguage groups PRs by the primary language of their repository
+	GroupByLanguage = "language"
+)
+
+// GroupByValues lists the accepted GroupBy values
+var GroupByValues = []string{
+	GroupByRepo, GroupByAuthor, GroupByLabel, GroupByWeek, GroupByMilestone,
+	GroupByBase, GroupByTopic, GroupByLanguage, GroupByNone,
+}
+
+// Supported RepoOrder values
+const (
+	// RepoOrderAlpha orders repository groups by name
+	RepoOrderAlpha = "alpha"
+	// RepoOrderActivity orders repository groups by PR count, busiest first
+	RepoOrderActivity = "activity"
+)
+
+// RepoOrderValues lists the accepted RepoOrder values
+var RepoOrderValues = []string{RepoOrderAlpha, RepoOrderActivity}
+
+// IsValidRepoOrder reports whether repoOrder is a supported RepoOrder value.
+// The empty string is treated as alpha.
+func IsValidRepoOrder(repoOrder string) bool {
+	return repoOrder == "" || repoOrder == RepoOrderAlpha || repoOrder == RepoOrderActivity
+}
+
+// IsValidGroupBy reports whether groupBy is a supported GroupBy value. The
+// empty string is treated as none.
+func IsValidGroupBy(groupBy string) bool {
+	if groupBy == "" {
+		return true
+	}
+	for _, value := range GroupByValues {
+		if groupBy == value {
+			return true
+		}
+	}
+	return false
+}
+
+// prGroup is a named set of PRs in a grouped report
+type prGroup struct {
+	Name string
+	PRs  []*model.PR
+}
+
+// groupPRs splits PRs into groups ordered by name. It returns nil when
+// grouping is disabled or the grouping key is unknown.
+func groupPRs(prs []*model.PR, groupBy string) []prGroup {
+	keyFn := groupKeyFunc(groupBy)
+	if keyFn == nil {
+		return nil
+	}
+
+	index := make(map[string]int)
+	var groups []prGroup
+	for _, pr := range prs {
+		for _, key := range keyFn(pr) {
+			i, ok := index[key]
+			if !ok {
+				i = len(groups)
+				index[key] = i
+				groups = append(groups, prGroup{Name: key})
+			}
+			groups[i].PRs = append(groups[i].PRs, pr)
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
+	return groups
+}
+
+// addEmptyGroups adds the groups known from the report metadata that have no
+// PRs: every scanned repository when grouping by repo, and every week of the
+// fetch window when grouping by week. Other groupings have no known buckets.
+func addEmptyGroups(groups []prGroup, groupBy string, meta Metadata) []prGroup {
+	var names []string
+	switch groupBy {
+	case GroupByRepo:
+		names = meta.ScannedRepositories
+	case GroupByWeek:
+		if meta.SinceTime == nil || meta.UntilTime == nil {
+			return groups
+		}
+		for week := weekStart(*meta.SinceTime); !week.After(*meta.UntilTime); week = week.AddDate(0, 0, 7) {
+			names = append(names, week.Format("2006-01-02"))
+		}
+	default:
+		return groups
+	}
+
+	existing := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		existing[group.Name] = true
+	}
+	added := false
+	for _, name := range names {
+		if !existing[name] {
+			existing[name] = true
+			groups = append(groups, prGroup{Name: name})
+			added = true
+		}
+	}
+	if added {
+		sort.SliceStable(groups, func(i, j int) bool {
+			return groups[i].Name < groups[j].Name
+		})
+	}
+	return groups
+}
+
+// orderRepoGroups reorders repository groups by PR count, busiest first, when
+// repoOrder is activity. Ties keep their alphabetical order. Other groupings
+// are left untouched.
+func orderRepoGroups(groups []prGroup, groupBy, repoOrder string) {
+	if groupBy != GroupByRepo || repoOrder != RepoOrderActivity {
+		return
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].PRs) > len(groups[j].PRs)
+	})
+}
+
+// groupKeyFunc returns the function mapping a PR to its group names
+func groupKeyFunc(groupBy string) func(*model.PR) []string {
+	switch groupBy {
+	case GroupByRepo:
+		return func(pr *model.PR) []string {
+			return []string{valueOr(pr.Repository, "unknown")}
+		}
+	case GroupByAuthor:
+		return func(pr *model.PR) []string {
+			return []string{valueOr(pr.Author, "unknown")}
+		}
+	case GroupByLabel:
+		return func(pr *model.PR) []string {
+			if len(pr.Labels) == 0 {
+				return []string{"unlabeled"}
+			}
+			return pr.Labels
+		}
+	case GroupByWeek:
+		return func(pr *model.PR) []string {
+			if !pr.IsMerged() {
+				return []string{"unmerged"}
+			}
+			return []string{weekStart(*pr.MergedAt).Format("2006-01-02")}
+		}
+	case GroupByMilestone:
+		return func(pr *model.PR) []string {
+			return []string{valueOr(pr.Milestone, "no milestone")}
+		}
+	case GroupByBase:
+		return func(pr *model.PR) []string {
+			return []string{valueOr(pr.BaseBranch, "unknown")}
+		}
+	case GroupByTopic:
+		return func(pr *model.PR) []string {
+			if len(pr.RepoTopics) == 0 {
+				return []string{"untagged"}
+			}
+			return pr.RepoTopics
+		}
+	case GroupByLanguage:
+		return func(pr *model.PR) []string {
+			return []string{valueOr(pr.RepoLanguage, "Unknown")}
+		}
+	default:
+		return nil
+	}
+}
+
+// groupTitle returns the heading text for a group
+func groupTitle(groupBy, name string) string {
+	switch groupBy {
+	case GroupByLabel:
+		return "Label: " + name
+	case GroupByWeek:
+		if name == "unmerged" {
+			return name
+		}
+		return "Week of " + name
+	case GroupByMilestone:
+		return "Milestone: " + name
+	case GroupByBase:
+		return "Merged into " + name
+	case GroupByTopic:
+		return "Topic: " + name
+	case GroupByLanguage:
+		return "Language: " + name
+	default:
+		return name
+	}
+}
+
+// weekStart returns midnight UTC on the Monday of the week containing t
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
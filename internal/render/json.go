@@ -0,0 +1,62 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// JSONReport is the document produced by RenderJSON
+type JSONReport struct {
+	Metadata     Metadata    `json:"metadata"`
+	PullRequests []*model.PR `json:"pull_requests"`
+}
+
+// RenderJSON generates an indented JSON document from metadata and PR list
+func RenderJSON(meta Metadata, prs []*model.PR) (string, error) {
+	return RenderJSONWithOptions(meta, prs, Options{})
+}
+
+// RenderJSONWithOptions generates a JSON document from metadata and PR list
+// using the given rendering options
+func RenderJSONWithOptions(meta Metadata, prs []*model.PR, opts Options) (string, error) {
+	report := JSONReport{
+		Metadata:     meta,
+		PullRequests: prs,
+	}
+
+	// Always emit an array so consumers don't have to handle null
+	if report.PullRequests == nil {
+		report.PullRequests = []*model.PR{}
+	}
+
+	var data []byte
+	var err error
+	if opts.CompactJSON {
+		data, err = json.Marshal(report)
+	} else {
+		data, err = json.MarshalIndent(report, "", "  ")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON report: %w", err)
+	}
+
+	return string(data) + "\n", nil
+}
+
+// RenderJSONLines generates newline-delimited JSON with one compact object per
+// PR and no wrapping document, for streaming into log pipelines
+func RenderJSONLines(prs []*model.PR) (string, error) {
+	var sb strings.Builder
+	for _, pr := range prs {
+		data, err := json.Marshal(pr)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal PR %s#%d: %w", pr.Repository, pr.Number, err)
+		}
+		sb.Write(data)
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
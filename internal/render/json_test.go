@@ -0,0 +1,168 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+func TestRenderJSON(t *testing.T) {
+	fixedTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	mergedTime := time.Date(2024, 1, 14, 15, 20, 0, 0, time.UTC)
+
+	meta := Metadata{
+		GeneratedAt:  fixedTime,
+		Scope:        "organization",
+		ScopeValue:   "acme-corp",
+		Since:        "-7d",
+		TotalPRs:     1,
+		Repositories: []string{"acme-corp/web-app"},
+		Summary:      "A quiet week.",
+	}
+	prs := []*model.PR{
+		{
+			Title:      "Add OAuth2 authentication support",
+			Author:     "alice-dev",
+			Repository: "acme-corp/web-app",
+			Number:     123,
+			MergedAt:   &mergedTime,
+			Labels:     []string{"feature"},
+			State:      "closed",
+		},
+	}
+
+	t.Run("round trips metadata and PRs", func(t *testing.T) {
+		output, err := RenderJSON(meta, prs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var report JSONReport
+		if err := json.Unmarshal([]byte(output), &report); err != nil {
+			t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
+		}
+
+		if report.Metadata.ScopeValue != "acme-corp" || report.Metadata.Summary != "A quiet week." {
+			t.Errorf("Unexpected metadata: %+v", report.Metadata)
+		}
+		if len(report.PullRequests) != 1 {
+			t.Fatalf("Expected 1 PR, got %d", len(report.PullRequests))
+		}
+		pr := report.PullRequests[0]
+		if pr.Number != 123 || pr.Author != "alice-dev" || pr.MergedAt == nil || !pr.MergedAt.Equal(mergedTime) {
+			t.Errorf("Unexpected PR: %+v", pr)
+		}
+	})
+
+	t.Run("uses snake_case keys", func(t *testing.T) {
+		output, err := RenderJSON(meta, prs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var raw struct {
+			Metadata map[string]interface{} `json:"metadata"`
+		}
+		if err := json.Unmarshal([]byte(output), &raw); err != nil {
+			t.Fatalf("Output is not valid JSON: %v", err)
+		}
+		if _, ok := raw.Metadata["scope_value"]; !ok {
+			t.Errorf("Expected metadata.scope_value key in output:\n%s", output)
+		}
+		if _, ok := raw.Metadata["since_time"]; ok {
+			t.Errorf("Expected since_time to be omitted when unset:\n%s", output)
+		}
+	})
+
+	t.Run("includes resolved window as RFC3339", func(t *testing.T) {
+		since := time.Date(2024, 1, 8, 10, 30, 0, 0, time.UTC)
+		windowed := meta
+		windowed.SinceTime = &since
+		windowed.UntilTime = &fixedTime
+
+		output, err := RenderJSON(windowed, prs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(output, `"since_time": "2024-01-08T10:30:00Z"`) ||
+			!strings.Contains(output, `"until_time": "2024-01-15T10:30:00Z"`) {
+			t.Errorf("Expected RFC3339 since_time and until_time in output:\n%s", output)
+		}
+	})
+
+	t.Run("empty PR list is an array", func(t *testing.T) {
+		output, err := RenderJSON(meta, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(output), &raw); err != nil {
+			t.Fatalf("Output is not valid JSON: %v", err)
+		}
+		if string(raw["pull_requests"]) != "[]" {
+			t.Errorf("Expected empty array for pull_requests, got %s", raw["pull_requests"])
+		}
+	})
+	t.Run("pretty output is indented", func(t *testing.T) {
+		output, err := RenderJSON(meta, prs)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Count(strings.TrimSuffix(output, "\n"), "\n") == 0 {
+			t.Errorf("Expected newlines between fields, got:\n%s", output)
+		}
+	})
+
+	t.Run("compact output is a single line", func(t *testing.T) {
+		output, err := RenderJSONWithOptions(meta, prs, Options{CompactJSON: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Contains(strings.TrimSuffix(output, "\n"), "\n") {
+			t.Errorf("Expected no newlines between fields, got:\n%s", output)
+		}
+		if !strings.HasSuffix(output, "\n") {
+			t.Error("Expected a trailing newline")
+		}
+
+		var report JSONReport
+		if err := json.Unmarshal([]byte(output), &report); err != nil {
+			t.Fatalf("Output is not valid JSON: %v", err)
+		}
+	})
+}
+
+func TestRenderJSONLines(t *testing.T) {
+	prs := []*model.PR{
+		{Title: "Add OAuth2", Author: "alice", Repository: "acme/web", Number: 1, Body: "Multi\nline body"},
+		{Title: "Fix crash", Author: "bob", Repository: "acme/api", Number: 2},
+		{Title: "Bump deps", Author: "carol", Repository: "acme/api", Number: 3},
+	}
+
+	output, err := RenderJSONLines(prs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != len(prs) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(prs), len(lines), output)
+	}
+	for i, line := range lines {
+		var pr model.PR
+		if err := json.Unmarshal([]byte(line), &pr); err != nil {
+			t.Fatalf("Line %d is not valid JSON: %v\n%s", i+1, err, line)
+		}
+		if pr.Number != prs[i].Number || pr.Title != prs[i].Title {
+			t.Errorf("Line %d: expected PR #%d, got %+v", i+1, prs[i].Number, pr)
+		}
+	}
+
+	if output, _ := RenderJSONLines(nil); output != "" {
+		t.Errorf("Expected no output for no PRs, got %q", output)
+	}
+}
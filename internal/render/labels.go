@@ -0,0 +1,43 @@
+package render
+
+import (
+	"sort"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// labelCount is the number of PRs carrying a label
+type labelCount struct {
+	Label string
+	Count int
+}
+
+// countLabels counts how many PRs carry each label, dropping labels used by
+// fewer than minCount PRs. The result is sorted by count, most used first,
+// then by label.
+func countLabels(prs []*model.PR, minCount int) []labelCount {
+	counts := make(map[string]int)
+	for _, pr := range prs {
+		seen := make(map[string]bool)
+		for _, label := range pr.Labels {
+			if !seen[label] {
+				seen[label] = true
+				counts[label]++
+			}
+		}
+	}
+
+	var result []labelCount
+	for label, count := range counts {
+		if count >= minCount {
+			result = append(result, labelCount{Label: label, Count: count})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Label < result[j].Label
+	})
+	return result
+}
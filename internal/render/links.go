@@ -0,0 +1,48 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Supported LinkStyle values
+const (
+	// LinkStyleInline writes links as [text](url)
+	LinkStyleInline = "inline"
+	// LinkStyleReference writes links as [text][n] and lists the URLs in a
+	// reference block at the end of the report
+	LinkStyleReference = "reference"
+)
+
+// IsValidLinkStyle reports whether linkStyle is a supported LinkStyle value.
+// The empty string is treated as inline.
+func IsValidLinkStyle(linkStyle string) bool {
+	return linkStyle == "" || linkStyle == LinkStyleInline || linkStyle == LinkStyleReference
+}
+
+// linker formats Markdown links in the configured style, collecting the URLs
+// of reference-style links
+type linker struct {
+	reference bool
+	urls      []string
+}
+
+// link returns a link to url with the given text
+func (l *linker) link(text, url string) string {
+	if !l.reference {
+		return fmt.Sprintf("[%s](%s)", text, url)
+	}
+	l.urls = append(l.urls, url)
+	return fmt.Sprintf("[%s][%d]", text, len(l.urls))
+}
+
+// writeReferences writes the collected reference definitions, if any
+func (l *linker) writeReferences(sb *strings.Builder) {
+	if len(l.urls) == 0 {
+		return
+	}
+	for i, url := range l.urls {
+		sb.WriteString(fmt.Sprintf("[%d]: %s\n", i+1, url))
+	}
+	sb.WriteString("\n")
+}
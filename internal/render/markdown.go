@@ -10,34 +10,125 @@ import (
 
 // Metadata contains information about the PR summary generation
 type Metadata struct {
-	GeneratedAt  time.Time
-	Scope        string
-	ScopeValue   string
-	Since        string
-	TotalPRs     int
-	Repositories []string
-	LLMProvider  string
-	LLMModel     string
-	Summary      string
+	// ReportID uniquely identifies the generated report for audit trails
+	ReportID    string    `json:"report_id,omitempty"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Scope       string    `json:"scope"`
+	ScopeValue  string    `json:"scope_value"`
+	Since       string    `json:"since"`
+	// Until is the configured end of the window, empty when it is now
+	Until string `json:"until,omitempty"`
+	// SinceTime and UntilTime are the absolute bounds of the fetch window
+	SinceTime *time.Time `json:"since_time,omitempty"`
+	UntilTime *time.Time `json:"until_time,omitempty"`
+	Filters   []string   `json:"filters"`
+	TotalPRs  int        `json:"total_prs"`
+	// TotalContributors counts the distinct PR authors
+	TotalContributors int `json:"total_contributors"`
+	// TotalRepositories counts the repositories with PRs
+	TotalRepositories int      `json:"total_repositories"`
+	Repositories      []string `json:"repositories"`
+	LLMProvider       string   `json:"llm_provider"`
+	LLMModel          string   `json:"llm_model"`
+	Summary           string   `json:"summary"`
+	// AuthorSummaries holds an AI summary per author, shown under the author
+	// groups of the details section
+	AuthorSummaries map[string]string `json:"author_summaries,omitempty"`
+	// Partial is set when the run was interrupted before all PRs were fetched
+	Partial bool `json:"partial"`
+	// ScannedRepositories lists every repository that was scanned, including
+	// those without PRs. It is only set when repos without PRs are included.
+	ScannedRepositories []string `json:"scanned_repositories,omitempty"`
+}
+
+// Options controls optional rendering behaviour. The zero value renders the
+// default report.
+type Options struct {
+	// GroupBy groups the PR details section ("" or "none" disables grouping)
+	GroupBy string
+	// CompactJSON emits single-line JSON instead of indented JSON
+	CompactJSON bool
+	// BadgeLabel, BadgeYellowAt and BadgeGreenAt configure RenderBadge
+	BadgeLabel    string
+	BadgeYellowAt int
+	BadgeGreenAt  int
+	// MinLabelCount hides labels used by fewer PRs from the label breakdown
+	// (0 or 1 shows every label). Per-PR labels are unaffected.
+	MinLabelCount int
+	// RepoOrder orders repository groups when grouping by repo ("" or alpha
+	// by name, activity by PR count)
+	RepoOrder string
+	// RepoDescriptions writes each repository's description under its
+	// heading when grouping by repo
+	RepoDescriptions bool
+	// HeadingOffset shifts every generated Markdown heading down this many
+	// levels, for embedding the report in a larger document
+	HeadingOffset int
+	// NoDetailsBody renders each PR in the details section as a one-line
+	// entry with its title, link and author, without the description
+	NoDetailsBody bool
+	// ShowEmptySections keeps groups without PRs in the details section, so
+	// recurring reports have the same headings. Repo groups come from
+	// Metadata.ScannedRepositories and week groups from the fetch window.
+	ShowEmptySections bool
+	// LinkStyle selects how PR links are written: "" or inline as
+	// [text](url), reference as [text][n] with the URLs listed at the end
+	LinkStyle string
+	// StatusIcons prefixes each PR in the details section with an indicator
+	// of its state (merged, open, closed without merging, draft)
+	StatusIcons bool
+	// DetailsFormat renders each PR in the details section as a one-line
+	// entry from a format string with placeholders such as {number}, {title}
+	// and {author} (see DetailsPlaceholders). Empty keeps the default layout.
+	DetailsFormat string
+	// DedupeBy collapses PRs in the details section: "title" renders PRs
+	// whose normalized titles match as one line listing every repository
+	DedupeBy string
 }
 
 // Render generates a Markdown document from metadata and PR list
 func Render(meta Metadata, prs []*model.PR) string {
+	return RenderWithOptions(meta, prs, Options{})
+}
+
+// RenderWithOptions generates a Markdown document from metadata and PR list
+// using the given rendering options
+func RenderWithOptions(meta Metadata, prs []*model.PR, opts Options) string {
 	var sb strings.Builder
+	h := func(level int) string { return heading(level, opts.HeadingOffset) }
+	links := &linker{reference: opts.LinkStyle == LinkStyleReference}
 
 	// Header
-	sb.WriteString("# Pull Request Summary\n\n")
+	sb.WriteString(h(1) + " Pull Request Summary\n\n")
 
 	// Metadata section
-	sb.WriteString("## Summary Information\n\n")
+	sb.WriteString(h(2) + " Summary Information\n\n")
 	sb.WriteString(fmt.Sprintf("- **Generated At**: %s\n", meta.GeneratedAt.Format("2006-01-02 15:04:05 UTC")))
 	sb.WriteString(fmt.Sprintf("- **Scope**: %s (%s)\n", meta.Scope, meta.ScopeValue))
-	sb.WriteString(fmt.Sprintf("- **Time Range**: %s\n", meta.Since))
+	if meta.Until != "" {
+		sb.WriteString(fmt.Sprintf("- **Time Range**: %s to %s\n", meta.Since, meta.Until))
+	} else {
+		sb.WriteString(fmt.Sprintf("- **Time Range**: %s\n", meta.Since))
+	}
+	if len(meta.Filters) > 0 {
+		sb.WriteString(fmt.Sprintf("- **Filters**: %s\n", strings.Join(meta.Filters, ", ")))
+	}
 	sb.WriteString(fmt.Sprintf("- **Total PRs**: %d\n", meta.TotalPRs))
+	if meta.Partial {
+		sb.WriteString("- **Status**: Partial (interrupted before all repositories were fetched)\n")
+	}
 
 	if len(meta.Repositories) > 0 {
 		sb.WriteString(fmt.Sprintf("- **Repositories**: %s\n", strings.Join(meta.Repositories, ", ")))
 	}
+	if len(meta.ScannedRepositories) > 0 {
+		inactive := reposWithoutPRs(meta)
+		sb.WriteString(fmt.Sprintf("- **Repositories Scanned**: %d (%d with PRs)\n",
+			len(meta.ScannedRepositories), len(meta.ScannedRepositories)-len(inactive)))
+		if len(inactive) > 0 {
+			sb.WriteString(fmt.Sprintf("- **Repositories Without PRs**: %s\n", strings.Join(inactive, ", ")))
+		}
+	}
 
 	if meta.LLMProvider != "" {
 		sb.WriteString(fmt.Sprintf("- **LLM Provider**: %s", meta.LLMProvider))
@@ -51,70 +142,210 @@ func Render(meta Metadata, prs []*model.PR) string {
 
 	// LLM Summary section (if available)
 	if meta.Summary != "" {
-		sb.WriteString("## AI Summary\n\n")
+		sb.WriteString(h(2) + " AI Summary\n\n")
 		sb.WriteString(meta.Summary)
 		sb.WriteString("\n\n")
 	}
 
+	// Label breakdown section
+	if counts := countLabels(prs, opts.MinLabelCount); len(counts) > 0 {
+		sb.WriteString(h(2) + " Labels\n\n")
+		for _, lc := range counts {
+			sb.WriteString(fmt.Sprintf("- %s: %d\n", escapeInline(lc.Label), lc.Count))
+		}
+		sb.WriteString("\n")
+	}
+
 	// PR Details section
 	if len(prs) > 0 {
-		sb.WriteString("## Pull Request Details\n\n")
-
-		for i, pr := range prs {
-			sb.WriteString(fmt.Sprintf("### %d. %s\n\n", i+1, pr.Title))
-
-			// Basic info
-			sb.WriteString(fmt.Sprintf("- **Author**: %s\n", pr.Author))
-			sb.WriteString(fmt.Sprintf("- **Repository**: %s\n", pr.Repository))
-			sb.WriteString(fmt.Sprintf("- **PR Number**: #%d\n", pr.Number))
-
-			if pr.MergedAt != nil {
-				sb.WriteString(fmt.Sprintf("- **Merged At**: %s\n", pr.MergedAt.Format("2006-01-02 15:04:05")))
-			}
-
-			if pr.HTMLURL != "" {
-				sb.WriteString(fmt.Sprintf("- **URL**: [View PR](%s)\n", pr.HTMLURL))
-			}
+		sb.WriteString(h(2) + " Pull Request Details\n\n")
 
-			// Labels
-			if len(pr.Labels) > 0 {
-				sb.WriteString(fmt.Sprintf("- **Labels**: %s\n", strings.Join(pr.Labels, ", ")))
-			}
-
-			// Description/Body
-			if pr.Body != "" {
-				sb.WriteString("\n**Description:**\n\n")
-				// Truncate very long descriptions
-				body := pr.Body
-				if len(body) > 500 {
-					body = body[:500] + "..."
+		var dupes map[*model.PR][]*model.PR
+		if opts.DedupeBy == DedupeByTitle {
+			prs, dupes = dedupeByTitle(prs)
+		}
+		groups := groupPRs(prs, opts.GroupBy)
+		if groups != nil && opts.ShowEmptySections {
+			groups = addEmptyGroups(groups, opts.GroupBy, meta)
+		}
+		orderRepoGroups(groups, opts.GroupBy, opts.RepoOrder)
+		oneLine := opts.NoDetailsBody || opts.DetailsFormat != "" || opts.DedupeBy != ""
+		if groups == nil {
+			for i, pr := range prs {
+				if oneLine {
+					writePRLine(&sb, i+1, pr, dupes[pr], opts, links)
+				} else {
+					writePR(&sb, i+1, pr, h(3), opts.StatusIcons, links)
 				}
-				sb.WriteString(body)
+			}
+			if oneLine {
 				sb.WriteString("\n")
 			}
-
-			// Files (if available)
-			if len(pr.FilePaths) > 0 {
-				sb.WriteString("\n**Modified Files:**\n\n")
-				for _, file := range pr.FilePaths {
-					sb.WriteString(fmt.Sprintf("- `%s`\n", file))
+		} else {
+			n := 0
+			for _, group := range groups {
+				sb.WriteString(fmt.Sprintf("%s %s (%d)\n\n", h(3), groupTitle(opts.GroupBy, group.Name), len(group.PRs)))
+				if len(group.PRs) == 0 {
+					sb.WriteString("_No pull requests._\n\n")
+					continue
+				}
+				if summary := meta.AuthorSummaries[group.Name]; opts.GroupBy == GroupByAuthor && summary != "" {
+					sb.WriteString(strings.TrimSpace(summary) + "\n\n")
+				}
+				if opts.RepoDescriptions && opts.GroupBy == GroupByRepo {
+					if description := strings.TrimSpace(group.PRs[0].RepoDescription); description != "" {
+						sb.WriteString("_" + escapeInline(description) + "_\n\n")
+					}
+				}
+				for _, pr := range group.PRs {
+					n++
+					if oneLine {
+						writePRLine(&sb, n, pr, dupes[pr], opts, links)
+					} else {
+						writePR(&sb, n, pr, h(4), opts.StatusIcons, links)
+					}
+				}
+				if oneLine {
+					sb.WriteString("\n")
 				}
 			}
-
-			sb.WriteString("\n---\n\n")
 		}
 	} else {
-		sb.WriteString("## No Pull Requests Found\n\n")
+		sb.WriteString(h(2) + " No Pull Requests Found\n\n")
 		sb.WriteString("No pull requests were found for the specified criteria.\n\n")
 	}
 
+	links.writeReferences(&sb)
+
 	// Footer
 	sb.WriteString("---\n\n")
-	sb.WriteString("*Generated by prtool*\n")
+	if meta.TotalPRs > 0 {
+		sb.WriteString(fmt.Sprintf("*%s from %s across %s*\n\n",
+			plural(meta.TotalPRs, "PR"), plural(meta.TotalContributors, "contributor"), plural(meta.TotalRepositories, "repo")))
+	}
+	if meta.ReportID != "" {
+		sb.WriteString(fmt.Sprintf("*Generated by prtool (report %s)*\n", meta.ReportID))
+	} else {
+		sb.WriteString("*Generated by prtool*\n")
+	}
 
 	return sb.String()
 }
 
+// plural formats a count with its noun, e.g. "1 repo" or "3 repos"
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// heading returns the Markdown heading marker for level shifted by offset,
+// capped at h6
+func heading(level, offset int) string {
+	level += offset
+	if level > 6 {
+		level = 6
+	}
+	return strings.Repeat("#", level)
+}
+
+// reposWithoutPRs returns the scanned repositories that do not appear in the
+// repositories with PRs
+func reposWithoutPRs(meta Metadata) []string {
+	active := make(map[string]bool, len(meta.Repositories))
+	for _, repo := range meta.Repositories {
+		active[repo] = true
+	}
+
+	var inactive []string
+	for _, repo := range meta.ScannedRepositories {
+		if !active[repo] {
+			inactive = append(inactive, repo)
+		}
+	}
+	return inactive
+}
+
+// writePR writes the details of a single PR under a heading of the given level
+func writePR(sb *strings.Builder, n int, pr *model.PR, heading string, icons bool, links *linker) {
+	title := revertedTitle(pr, escapeInline(pr.Title))
+	if icons {
+		title = statusIcon(pr) + " " + title
+	}
+	sb.WriteString(fmt.Sprintf("%s %d. %s\n\n", heading, n, title))
+
+	// Basic info
+	sb.WriteString(fmt.Sprintf("- **Author**: %s\n", pr.Author))
+	sb.WriteString(fmt.Sprintf("- **Repository**: %s\n", pr.Repository))
+	if pr.Scope != "" {
+		sb.WriteString(fmt.Sprintf("- **Scope**: %s\n", pr.Scope))
+	}
+	sb.WriteString(fmt.Sprintf("- **PR Number**: #%d\n", pr.Number))
+
+	if pr.IsMerged() {
+		sb.WriteString(fmt.Sprintf("- **Merged At**: %s\n", pr.MergedAt.Format("2006-01-02 15:04:05")))
+	}
+
+	if pr.HTMLURL != "" {
+		sb.WriteString(fmt.Sprintf("- **URL**: %s\n", links.link("View PR", pr.HTMLURL)))
+	}
+
+	// Labels
+	if len(pr.Labels) > 0 {
+		labels := make([]string, len(pr.Labels))
+		for i, label := range pr.Labels {
+			labels[i] = escapeInline(label)
+		}
+		sb.WriteString(fmt.Sprintf("- **Labels**: %s\n", strings.Join(labels, ", ")))
+	}
+
+	// Description/Body
+	if pr.Body != "" {
+		sb.WriteString("\n**Description:**\n\n")
+		// Truncate very long descriptions
+		sb.WriteString(pr.ShortBody(500))
+		sb.WriteString("\n")
+	}
+
+	// Files (if available)
+	if len(pr.FilePaths) > 0 {
+		sb.WriteString("\n**Modified Files:**\n\n")
+		for _, file := range pr.FilePaths {
+			sb.WriteString(fmt.Sprintf("- `%s`\n", file))
+		}
+	}
+
+	sb.WriteString("\n---\n\n")
+}
+
+// writePRLine writes a PR as a single numbered line with its title, link,
+// author and reference, or as laid out by opts.DetailsFormat. PRs collapsed
+// into pr by --dedupe-by are listed after the reference.
+func writePRLine(sb *strings.Builder, n int, pr *model.PR, dupes []*model.PR, opts Options, links *linker) {
+	var line string
+	if opts.DetailsFormat != "" {
+		line = expandDetailsFormat(opts.DetailsFormat, pr)
+		if len(dupes) > 0 {
+			line += fmt.Sprintf(" (also %s)", prRefs(dupes[0], dupes[1:]))
+		}
+	} else {
+		title := escapeInline(pr.Title)
+		if pr.HTMLURL != "" {
+			title = links.link(title, pr.HTMLURL)
+		}
+		title = revertedTitle(pr, title)
+		line = fmt.Sprintf("%s by %s (%s)", title, pr.Author, prRefs(pr, dupes))
+		if pr.Scope != "" {
+			line += fmt.Sprintf(" [%s]", pr.Scope)
+		}
+	}
+	if opts.StatusIcons {
+		line = statusIcon(pr) + " " + line
+	}
+	sb.WriteString(fmt.Sprintf("%d. %s\n", n, line))
+}
+
 // RenderTable generates a simple table view of PRs for dry-run mode
 func RenderTable(prs []*model.PR) string {
 	if len(prs) == 0 {
@@ -130,10 +361,7 @@ func RenderTable(prs []*model.PR) string {
 
 	// Rows
 	for i, pr := range prs {
-		title := pr.Title
-		if len(title) > 40 {
-			title = title[:37] + "..."
-		}
+		title := pr.ShortTitle(40)
 
 		author := pr.Author
 		if len(author) > 15 {
@@ -145,13 +373,8 @@ func RenderTable(prs []*model.PR) string {
 			repository = repository[:17] + "..."
 		}
 
-		mergedAt := "N/A"
-		if pr.MergedAt != nil {
-			mergedAt = pr.MergedAt.Format("2006-01-02")
-		}
-
 		sb.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %s |\n",
-			i+1, title, author, repository, mergedAt))
+			i+1, escapeTableCell(title), escapeTableCell(author), escapeTableCell(repository), pr.MergedDate()))
 	}
 
 	sb.WriteString(fmt.Sprintf("\nTotal: %d pull request(s)\n", len(prs)))
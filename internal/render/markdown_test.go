@@ -1,11 +1,13 @@
 package render
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/willis7/prtool/internal/model"
 )
@@ -20,20 +22,23 @@ func TestRender(t *testing.T) {
 		name       string
 		metadata   Metadata
 		prs        []*model.PR
+		opts       Options
 		goldenFile string
 	}{
 		{
 			name: "full_report_with_multiple_prs",
 			metadata: Metadata{
-				GeneratedAt:  fixedTime,
-				Scope:        "organization",
-				ScopeValue:   "acme-corp",
-				Since:        "-7d",
-				TotalPRs:     2,
-				Repositories: []string{"acme-corp/web-app", "acme-corp/api-service"},
-				LLMProvider:  "openai",
-				LLMModel:     "gpt-4",
-				Summary:      "This week saw significant improvements to the authentication system and API performance optimizations.",
+				GeneratedAt:       fixedTime,
+				Scope:             "organization",
+				ScopeValue:        "acme-corp",
+				Since:             "-7d",
+				TotalPRs:          2,
+				TotalContributors: 2,
+				TotalRepositories: 2,
+				Repositories:      []string{"acme-corp/web-app", "acme-corp/api-service"},
+				LLMProvider:       "openai",
+				LLMModel:          "gpt-4",
+				Summary:           "This week saw significant improvements to the authentication system and API performance optimizations.",
 			},
 			prs: []*model.PR{
 				{
@@ -66,12 +71,14 @@ func TestRender(t *testing.T) {
 		{
 			name: "minimal_report_no_llm",
 			metadata: Metadata{
-				GeneratedAt:  fixedTime,
-				Scope:        "user",
-				ScopeValue:   "john-doe",
-				Since:        "-1d",
-				TotalPRs:     1,
-				Repositories: []string{"john-doe/personal-project"},
+				GeneratedAt:       fixedTime,
+				Scope:             "user",
+				ScopeValue:        "john-doe",
+				Since:             "-1d",
+				TotalPRs:          1,
+				TotalContributors: 1,
+				TotalRepositories: 1,
+				Repositories:      []string{"john-doe/personal-project"},
 			},
 			prs: []*model.PR{
 				{
@@ -88,12 +95,14 @@ func TestRender(t *testing.T) {
 		{
 			name: "empty_report",
 			metadata: Metadata{
-				GeneratedAt:  fixedTime,
-				Scope:        "repository",
-				ScopeValue:   "example/empty-repo",
-				Since:        "-30d",
-				TotalPRs:     0,
-				Repositories: []string{"example/empty-repo"},
+				GeneratedAt:       fixedTime,
+				Scope:             "repository",
+				ScopeValue:        "example/empty-repo",
+				Since:             "-30d",
+				TotalPRs:          0,
+				TotalContributors: 0,
+				TotalRepositories: 0,
+				Repositories:      []string{"example/empty-repo"},
 			},
 			prs:        []*model.PR{},
 			goldenFile: "empty_report.md",
@@ -101,12 +110,14 @@ func TestRender(t *testing.T) {
 		{
 			name: "long_description_truncation",
 			metadata: Metadata{
-				GeneratedAt:  fixedTime,
-				Scope:        "organization",
-				ScopeValue:   "test-org",
-				Since:        "-7d",
-				TotalPRs:     1,
-				Repositories: []string{"test-org/repo"},
+				GeneratedAt:       fixedTime,
+				Scope:             "organization",
+				ScopeValue:        "test-org",
+				Since:             "-7d",
+				TotalPRs:          1,
+				TotalContributors: 1,
+				TotalRepositories: 1,
+				Repositories:      []string{"test-org/repo"},
 			},
 			prs: []*model.PR{
 				{
@@ -121,12 +132,200 @@ func TestRender(t *testing.T) {
 			},
 			goldenFile: "truncated_description.md",
 		},
+		{
+			name: "report_with_filters",
+			metadata: Metadata{
+				GeneratedAt:       fixedTime,
+				Scope:             "organization",
+				ScopeValue:        "acme-corp",
+				Since:             "-14d",
+				Filters:           []string{"since=-14d", "state=merged", "strip-pr-template"},
+				TotalPRs:          1,
+				TotalContributors: 1,
+				TotalRepositories: 1,
+				Repositories:      []string{"acme-corp/web-app"},
+			},
+			prs: []*model.PR{
+				{
+					Title:      "Add rate limiting",
+					Author:     "alice-dev",
+					Repository: "acme-corp/web-app",
+					Number:     321,
+					MergedAt:   &mergedTime1,
+					State:      "closed",
+				},
+			},
+			goldenFile: "filters_report.md",
+		},
+		{
+			name: "report_grouped_by_base_branch",
+			metadata: Metadata{
+				GeneratedAt:       fixedTime,
+				Scope:             "repository",
+				ScopeValue:        "acme-corp/web-app",
+				Since:             "-7d",
+				TotalPRs:          3,
+				TotalContributors: 2,
+				TotalRepositories: 1,
+				Repositories:      []string{"acme-corp/web-app"},
+			},
+			prs: []*model.PR{
+				{
+					Title:      "Fix checkout rounding",
+					Author:     "alice-dev",
+					Repository: "acme-corp/web-app",
+					Number:     130,
+					MergedAt:   &mergedTime1,
+					State:      "closed",
+					BaseBranch: "release/1.2",
+				},
+				{
+					Title:      "Add dark mode",
+					Author:     "bob-smith",
+					Repository: "acme-corp/web-app",
+					Number:     131,
+					MergedAt:   &mergedTime1,
+					State:      "closed",
+					BaseBranch: "main",
+				},
+				{
+					Title:      "Update dependencies",
+					Author:     "alice-dev",
+					Repository: "acme-corp/web-app",
+					Number:     132,
+					MergedAt:   &mergedTime2,
+					State:      "closed",
+					BaseBranch: "main",
+				},
+			},
+			opts:       Options{GroupBy: GroupByBase},
+			goldenFile: "grouped_by_base.md",
+		},
+		{
+			name: "report_with_heading_offset",
+			metadata: Metadata{
+				GeneratedAt:       fixedTime,
+				Scope:             "repository",
+				ScopeValue:        "acme-corp/web-app",
+				Since:             "-7d",
+				TotalPRs:          2,
+				TotalContributors: 2,
+				TotalRepositories: 1,
+				Repositories:      []string{"acme-corp/web-app"},
+				Summary:           "Two fixes landed this week.",
+			},
+			prs: []*model.PR{
+				{
+					Title:      "Fix checkout rounding",
+					Author:     "alice-dev",
+					Repository: "acme-corp/web-app",
+					Number:     130,
+					MergedAt:   &mergedTime1,
+					State:      "closed",
+					Labels:     []string{"bug"},
+					BaseBranch: "release/1.2",
+				},
+				{
+					Title:      "Fix login redirect",
+					Author:     "bob-smith",
+					Repository: "acme-corp/web-app",
+					Number:     131,
+					MergedAt:   &mergedTime2,
+					State:      "closed",
+					Labels:     []string{"bug"},
+					BaseBranch: "main",
+				},
+			},
+			opts:       Options{GroupBy: GroupByBase, HeadingOffset: 1},
+			goldenFile: "heading_offset.md",
+		},
+		{
+			name: "report_without_details_body",
+			metadata: Metadata{
+				GeneratedAt:       fixedTime,
+				Scope:             "repository",
+				ScopeValue:        "acme-corp/web-app",
+				Since:             "-7d",
+				TotalPRs:          2,
+				TotalContributors: 2,
+				TotalRepositories: 1,
+				Repositories:      []string{"acme-corp/web-app"},
+			},
+			prs: []*model.PR{
+				{
+					Title:      "Fix checkout rounding",
+					Body:       "Rounds totals to the nearest cent before tax.",
+					Author:     "alice-dev",
+					Repository: "acme-corp/web-app",
+					Number:     130,
+					MergedAt:   &mergedTime1,
+					State:      "closed",
+					HTMLURL:    "https://github.com/acme-corp/web-app/pull/130",
+				},
+				{
+					Title:      "Add dark mode",
+					Body:       "Adds a theme toggle to the settings page.",
+					Author:     "bob-smith",
+					Repository: "acme-corp/web-app",
+					Number:     131,
+					MergedAt:   &mergedTime2,
+					State:      "closed",
+					HTMLURL:    "https://github.com/acme-corp/web-app/pull/131",
+				},
+			},
+			opts:       Options{NoDetailsBody: true},
+			goldenFile: "no_details_body.md",
+		},
+		{
+			name: "reference link style",
+			metadata: Metadata{
+				GeneratedAt:       fixedTime,
+				Scope:             "repository",
+				ScopeValue:        "acme-corp/web-app",
+				Since:             "-7d",
+				TotalPRs:          3,
+				TotalContributors: 3,
+				TotalRepositories: 2,
+				Repositories:      []string{"acme-corp/api", "acme-corp/web-app"},
+			},
+			prs: []*model.PR{
+				{
+					Title:      "Fix checkout rounding",
+					Author:     "alice-dev",
+					Repository: "acme-corp/web-app",
+					Number:     130,
+					MergedAt:   &mergedTime1,
+					State:      "closed",
+					HTMLURL:    "https://github.com/acme-corp/web-app/pull/130",
+				},
+				{
+					Title:      "Rate limit login",
+					Author:     "carol",
+					Repository: "acme-corp/api",
+					Number:     12,
+					MergedAt:   &mergedTime2,
+					State:      "closed",
+					HTMLURL:    "https://github.com/acme-corp/api/pull/12",
+				},
+				{
+					Title:      "Add dark mode",
+					Author:     "bob-smith",
+					Repository: "acme-corp/web-app",
+					Number:     131,
+					MergedAt:   &mergedTime2,
+					State:      "closed",
+					HTMLURL:    "https://github.com/acme-corp/web-app/pull/131",
+				},
+			},
+			opts:       Options{GroupBy: GroupByRepo, LinkStyle: LinkStyleReference},
+			goldenFile: "reference_links.md",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Generate the markdown
-			result := Render(tt.metadata, tt.prs)
+			result := RenderWithOptions(tt.metadata, tt.prs, tt.opts)
 
 			// Compare with golden file
 			if shouldUpdateGolden() {
@@ -167,6 +366,25 @@ func TestRenderTable(t *testing.T) {
 |---|-------|--------|------------|----------|
 | 1 | Fix critical bug | alice | org/repo | 2024-01-14 |
 
+Total: 1 pull request(s)
+`,
+		},
+		{
+			name: "pipe_in_title",
+			prs: []*model.PR{
+				{
+					Title:      "[WIP] Fix | pipe",
+					Author:     "alice",
+					Repository: "org/repo",
+					MergedAt:   &fixedTime,
+				},
+			},
+			expected: `Found Pull Requests:
+
+| # | Title | Author | Repository | Merged At |
+|---|-------|--------|------------|----------|
+| 1 | [WIP] Fix \| pipe | alice | org/repo | 2024-01-14 |
+
 Total: 1 pull request(s)
 `,
 		},
@@ -208,6 +426,35 @@ Total: 2 pull request(s)
 	}
 }
 
+func TestRenderTable_PipeKeepsColumns(t *testing.T) {
+	result := RenderTable([]*model.PR{
+		{Title: "a | b | c", Author: "alice", Repository: "org/repo"},
+	})
+
+	for _, line := range strings.Split(result, "\n") {
+		if !strings.HasPrefix(line, "| 1 ") {
+			continue
+		}
+		unescaped := strings.Count(line, "|") - strings.Count(line, `\|`)
+		if unescaped != 6 {
+			t.Errorf("Expected 6 column separators, got %d in %q", unescaped, line)
+		}
+	}
+}
+
+func TestRender_EscapesTitleMarkup(t *testing.T) {
+	result := Render(Metadata{}, []*model.PR{
+		{Title: "Rename *_config_* to <settings>", Labels: []string{"needs_review"}},
+	})
+
+	if !strings.Contains(result, `### 1. Rename \*\_config\_\* to \<settings\>`) {
+		t.Errorf("Expected escaped title heading, got:\n%s", result)
+	}
+	if !strings.Contains(result, `- **Labels**: needs\_review`) {
+		t.Errorf("Expected escaped labels, got:\n%s", result)
+	}
+}
+
 func TestMetadata_Validation(t *testing.T) {
 	// Test that metadata fields are properly formatted
 	fixedTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
@@ -280,3 +527,408 @@ func compareWithGolden(t *testing.T, filename, actual string) {
 			goldenPath, string(expected), actual)
 	}
 }
+
+func TestGroupPRs(t *testing.T) {
+	monday := time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)
+	sunday := time.Date(2024, 1, 14, 23, 0, 0, 0, time.UTC)
+	nextWeek := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+
+	prs := []*model.PR{
+		{Title: "A", Author: "bob", Repository: "org/web", MergedAt: &monday, Labels: []string{"bug", "ui"}, Milestone: "v2", RepoLanguage: "TypeScript"},
+		{Title: "B", Author: "alice", Repository: "org/api", MergedAt: &sunday},
+		{Title: "C", Author: "bob", Repository: "org/api", MergedAt: &nextWeek, Labels: []string{"bug"}, Milestone: "v1", RepoLanguage: "Go"},
+	}
+
+	tests := []struct {
+		groupBy  string
+		expected string
+	}{
+		{GroupByRepo, "org/api[B C] org/web[A]"},
+		{GroupByAuthor, "alice[B] bob[A C]"},
+		{GroupByLabel, "bug[A C] ui[A] unlabeled[B]"},
+		{GroupByWeek, "2024-01-08[A B] 2024-01-15[C]"},
+		{GroupByMilestone, "no milestone[B] v1[C] v2[A]"},
+		{GroupByLanguage, "Go[C] TypeScript[A] Unknown[B]"},
+		{GroupByNone, ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			var parts []string
+			for _, group := range groupPRs(prs, tt.groupBy) {
+				var titles []string
+				for _, pr := range group.PRs {
+					titles = append(titles, pr.Title)
+				}
+				parts = append(parts, fmt.Sprintf("%s[%s]", group.Name, strings.Join(titles, " ")))
+			}
+			if got := strings.Join(parts, " "); got != tt.expected {
+				t.Errorf("Expected groups %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderWithOptions_MinLabelCount(t *testing.T) {
+	prs := []*model.PR{
+		{Title: "A", Author: "alice", Repository: "org/api", Labels: []string{"bug", "ui"}},
+		{Title: "B", Author: "bob", Repository: "org/api", Labels: []string{"bug", "docs"}},
+		{Title: "C", Author: "carol", Repository: "org/api", Labels: []string{"bug", "docs"}},
+	}
+
+	tests := []struct {
+		name     string
+		min      int
+		shown    []string
+		hidden   []string
+		noLabels bool
+	}{
+		{name: "default shows all", min: 0, shown: []string{"- bug: 3", "- docs: 2", "- ui: 1"}},
+		{name: "threshold 2 hides one-off labels", min: 2, shown: []string{"- bug: 3", "- docs: 2"}, hidden: []string{"- ui: 1"}},
+		{name: "threshold above all counts hides the section", min: 4, noLabels: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := RenderWithOptions(Metadata{}, prs, Options{MinLabelCount: tt.min})
+
+			if tt.noLabels {
+				if strings.Contains(result, "## Labels") {
+					t.Errorf("Expected no label breakdown, got:\n%s", result)
+				}
+			}
+			for _, want := range tt.shown {
+				if !strings.Contains(result, want+"\n") {
+					t.Errorf("Expected label breakdown to contain %q", want)
+				}
+			}
+			for _, unwanted := range tt.hidden {
+				if strings.Contains(result, unwanted+"\n") {
+					t.Errorf("Expected label breakdown to omit %q", unwanted)
+				}
+			}
+
+			// Per-PR labels are never filtered
+			if !strings.Contains(result, "- **Labels**: bug, ui") {
+				t.Error("Expected per-PR labels to be rendered in full")
+			}
+		})
+	}
+}
+
+func TestRenderWithOptions_RepoOrderActivity(t *testing.T) {
+	prs := []*model.PR{
+		{Title: "A1", Repository: "org/alpha"},
+		{Title: "B1", Repository: "org/busy"},
+		{Title: "B2", Repository: "org/busy"},
+		{Title: "B3", Repository: "org/busy"},
+		{Title: "M1", Repository: "org/mid"},
+		{Title: "M2", Repository: "org/mid"},
+	}
+
+	tests := []struct {
+		repoOrder string
+		expected  []string
+	}{
+		{repoOrder: "", expected: []string{"### org/alpha (1)", "### org/busy (3)", "### org/mid (2)"}},
+		{repoOrder: RepoOrderAlpha, expected: []string{"### org/alpha (1)", "### org/busy (3)", "### org/mid (2)"}},
+		{repoOrder: RepoOrderActivity, expected: []string{"### org/busy (3)", "### org/mid (2)", "### org/alpha (1)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("order "+tt.repoOrder, func(t *testing.T) {
+			result := RenderWithOptions(Metadata{}, prs, Options{GroupBy: GroupByRepo, RepoOrder: tt.repoOrder})
+
+			last := -1
+			for _, heading := range tt.expected {
+				idx := strings.Index(result, heading)
+				if idx < 0 {
+					t.Fatalf("Expected heading %q in report", heading)
+				}
+				if idx < last {
+					t.Errorf("Expected %q to come after the previous group", heading)
+				}
+				last = idx
+			}
+		})
+	}
+}
+
+func TestRenderWithOptions_HeadingOffset(t *testing.T) {
+	merged := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	meta := Metadata{Summary: "Summary", TotalPRs: 1}
+	prs := []*model.PR{{Title: "Fix", Author: "alice", Repository: "org/api", MergedAt: &merged, Labels: []string{"bug"}}}
+
+	for _, groupBy := range []string{GroupByNone, GroupByRepo} {
+		t.Run(groupBy, func(t *testing.T) {
+			base := strings.Split(RenderWithOptions(meta, prs, Options{GroupBy: groupBy}), "\n")
+			shifted := strings.Split(RenderWithOptions(meta, prs, Options{GroupBy: groupBy, HeadingOffset: 1}), "\n")
+
+			if len(base) != len(shifted) {
+				t.Fatalf("Expected the same number of lines, got %d and %d", len(base), len(shifted))
+			}
+			headings := 0
+			for i, line := range base {
+				if strings.HasPrefix(line, "#") {
+					headings++
+					if shifted[i] != "#"+line {
+						t.Errorf("Expected heading %q to become %q, got %q", line, "#"+line, shifted[i])
+					}
+				} else if shifted[i] != line {
+					t.Errorf("Expected non-heading line %q to be unchanged, got %q", line, shifted[i])
+				}
+			}
+			if headings == 0 {
+				t.Error("Expected the report to contain headings")
+			}
+		})
+	}
+
+	if got := heading(4, 5); got != "######" {
+		t.Errorf("Expected headings to be capped at h6, got %q", got)
+	}
+}
+
+func TestRenderWithOptions_EmptySections(t *testing.T) {
+	merged := time.Date(2024, 1, 17, 10, 0, 0, 0, time.UTC)
+	since := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)
+	meta := Metadata{
+		TotalPRs:            1,
+		Repositories:        []string{"org/api"},
+		ScannedRepositories: []string{"org/api", "org/idle"},
+		SinceTime:           &since,
+		UntilTime:           &until,
+	}
+	prs := []*model.PR{{Title: "Fix", Author: "alice", Repository: "org/api", MergedAt: &merged}}
+
+	tests := []struct {
+		groupBy string
+		empty   []string
+		kept    string
+	}{
+		{groupBy: GroupByRepo, empty: []string{"### org/idle (0)"}, kept: "### org/api (1)"},
+		{groupBy: GroupByWeek, empty: []string{"### Week of 2024-01-01 (0)", "### Week of 2024-01-08 (0)"}, kept: "### Week of 2024-01-15 (1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupBy, func(t *testing.T) {
+			pruned := RenderWithOptions(meta, prs, Options{GroupBy: tt.groupBy})
+			shown := RenderWithOptions(meta, prs, Options{GroupBy: tt.groupBy, ShowEmptySections: true})
+
+			for _, heading := range tt.empty {
+				if strings.Contains(pruned, heading) {
+					t.Errorf("Expected empty group %q to be pruned by default", heading)
+				}
+				if !strings.Contains(shown, heading+"\n\n_No pull requests._\n") {
+					t.Errorf("Expected empty group %q to be shown when forced, got:\n%s", heading, shown)
+				}
+			}
+			if !strings.Contains(pruned, tt.kept) || !strings.Contains(shown, tt.kept) {
+				t.Errorf("Expected group %q in both reports", tt.kept)
+			}
+			if strings.Contains(pruned, "_No pull requests._") {
+				t.Error("Expected no empty placeholder when pruning")
+			}
+		})
+	}
+
+	t.Run("groups stay sorted", func(t *testing.T) {
+		shown := RenderWithOptions(meta, prs, Options{GroupBy: GroupByWeek, ShowEmptySections: true})
+		if strings.Index(shown, "Week of 2024-01-08") > strings.Index(shown, "Week of 2024-01-15") {
+			t.Error("Expected empty weeks to be ordered with the others")
+		}
+	})
+}
+
+func TestRender_ReportID(t *testing.T) {
+	meta := Metadata{ReportID: "weekly-2024-03"}
+
+	markdown := Render(meta, nil)
+	if !strings.Contains(markdown, "*Generated by prtool (report weekly-2024-03)*\n") {
+		t.Errorf("Expected report ID in the footer, got:\n%s", markdown)
+	}
+
+	output, err := RenderJSON(meta, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, `"report_id": "weekly-2024-03"`) {
+		t.Errorf("Expected report_id in JSON output, got:\n%s", output)
+	}
+
+	if markdown := Render(Metadata{}, nil); !strings.Contains(markdown, "*Generated by prtool*\n") {
+		t.Errorf("Expected the plain footer without a report ID, got:\n%s", markdown)
+	}
+}
+
+func TestRenderWithOptions_StatusIcons(t *testing.T) {
+	mergedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	prs := []*model.PR{
+		{Number: 1, Title: "Merged change", State: "closed", MergedAt: &mergedAt},
+		{Number: 2, Title: "Open change", State: "open"},
+		{Number: 3, Title: "Rejected change", State: "closed"},
+		{Number: 4, Title: "Draft change", State: "open", IsDraft: true},
+	}
+	meta := Metadata{GeneratedAt: mergedAt, TotalPRs: len(prs)}
+
+	expected := []string{
+		"### 1. ✅ Merged change",
+		"### 2. 🔄 Open change",
+		"### 3. ❌ Rejected change",
+		"### 4. 📝 Draft change",
+	}
+
+	output := RenderWithOptions(meta, prs, Options{StatusIcons: true})
+	for _, want := range expected {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+
+	lines := RenderWithOptions(meta, prs, Options{StatusIcons: true, NoDetailsBody: true})
+	if !strings.Contains(lines, "3. ❌ Rejected change by") {
+		t.Errorf("Expected an icon on one-line entries, got:\n%s", lines)
+	}
+
+	plain := RenderWithOptions(meta, prs, Options{})
+	for _, icon := range []string{StatusIconMerged, StatusIconOpen, StatusIconClosed, StatusIconDraft} {
+		if strings.Contains(plain, icon) {
+			t.Errorf("Expected no status icons by default, got:\n%s", plain)
+		}
+	}
+}
+
+func TestRenderWithOptions_DetailsFormat(t *testing.T) {
+	mergedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	prs := []*model.PR{{
+		Number:     123,
+		Title:      "Add *rate* limiting",
+		Author:     "alice",
+		Repository: "acme/api",
+		MergedAt:   &mergedAt,
+		HTMLURL:    "https://github.com/acme/api/pull/123",
+		Labels:     []string{"feature", "security"},
+	}}
+	meta := Metadata{GeneratedAt: mergedAt, TotalPRs: len(prs)}
+
+	output := RenderWithOptions(meta, prs, Options{
+		DetailsFormat: "{merged} #{number} {title} ({author}, {repo}) [{labels}] {url}",
+	})
+	expected := "1. 2024-01-15 #123 Add \\*rate\\* limiting (alice, acme/api) [feature, security] https://github.com/acme/api/pull/123\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected %q in output, got:\n%s", expected, output)
+	}
+	if strings.Contains(output, "**Author**") {
+		t.Errorf("Expected one-line entries instead of the full layout, got:\n%s", output)
+	}
+
+	if err := ValidateDetailsFormat("{number} {title}"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := ValidateDetailsFormat("{number} {body}"); err == nil || !strings.Contains(err.Error(), "{body}") {
+		t.Errorf("Expected an unknown placeholder error, got %v", err)
+	}
+}
+
+func TestRenderWithOptions_DedupeByTitle(t *testing.T) {
+	prs := []*model.PR{
+		{Number: 10, Title: "Bump logging lib", Author: "renovate", Repository: "acme/orders"},
+		{Number: 11, Title: "Add refund endpoint", Author: "alice", Repository: "acme/orders"},
+		{Number: 4, Title: "bump  logging lib.", Author: "renovate", Repository: "acme/billing"},
+		{Number: 7, Title: "Bump logging lib", Author: "renovate", Repository: "acme/users"},
+	}
+	meta := Metadata{TotalPRs: len(prs)}
+
+	output := RenderWithOptions(meta, prs, Options{DedupeBy: DedupeByTitle})
+	expected := "1. Bump logging lib by renovate (acme/orders#10, acme/billing#4, acme/users#7)\n" +
+		"2. Add refund endpoint by alice (acme/orders#11)\n"
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected %q in output, got:\n%s", expected, output)
+	}
+	if strings.Count(output, "ogging lib") != 1 {
+		t.Errorf("Expected the same-titled PRs to collapse into one entry, got:\n%s", output)
+	}
+}
+
+func TestRenderWithOptions_AuthorSummaries(t *testing.T) {
+	prs := []*model.PR{
+		{Number: 1, Title: "Add retries", Author: "alice"},
+		{Number: 2, Title: "Fix login", Author: "bob"},
+	}
+	meta := Metadata{TotalPRs: len(prs), AuthorSummaries: map[string]string{"alice": "Alice made the client resilient.\n"}}
+
+	output := RenderWithOptions(meta, prs, Options{GroupBy: GroupByAuthor})
+	if !strings.Contains(output, "### alice (1)\n\nAlice made the client resilient.\n\n#### 1. Add retries") {
+		t.Errorf("Expected alice's summary under her heading, got:\n%s", output)
+	}
+	if !strings.Contains(output, "### bob (1)\n\n#### 2. Fix login") {
+		t.Errorf("Expected no summary for bob, got:\n%s", output)
+	}
+
+	if ungrouped := RenderWithOptions(meta, prs, Options{}); strings.Contains(ungrouped, "resilient") {
+		t.Errorf("Expected author summaries only with author groups, got:\n%s", ungrouped)
+	}
+}
+
+func TestRenderWithOptions_RepoDescriptions(t *testing.T) {
+	prs := []*model.PR{
+		{Number: 1, Title: "Add retries", Repository: "acme/api", RepoDescription: "Public *REST* API\n"},
+		{Number: 2, Title: "Fix login", Repository: "acme/web"},
+	}
+	meta := Metadata{TotalPRs: len(prs)}
+
+	output := RenderWithOptions(meta, prs, Options{GroupBy: GroupByRepo, RepoDescriptions: true})
+	if !strings.Contains(output, "### acme/api (1)\n\n_Public \\*REST\\* API_\n\n#### 1. Add retries") {
+		t.Errorf("Expected the description under the repo heading, got:\n%s", output)
+	}
+	if !strings.Contains(output, "### acme/web (1)\n\n#### 2. Fix login") {
+		t.Errorf("Expected no description line for a repo without one, got:\n%s", output)
+	}
+
+	if without := RenderWithOptions(meta, prs, Options{GroupBy: GroupByRepo}); strings.Contains(without, "Public") {
+		t.Errorf("Expected descriptions only when enabled, got:\n%s", without)
+	}
+	if byAuthor := RenderWithOptions(meta, prs, Options{GroupBy: GroupByAuthor, RepoDescriptions: true}); strings.Contains(byAuthor, "Public") {
+		t.Errorf("Expected descriptions only with repo groups, got:\n%s", byAuthor)
+	}
+}
+
+func TestRender_RevertedPR(t *testing.T) {
+	prs := []*model.PR{
+		{Number: 10, Title: "Add caching layer", Author: "alice", Repository: "acme/api", RevertedBy: 20},
+		{Number: 20, Title: `Revert "Add caching layer"`, Author: "bob", Repository: "acme/api"},
+	}
+	meta := Metadata{TotalPRs: len(prs)}
+
+	output := Render(meta, prs)
+	if !strings.Contains(output, "### 1. ~~Add caching layer~~ (reverted by #20)") {
+		t.Errorf("Expected the original PR to be flagged, got:\n%s", output)
+	}
+	if strings.Contains(output, "~~Revert") {
+		t.Errorf("Expected the revert PR not to be flagged, got:\n%s", output)
+	}
+
+	lines := RenderWithOptions(meta, prs, Options{NoDetailsBody: true})
+	if !strings.Contains(lines, "1. ~~Add caching layer~~ (reverted by #20) by alice") {
+		t.Errorf("Expected one-line entries to be flagged, got:\n%s", lines)
+	}
+}
+
+func TestRender_TruncatesMultibyteBody(t *testing.T) {
+	// 499 ASCII bytes put the first byte of "é" at byte 499, so a byte cut at
+	// 500 would split it
+	body := strings.Repeat("a", 499) + strings.Repeat("é", 300)
+	prs := []*model.PR{{Number: 1, Title: "Localize", Author: "alice", Repository: "acme/api", Body: body}}
+
+	output := Render(Metadata{TotalPRs: 1}, prs)
+
+	if !utf8.ValidString(output) {
+		t.Fatal("Expected the truncated body to be valid UTF-8")
+	}
+	expected := strings.Repeat("a", 497) + "..."
+	if !strings.Contains(output, expected+"\n") {
+		t.Errorf("Expected the body cut to 500 characters, got:\n%s", output)
+	}
+}
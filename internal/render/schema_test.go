@@ -0,0 +1,125 @@
+package render
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+func TestJSONSchema(t *testing.T) {
+	output, err := JSONSchema()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var schema struct {
+		Schema     string `json:"$schema"`
+		Type       string `json:"type"`
+		Properties map[string]struct {
+			Type       interface{}                `json:"type"`
+			Properties map[string]json.RawMessage `json:"properties"`
+			Items      struct {
+				AnyOf []struct {
+					Properties map[string]json.RawMessage `json:"properties"`
+				} `json:"anyOf"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(output), &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v\n%s", err, output)
+	}
+
+	if schema.Schema == "" || schema.Type != "object" {
+		t.Errorf("Expected a top-level object schema, got %+v", schema)
+	}
+
+	// Pull requests are pointers, so their object schema is the first anyOf branch
+	prItems := schema.Properties["pull_requests"].Items.AnyOf
+	if len(prItems) != 2 {
+		t.Fatalf("Expected pull request items to be an object or null, got %+v", prItems)
+	}
+	prProperties := prItems[0].Properties
+	for _, name := range jsonFieldNames(t, reflect.TypeOf(model.PR{})) {
+		if _, ok := prProperties[name]; !ok {
+			t.Errorf("Expected schema to list PR field %q", name)
+		}
+	}
+
+	metaProperties := schema.Properties["metadata"].Properties
+	for _, name := range jsonFieldNames(t, reflect.TypeOf(Metadata{})) {
+		if _, ok := metaProperties[name]; !ok {
+			t.Errorf("Expected schema to list metadata field %q", name)
+		}
+	}
+}
+
+func TestJSONSchema_FieldTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected interface{}
+	}{
+		{"string", "", "string"},
+		{"integer", 0, "integer"},
+		{"boolean", false, "boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := typeSchema(reflect.TypeOf(tt.value))
+			if schema["type"] != tt.expected {
+				t.Errorf("Expected type %v, got %v", tt.expected, schema["type"])
+			}
+		})
+	}
+
+	if format := typeSchema(timeType)["format"]; format != "date-time" {
+		t.Errorf("Expected time.Time to use date-time format, got %v", format)
+	}
+}
+
+func TestJSONSchema_Pointers(t *testing.T) {
+	type nested struct {
+		Name string `json:"name"`
+	}
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"pointer to time", (*time.Time)(nil), `{"anyOf":[{"format":"date-time","type":"string"},{"type":"null"}]}`},
+		{"pointer to slice", (*[]string)(nil), `{"anyOf":[{"items":{"type":"string"},"type":["array","null"]},{"type":"null"}]}`},
+		{"pointer to struct", (*nested)(nil), `{"anyOf":[{"properties":{"name":{"type":"string"}},"required":["name"],"type":"object"},{"type":"null"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(typeSchema(reflect.TypeOf(tt.value)))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(data) != tt.expected {
+				t.Errorf("Expected schema %s, got %s", tt.expected, data)
+			}
+		})
+	}
+}
+
+// jsonFieldNames returns the JSON names of every exported field of a struct type
+func jsonFieldNames(t *testing.T, typ reflect.Type) []string {
+	t.Helper()
+
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		if name, _, ok := jsonFieldName(typ.Field(i)); ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		t.Fatalf("Expected %s to have JSON fields", typ)
+	}
+	return names
+}
@@ -0,0 +1,39 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// Status indicators shown before PR titles with Options.StatusIcons
+const (
+	StatusIconMerged = "✅"
+	StatusIconOpen   = "🔄"
+	StatusIconClosed = "❌"
+	StatusIconDraft  = "📝"
+)
+
+// statusIcon returns the indicator for the PR's state: merged, draft, open,
+// or closed without being merged
+func statusIcon(pr *model.PR) string {
+	switch {
+	case pr.IsMerged():
+		return StatusIconMerged
+	case pr.IsDraft:
+		return StatusIconDraft
+	case pr.State == "open":
+		return StatusIconOpen
+	default:
+		return StatusIconClosed
+	}
+}
+
+// revertedTitle strikes through the title of a PR that was later reverted
+// and notes the reverting PR
+func revertedTitle(pr *model.PR, title string) string {
+	if pr.RevertedBy == 0 {
+		return title
+	}
+	return fmt.Sprintf("~~%s~~ (reverted by #%d)", title, pr.RevertedBy)
+}
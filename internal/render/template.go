@@ -0,0 +1,50 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// TemplateData is the value passed to custom report templates
+type TemplateData struct {
+	Metadata     Metadata
+	PullRequests []*model.PR
+}
+
+// templateFuncs are the helper functions available to custom templates
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"date": func(layout string, t interface{}) string {
+		switch v := t.(type) {
+		case time.Time:
+			return v.Format(layout)
+		case *time.Time:
+			if v == nil {
+				return ""
+			}
+			return v.Format(layout)
+		default:
+			return ""
+		}
+	},
+}
+
+// RenderTemplate renders the report with a user supplied text/template.
+// The template receives a TemplateData value.
+func RenderTemplate(name, text string, meta Metadata, prs []*model.PR) (string, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, TemplateData{Metadata: meta, PullRequests: prs}); err != nil {
+		return "", fmt.Errorf("failed to execute template %s: %w", name, err)
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,35 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	mergedAt := time.Date(2024, 1, 14, 15, 20, 0, 0, time.UTC)
+	meta := Metadata{ScopeValue: "acme", TotalPRs: 1}
+	prs := []*model.PR{
+		{Title: "Add OAuth", Number: 12, Labels: []string{"feature", "security"}, MergedAt: &mergedAt},
+	}
+
+	text := `{{.Metadata.ScopeValue}}: {{.Metadata.TotalPRs}}
+{{range .PullRequests}}- #{{.Number}} {{.Title}} [{{join .Labels ", "}}] {{date "2006-01-02" .MergedAt}}
+{{end}}`
+
+	output, err := RenderTemplate("exec", text, meta, prs)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "acme: 1\n- #12 Add OAuth [feature, security] 2024-01-14\n"
+	if output != expected {
+		t.Errorf("Expected %q, got %q", expected, output)
+	}
+
+	if _, err := RenderTemplate("broken", "{{.Metadata", meta, prs); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Expected parse error naming the template, got %v", err)
+	}
+}
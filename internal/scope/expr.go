@@ -0,0 +1,359 @@
+package scope
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// RepoFilterFields lists the repository attributes available in a repo
+// filter expression
+var RepoFilterFields = []string{"name", "topic", "language", "archived", "fork", "private", "stars"}
+
+// RepoFilter is a compiled repo filter expression
+type RepoFilter struct {
+	root exprNode
+}
+
+// ParseRepoFilter compiles a repo filter expression such as
+//
+//	topic == "backend" && !archived && stars > 10
+//
+// Expressions combine comparisons with &&, || and !, and may use
+// parentheses. name, language and topic compare as strings (== and !=,
+// case-insensitive; topic matches when any topic does), stars compares as a
+// number (==, !=, <, <=, >, >=), and archived, fork and private are booleans
+// that can be used bare or compared with true/false.
+func ParseRepoFilter(expr string) (*RepoFilter, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repo filter expression: %w", err)
+	}
+
+	p := &exprParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.peek().kind != tokenEOF {
+		err = fmt.Errorf("unexpected %s", p.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid repo filter expression: %w", err)
+	}
+
+	return &RepoFilter{root: root}, nil
+}
+
+// Match reports whether repo satisfies the expression
+func (f *RepoFilter) Match(repo Repository) bool {
+	return f.root.eval(repo)
+}
+
+// filterByExpr returns the repositories matching filter
+func filterByExpr(repos []Repository, filter *RepoFilter) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		if filter.Match(repo) {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+// Expression tree
+
+type exprNode interface {
+	eval(repo Repository) bool
+}
+
+type andNode struct{ left, right exprNode }
+
+func (n andNode) eval(repo Repository) bool { return n.left.eval(repo) && n.right.eval(repo) }
+
+type orNode struct{ left, right exprNode }
+
+func (n orNode) eval(repo Repository) bool { return n.left.eval(repo) || n.right.eval(repo) }
+
+type notNode struct{ inner exprNode }
+
+func (n notNode) eval(repo Repository) bool { return !n.inner.eval(repo) }
+
+// boolField is a bare boolean attribute such as archived
+type boolField struct{ field string }
+
+func (n boolField) eval(repo Repository) bool { return boolAttr(repo, n.field) }
+
+// compareNode compares an attribute with a literal
+type compareNode struct {
+	field string
+	op    string
+	str   string
+	num   int
+	flag  bool
+}
+
+func (n compareNode) eval(repo Repository) bool {
+	switch n.field {
+	case "stars":
+		return compareInts(repo.Stars, n.op, n.num)
+	case "archived", "fork", "private":
+		return (boolAttr(repo, n.field) == n.flag) == (n.op == "==")
+	case "topic":
+		found := false
+		for _, topic := range repo.Topics {
+			if strings.EqualFold(topic, n.str) {
+				found = true
+				break
+			}
+		}
+		return found == (n.op == "==")
+	default:
+		value := repo.Name
+		if n.field == "language" {
+			value = repo.Language
+		}
+		return strings.EqualFold(value, n.str) == (n.op == "==")
+	}
+}
+
+func boolAttr(repo Repository, field string) bool {
+	switch field {
+	case "archived":
+		return repo.Archived
+	case "fork":
+		return repo.Fork
+	default:
+		return repo.Private
+	}
+}
+
+func compareInts(a int, op string, b int) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	default:
+		return a >= b
+	}
+}
+
+// Tokenizer
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOp
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return strconv.Quote(t.value)
+	default:
+		return fmt.Sprintf("'%s'", t.value)
+	}
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[start:i])})
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[start:i])})
+		case r == '"':
+			var sb strings.Builder
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			i++
+			tokens = append(tokens, token{tokenString, sb.String()})
+		default:
+			op := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")"} {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character '%c'", r)
+			}
+			tokens = append(tokens, token{tokenOp, op})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+// Parser
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) acceptOp(op string) bool {
+	if t := p.peek(); t.kind == tokenOp && t.value == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptOp("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.acceptOp("!") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	}
+	if p.acceptOp("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptOp(")") {
+			return nil, fmt.Errorf("expected ')' but found %s", p.peek())
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	t := p.next()
+	if t.kind != tokenIdent {
+		return nil, fmt.Errorf("expected a field but found %s", t)
+	}
+	field := strings.ToLower(t.value)
+
+	var op string
+	if next := p.peek(); next.kind == tokenOp {
+		switch next.value {
+		case "==", "!=", "<", "<=", ">", ">=":
+			op = next.value
+			p.pos++
+		}
+	}
+
+	switch field {
+	case "archived", "fork", "private":
+		if op == "" {
+			return boolField{field}, nil
+		}
+		if op != "==" && op != "!=" {
+			return nil, fmt.Errorf("operator '%s' is not supported for %s", op, field)
+		}
+		value := p.next()
+		if value.kind != tokenIdent || (value.value != "true" && value.value != "false") {
+			return nil, fmt.Errorf("expected true or false after %s %s but found %s", field, op, value)
+		}
+		return compareNode{field: field, op: op, flag: value.value == "true"}, nil
+	case "stars":
+		if op == "" {
+			return nil, fmt.Errorf("expected a comparison after %s", field)
+		}
+		value := p.next()
+		if value.kind != tokenNumber {
+			return nil, fmt.Errorf("expected a number after %s %s but found %s", field, op, value)
+		}
+		num, err := strconv.Atoi(value.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", value)
+		}
+		return compareNode{field: field, op: op, num: num}, nil
+	case "name", "language", "topic":
+		if op != "==" && op != "!=" {
+			return nil, fmt.Errorf("expected == or != after %s", field)
+		}
+		value := p.next()
+		if value.kind != tokenString {
+			return nil, fmt.Errorf("expected a quoted string after %s %s but found %s", field, op, value)
+		}
+		return compareNode{field: field, op: op, str: value.value}, nil
+	default:
+		return nil, fmt.Errorf("unknown field '%s' (supported: %s)", t.value, strings.Join(RepoFilterFields, ", "))
+	}
+}
@@ -0,0 +1,103 @@
+package scope
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/willis7/prtool/internal/config"
+	"github.com/willis7/prtool/internal/gh"
+)
+
+func TestRepoFilter_Match(t *testing.T) {
+	repos := []Repository{
+		{Name: "acme/api", Topics: []string{"backend", "go"}, Language: "Go", Stars: 42},
+		{Name: "acme/legacy", Topics: []string{"backend"}, Language: "Java", Stars: 80, Archived: true},
+		{Name: "acme/web", Topics: []string{"frontend"}, Language: "TypeScript", Stars: 5, Private: true},
+		{Name: "acme/api-fork", Topics: []string{"backend"}, Language: "Go", Stars: 0, Fork: true},
+	}
+
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{`topic == "backend" && !archived && stars > 10`, "acme/api"},
+		{`!fork && !archived`, "acme/api,acme/web"},
+		{`language == "go" || private`, "acme/api,acme/web,acme/api-fork"},
+		{`stars >= 42 && stars <= 80`, "acme/api,acme/legacy"},
+		{`topic != "backend"`, "acme/web"},
+		{`!(name == "acme/api" || archived == true)`, "acme/web,acme/api-fork"},
+		{`fork == false && stars != 5 && stars < 50`, "acme/api"},
+		{`name == "ACME/WEB"`, "acme/web"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			filter, err := ParseRepoFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var names []string
+			for _, repo := range filterByExpr(repos, filter) {
+				names = append(names, repo.Name)
+			}
+			if got := strings.Join(names, ","); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseRepoFilter_Errors(t *testing.T) {
+	tests := []struct {
+		expr        string
+		expectError string
+	}{
+		{`owner == "acme"`, "unknown field 'owner'"},
+		{`stars > "ten"`, "expected a number"},
+		{`topic > "a"`, "expected == or != after topic"},
+		{`topic == backend`, "expected a quoted string"},
+		{`archived > true`, "not supported for archived"},
+		{`stars`, "expected a comparison after stars"},
+		{`(fork`, "expected ')'"},
+		{`fork fork`, "unexpected 'fork'"},
+		{`name == "unterminated`, "unterminated string"},
+		{`fork; rm -rf /`, "unexpected character ';'"},
+		{``, "expected a field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseRepoFilter(tt.expr)
+			if err == nil {
+				t.Fatalf("Expected error containing %q, got nil", tt.expectError)
+			}
+			if !strings.Contains(err.Error(), tt.expectError) {
+				t.Errorf("Expected error containing %q, got %q", tt.expectError, err.Error())
+			}
+		})
+	}
+}
+
+func TestResolveRepositories_RepoFilterExpr(t *testing.T) {
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{
+		{FullName: github.String("acme/api"), Topics: []string{"backend"}, StargazersCount: github.Int(20)},
+		{FullName: github.String("acme/old"), Topics: []string{"backend"}, StargazersCount: github.Int(30), Archived: github.Bool(true)},
+		{FullName: github.String("acme/web"), Topics: []string{"frontend"}, StargazersCount: github.Int(50)},
+	})
+
+	repos, err := ResolveRepositories(&config.Config{Org: "acme", RepoFilterExpr: `topic == "backend" && !archived`}, mockClient)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "acme/api" {
+		t.Errorf("Expected only acme/api, got %+v", repos)
+	}
+
+	_, err = ResolveRepositories(&config.Config{Org: "acme", RepoFilterExpr: `stars > 100`}, mockClient)
+	if err == nil || !strings.Contains(err.Error(), "no repositories matching") {
+		t.Errorf("Expected no-match error, got %v", err)
+	}
+}
@@ -2,14 +2,55 @@ package scope
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/google/go-github/v55/github"
 	"github.com/willis7/prtool/internal/config"
 	"github.com/willis7/prtool/internal/gh"
 )
 
+// Repository describes a repository in scope along with the metadata used to
+// filter and facet the report
+type Repository struct {
+	// Name is the repository name in "owner/name" format
+	Name   string
+	Topics []string
+	// Language is the repository's primary language, empty when GitHub has none
+	Language string
+	// Description is the repository's one-line description, possibly empty
+	Description string
+	Archived    bool
+	Fork        bool
+	Private     bool
+	Stars       int
+	// PushedAt is when the repository was last pushed to, zero when unknown
+	PushedAt time.Time
+	// Scope is the team the repository was resolved from when several teams
+	// are in scope, and empty otherwise
+	Scope string
+}
+
 // ResolveRepos resolves the repository names based on the configuration scope
 // It validates that exactly one scope is specified and returns a list of repository names
 func ResolveRepos(cfg *config.Config, ghClient gh.GitHubClient) ([]string, error) {
+	repos, err := ResolveRepositories(cfg, ghClient)
+	if err != nil {
+		return nil, err
+	}
+
+	repoNames := make([]string, len(repos))
+	for i, repo := range repos {
+		repoNames[i] = repo.Name
+	}
+
+	return repoNames, nil
+}
+
+// ResolveRepositories resolves the repositories based on the configuration
+// scope, applying the topic filter when one is configured
+func ResolveRepositories(cfg *config.Config, ghClient gh.GitHubClient) ([]Repository, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("configuration is required")
 	}
@@ -48,56 +89,248 @@ func ResolveRepos(cfg *config.Config, ghClient gh.GitHubClient) ([]string, error
 	}
 
 	// Fetch repositories using the GitHub client
-	repos, err := ghClient.ListRepos(cfg)
+	repos, scopes, err := listRepos(cfg, ghClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list repositories for %s: %w", scopeType, err)
 	}
 
-	// Extract repository names in "owner/name" format
-	var repoNames []string
+	var resolved []Repository
 	for _, repo := range repos {
-		if repo.FullName != nil {
-			repoNames = append(repoNames, *repo.FullName)
-		} else if repo.Owner != nil && repo.Owner.Login != nil && repo.Name != nil {
-			// Fallback: construct from owner login and repo name
-			repoNames = append(repoNames, fmt.Sprintf("%s/%s", *repo.Owner.Login, *repo.Name))
+		name := repoName(repo)
+		if name == "" {
+			continue
 		}
+		resolved = append(resolved, Repository{
+			Name:        name,
+			Topics:      repo.Topics,
+			Language:    repo.GetLanguage(),
+			Description: repo.GetDescription(),
+			Archived:    repo.GetArchived(),
+			Fork:        repo.GetFork(),
+			Private:     repo.GetPrivate(),
+			Stars:       repo.GetStargazersCount(),
+			PushedAt:    repo.GetPushedAt().Time,
+			Scope:       scopes[name],
+		})
 	}
 
-	if len(repoNames) == 0 {
+	if len(resolved) == 0 {
 		return nil, fmt.Errorf("no repositories found for %s scope", scopeType)
 	}
 
-	return repoNames, nil
+	if cfg.Topic != "" {
+		resolved = filterByTopic(resolved, cfg.Topic)
+		if len(resolved) == 0 {
+			return nil, fmt.Errorf("no repositories with topic '%s' found for %s scope", cfg.Topic, scopeType)
+		}
+	}
+
+	if cfg.RepoFilterExpr != "" {
+		filter, err := ParseRepoFilter(cfg.RepoFilterExpr)
+		if err != nil {
+			return nil, err
+		}
+		resolved = filterByExpr(resolved, filter)
+		if len(resolved) == 0 {
+			return nil, fmt.Errorf("no repositories matching '%s' found for %s scope", cfg.RepoFilterExpr, scopeType)
+		}
+	}
+
+	sortRepositories(resolved, cfg.ScanOrder)
+
+	// Without a scan order this keeps the first repositories in listing order
+	if cfg.RepoLimit > 0 && len(resolved) > cfg.RepoLimit {
+		resolved = resolved[:cfg.RepoLimit]
+	}
+
+	return appendExtraRepos(resolved, cfg.ExtraRepos), nil
 }
 
-// ValidateScope validates that exactly one scope is specified in the configuration
-func ValidateScope(cfg *config.Config) error {
+// Supported ScanOrder values
+const (
+	// ScanOrderPushed orders repositories by their last push, most recent first
+	ScanOrderPushed = "pushed"
+	// ScanOrderAlpha orders repositories by name
+	ScanOrderAlpha = "alpha"
+	// ScanOrderStars orders repositories by stars, most starred first
+	ScanOrderStars = "stars"
+)
+
+// ScanOrderValues lists the accepted ScanOrder values
+var ScanOrderValues = []string{ScanOrderPushed, ScanOrderAlpha, ScanOrderStars}
+
+// IsValidScanOrder reports whether scanOrder is a supported ScanOrder value.
+// Empty keeps the order GitHub lists the repositories in.
+func IsValidScanOrder(scanOrder string) bool {
+	return scanOrder == "" || scanOrder == ScanOrderPushed || scanOrder == ScanOrderAlpha || scanOrder == ScanOrderStars
+}
+
+// sortRepositories orders repos in place by scanOrder. Ties keep their
+// listing order, and an empty scanOrder leaves repos unchanged.
+func sortRepositories(repos []Repository, scanOrder string) {
+	var less func(a, b Repository) bool
+	switch scanOrder {
+	case ScanOrderPushed:
+		less = func(a, b Repository) bool { return a.PushedAt.After(b.PushedAt) }
+	case ScanOrderAlpha:
+		less = func(a, b Repository) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case ScanOrderStars:
+		less = func(a, b Repository) bool { return a.Stars > b.Stars }
+	default:
+		return
+	}
+	sort.SliceStable(repos, func(i, j int) bool { return less(repos[i], repos[j]) })
+}
+
+// listRepos lists the repositories in scope. Several teams are listed one at
+// a time, and the returned map records the first team that listed each
+// repository.
+func listRepos(cfg *config.Config, ghClient gh.GitHubClient) ([]*github.Repository, map[string]string, error) {
+	if len(cfg.Team) < 2 {
+		repos, err := ghClient.ListRepos(cfg)
+		return repos, nil, err
+	}
+
+	var all []*github.Repository
+	scopes := make(map[string]string)
+	for _, team := range cfg.Team {
+		teamCfg := *cfg
+		teamCfg.Team = config.TeamList{team}
+		repos, err := ghClient.ListRepos(&teamCfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, repo := range repos {
+			name := repoName(repo)
+			if _, seen := scopes[name]; seen || name == "" {
+				continue
+			}
+			scopes[name] = team
+			all = append(all, repo)
+		}
+	}
+	return all, scopes, nil
+}
+
+// repoName returns the repository name in "owner/name" format, or "" when
+// the API response carries neither the full name nor the owner and name
+func repoName(repo *github.Repository) string {
+	if repo.FullName != nil {
+		return *repo.FullName
+	}
+	if repo.Owner != nil && repo.Owner.Login != nil && repo.Name != nil {
+		return fmt.Sprintf("%s/%s", *repo.Owner.Login, *repo.Name)
+	}
+	return ""
+}
+
+// appendExtraRepos adds explicitly named repositories that scope resolution
+// did not return, such as private repos missing from an org listing
+func appendExtraRepos(repos []Repository, extra []string) []Repository {
+	for _, name := range extra {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		duplicate := false
+		for _, repo := range repos {
+			if strings.EqualFold(repo.Name, name) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			repos = append(repos, Repository{Name: name})
+		}
+	}
+	return repos
+}
+
+// filterByTopic returns the repositories tagged with topic. GitHub topics
+// are lowercase, so the match ignores case.
+func filterByTopic(repos []Repository, topic string) []Repository {
+	var filtered []Repository
+	for _, repo := range repos {
+		for _, t := range repo.Topics {
+			if strings.EqualFold(t, topic) {
+				filtered = append(filtered, repo)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// validateHosts checks that each host profile has a unique name and at least
+// one org or repository in owner/repo format
+func validateHosts(hosts []config.HostProfile) error {
+	seen := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		if host.Name == "" {
+			return fmt.Errorf("every host needs a name")
+		}
+		if seen[host.Name] {
+			return fmt.Errorf("duplicate host name '%s'", host.Name)
+		}
+		seen[host.Name] = true
+
+		if len(host.Orgs) == 0 && len(host.Repos) == 0 {
+			return fmt.Errorf("host '%s' needs at least one org or repo", host.Name)
+		}
+		for _, repo := range host.Repos {
+			if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+				return fmt.Errorf("host '%s' repository '%s' must be in format 'owner/repo'", host.Name, repo)
+			}
+		}
+	}
+	return nil
+}
+
+// SelfAlias is the shorthand that refers to the authenticated user
+const SelfAlias = "@me"
+
+// ResolveSelf replaces the @me shorthand in the user scope with the login
+// of the authenticated user
+func ResolveSelf(cfg *config.Config, ghClient gh.GitHubClient) error {
 	if cfg == nil {
 		return fmt.Errorf("configuration is required")
 	}
 
-	scopeCount := 0
-	scopes := []string{}
+	if cfg.User != SelfAlias {
+		return nil
+	}
 
-	if cfg.Org != "" {
-		scopeCount++
-		scopes = append(scopes, "org")
+	if ghClient == nil {
+		return fmt.Errorf("GitHub client is required")
 	}
-	if len(cfg.Team) > 0 {
-		scopeCount++
-		scopes = append(scopes, "team")
+
+	login, err := ghClient.CurrentUser()
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", SelfAlias, err)
 	}
-	if cfg.User != "" {
-		scopeCount++
-		scopes = append(scopes, "user")
+	cfg.User = login
+
+	return nil
+}
+
+// ValidateScope validates that exactly one scope is specified in the configuration
+func ValidateScope(cfg *config.Config) error {
+	if cfg == nil {
+		return fmt.Errorf("configuration is required")
 	}
-	if cfg.Repo != "" {
-		scopeCount++
-		scopes = append(scopes, "repo")
+
+	scopes := cfg.Scopes()
+	scopeCount := len(scopes)
+
+	if len(cfg.Hosts) > 0 {
+		if err := validateHosts(cfg.Hosts); err != nil {
+			return err
+		}
 	}
 
-	if scopeCount == 0 {
+	// Host profiles can supply every scope
+	if scopeCount == 0 && len(cfg.Hosts) == 0 {
 		return fmt.Errorf("no scope specified: exactly one of org, team, user, or repo must be provided")
 	}
 
@@ -2,7 +2,9 @@ package scope
 
 import (
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v55/github"
 	"github.com/willis7/prtool/internal/config"
@@ -205,6 +207,30 @@ func TestValidateScope(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "host profiles without a scope",
+			cfg: &config.Config{
+				Hosts: []config.HostProfile{{Name: "ghe", Orgs: []string{"corp"}}},
+			},
+			expectError: false,
+		},
+		{
+			name: "host profile without orgs or repos",
+			cfg: &config.Config{
+				Org:   "test-org",
+				Hosts: []config.HostProfile{{Name: "ghe"}},
+			},
+			expectError: true,
+			errorMsg:    "host 'ghe' needs at least one org or repo",
+		},
+		{
+			name: "duplicate host names",
+			cfg: &config.Config{
+				Hosts: []config.HostProfile{{Name: "ghe", Orgs: []string{"a"}}, {Name: "ghe", Repos: []string{"b/c"}}},
+			},
+			expectError: true,
+			errorMsg:    "duplicate host name 'ghe'",
+		},
 		{
 			name: "valid repo scope",
 			cfg: &config.Config{
@@ -320,6 +346,101 @@ func TestResolveRepos_Integration(t *testing.T) {
 	}
 }
 
+func TestResolveRepositories_TopicFilter(t *testing.T) {
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{
+		{FullName: github.String("test-org/api"), Topics: []string{"backend", "go"}},
+		{FullName: github.String("test-org/web"), Topics: []string{"frontend"}, Language: github.String("TypeScript"), Description: github.String("Customer dashboard")},
+		{FullName: github.String("test-org/worker"), Topics: []string{"backend"}},
+		{FullName: github.String("test-org/docs")},
+	})
+
+	t.Run("filters to a single topic", func(t *testing.T) {
+		cfg := &config.Config{Org: "test-org", Topic: "Frontend"}
+
+		repos, err := ResolveRepositories(cfg, mockClient)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(repos) != 1 || repos[0].Name != "test-org/web" {
+			t.Fatalf("Expected only test-org/web, got %+v", repos)
+		}
+		if len(repos[0].Topics) != 1 || repos[0].Topics[0] != "frontend" {
+			t.Errorf("Expected topics to be carried through, got %v", repos[0].Topics)
+		}
+		if repos[0].Language != "TypeScript" {
+			t.Errorf("Expected language TypeScript to be carried through, got %q", repos[0].Language)
+		}
+		if repos[0].Description != "Customer dashboard" {
+			t.Errorf("Expected the description to be carried through, got %q", repos[0].Description)
+		}
+	})
+
+	t.Run("no topic keeps every repository", func(t *testing.T) {
+		cfg := &config.Config{Org: "test-org"}
+
+		repos, err := ResolveRepositories(cfg, mockClient)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(repos) != 4 {
+			t.Errorf("Expected 4 repositories, got %d", len(repos))
+		}
+	})
+
+	t.Run("unknown topic is an error", func(t *testing.T) {
+		cfg := &config.Config{Org: "test-org", Topic: "mobile"}
+
+		_, err := ResolveRepositories(cfg, mockClient)
+		if err == nil {
+			t.Fatal("Expected error for a topic with no repositories")
+		}
+		if !containsSubstring(err.Error(), "topic 'mobile'") {
+			t.Errorf("Expected error to mention the topic, got %q", err.Error())
+		}
+	})
+}
+
+func TestResolveRepositories_ScanOrder(t *testing.T) {
+	day := func(d int) *github.Timestamp {
+		return &github.Timestamp{Time: time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC)}
+	}
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{
+		{FullName: github.String("test-org/web"), PushedAt: day(2), StargazersCount: github.Int(50)},
+		{FullName: github.String("test-org/api"), PushedAt: day(9), StargazersCount: github.Int(5)},
+		{FullName: github.String("test-org/docs"), StargazersCount: github.Int(10)},
+		{FullName: github.String("test-org/worker"), PushedAt: day(5)},
+	})
+
+	tests := []struct {
+		scanOrder string
+		repoLimit int
+		expected  []string
+	}{
+		{scanOrder: "", expected: []string{"test-org/web", "test-org/api", "test-org/docs", "test-org/worker"}},
+		{scanOrder: ScanOrderPushed, expected: []string{"test-org/api", "test-org/worker", "test-org/web", "test-org/docs"}},
+		{scanOrder: ScanOrderPushed, repoLimit: 2, expected: []string{"test-org/api", "test-org/worker"}},
+		{scanOrder: ScanOrderAlpha, expected: []string{"test-org/api", "test-org/docs", "test-org/web", "test-org/worker"}},
+		{scanOrder: ScanOrderStars, expected: []string{"test-org/web", "test-org/docs", "test-org/api", "test-org/worker"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q limit %d", tt.scanOrder, tt.repoLimit), func(t *testing.T) {
+			cfg := &config.Config{Org: "test-org", ScanOrder: tt.scanOrder, RepoLimit: tt.repoLimit}
+
+			repos, err := ResolveRepos(cfg, mockClient)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if strings.Join(repos, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, repos)
+			}
+		})
+	}
+}
+
 func TestResolveRepos_ErrorHandling(t *testing.T) {
 	// Test various error scenarios
 	testCases := []struct {
@@ -387,3 +508,56 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestResolveSelf(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          *config.Config
+		mockLogin    string
+		authError    error
+		expectedUser string
+		expectError  bool
+	}{
+		{
+			name:         "@me is replaced with the authenticated login",
+			cfg:          &config.Config{User: "@me"},
+			mockLogin:    "octocat",
+			expectedUser: "octocat",
+		},
+		{
+			name:         "explicit user is left unchanged",
+			cfg:          &config.Config{User: "hubot"},
+			mockLogin:    "octocat",
+			expectedUser: "hubot",
+		},
+		{
+			name:        "lookup failure is returned",
+			cfg:         &config.Config{User: "@me"},
+			authError:   fmt.Errorf("bad credentials"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := gh.NewMockClient()
+			mockClient.SetMockLogin(tt.mockLogin)
+			mockClient.SetAuthError(tt.authError)
+
+			err := ResolveSelf(tt.cfg, mockClient)
+
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.cfg.User != tt.expectedUser {
+				t.Errorf("Expected user %q, got %q", tt.expectedUser, tt.cfg.User)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"regexp"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// pseudonymPrefix starts every pseudonym, e.g. "Contributor A"
+const pseudonymPrefix = "Contributor "
+
+// Anonymize replaces each distinct author login with a pseudonym such as
+// "Contributor A", assigned in order of first appearance so the same login
+// always gets the same pseudonym within a run. @mentions of the authors in
+// titles and descriptions are replaced too, and commit emails are cleared.
+// With stripURLs the PR links are removed as well. The PRs are modified in
+// place.
+func Anonymize(prs []*model.PR, stripURLs bool) {
+	pseudonyms := make(map[string]string)
+	for _, pr := range prs {
+		if pr.Author == "" {
+			continue
+		}
+		if _, ok := pseudonyms[pr.Author]; !ok {
+			pseudonyms[pr.Author] = pseudonymPrefix + pseudonymSuffix(len(pseudonyms))
+		}
+	}
+
+	mentions := make(map[*regexp.Regexp]string, len(pseudonyms))
+	for login, pseudonym := range pseudonyms {
+		// Logins are case-insensitive and may contain hyphens, so @alice
+		// must not match the start of @alice-bot
+		mentions[regexp.MustCompile(`(?i)@`+regexp.QuoteMeta(login)+`([^\w-]|$)`)] = pseudonym + "${1}"
+	}
+
+	for _, pr := range prs {
+		if pseudonym, ok := pseudonyms[pr.Author]; ok {
+			pr.Author = pseudonym
+		}
+		for mention, pseudonym := range mentions {
+			pr.Title = mention.ReplaceAllString(pr.Title, pseudonym)
+			pr.Body = mention.ReplaceAllString(pr.Body, pseudonym)
+		}
+		pr.AuthorEmail = ""
+		if stripURLs {
+			pr.HTMLURL = ""
+		}
+	}
+}
+
+// pseudonymSuffix returns the letters for the nth pseudonym (0-based):
+// A to Z, then AA, AB and so on
+func pseudonymSuffix(n int) string {
+	suffix := ""
+	for n++; n > 0; n = (n - 1) / 26 {
+		suffix = string(rune('A'+(n-1)%26)) + suffix
+	}
+	return suffix
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+func TestAnonymize(t *testing.T) {
+	prs := []*model.PR{
+		{Number: 1, Author: "alice", Title: "Add retries", HTMLURL: "https://github.com/acme/api/pull/1", AuthorEmail: "alice@example.com"},
+		{Number: 2, Author: "bob", Title: "Fix login", Body: "Follow-up to @Alice's change, cc @alice-bot and @bob."},
+		{Number: 3, Author: "alice", Title: "Tidy up"},
+		{Number: 4, Author: "carol", Title: "Docs"},
+	}
+
+	Anonymize(prs, false)
+
+	expectedAuthors := []string{"Contributor A", "Contributor B", "Contributor A", "Contributor C"}
+	for i, pr := range prs {
+		if pr.Author != expectedAuthors[i] {
+			t.Errorf("PR %d: expected author %q, got %q", pr.Number, expectedAuthors[i], pr.Author)
+		}
+	}
+	if expected := "Follow-up to Contributor A's change, cc @alice-bot and Contributor B."; prs[1].Body != expected {
+		t.Errorf("Expected mentions replaced, got %q", prs[1].Body)
+	}
+	if prs[0].AuthorEmail != "" {
+		t.Errorf("Expected the commit email to be cleared, got %q", prs[0].AuthorEmail)
+	}
+	if prs[0].HTMLURL == "" {
+		t.Error("Expected URLs to be kept without stripURLs")
+	}
+
+	// The same logins map to the same pseudonyms in every run
+	again := []*model.PR{{Author: "alice"}, {Author: "bob"}, {Author: "alice"}}
+	Anonymize(again, true)
+	if again[0].Author != "Contributor A" || again[1].Author != "Contributor B" || again[2].Author != "Contributor A" {
+		t.Errorf("Expected a stable mapping, got %q, %q, %q", again[0].Author, again[1].Author, again[2].Author)
+	}
+
+	urls := []*model.PR{{Author: "alice", HTMLURL: "https://github.com/acme/api/pull/1"}}
+	Anonymize(urls, true)
+	if urls[0].HTMLURL != "" {
+		t.Errorf("Expected the URL to be stripped, got %q", urls[0].HTMLURL)
+	}
+}
+
+func TestPseudonymSuffix(t *testing.T) {
+	for n, expected := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := pseudonymSuffix(n); got != expected {
+			t.Errorf("pseudonymSuffix(%d) = %q, expected %q", n, got, expected)
+		}
+	}
+}
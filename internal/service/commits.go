@@ -0,0 +1,40 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+)
+
+// authorEmail returns the commit email of the PR author: the git author email
+// of the first commit made by the author's account, else of the first commit.
+// It is empty when the commits carry no email.
+func authorEmail(author string, commits []*github.RepositoryCommit) string {
+	for _, commit := range commits {
+		if strings.EqualFold(commit.GetAuthor().GetLogin(), author) {
+			if email := commit.GetCommit().GetAuthor().GetEmail(); email != "" {
+				return email
+			}
+		}
+	}
+	if len(commits) > 0 {
+		return commits[0].GetCommit().GetAuthor().GetEmail()
+	}
+	return ""
+}
+
+// matchesDomain reports whether email belongs to one of the domains or their
+// subdomains, ignoring case. An empty email never matches.
+func matchesDomain(email string, domains []string) bool {
+	_, host, ok := strings.Cut(strings.ToLower(email), "@")
+	if !ok || host == "" {
+		return false
+	}
+	for _, domain := range domains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
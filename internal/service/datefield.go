@@ -0,0 +1,48 @@
+package service
+
+import (
+	"time"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// PR timestamps the since window can filter on. Merged and created report
+// merged PRs only, while closed reports every PR closed in the window,
+// including those closed without merging.
+const (
+	DateFieldMerged  = "merged"
+	DateFieldClosed  = "closed"
+	DateFieldCreated = "created"
+)
+
+// DateFields lists the supported --date-field values
+var DateFields = []string{DateFieldMerged, DateFieldClosed, DateFieldCreated}
+
+// IsValidDateField reports whether field is a supported date field. Empty
+// means the default, merged.
+func IsValidDateField(field string) bool {
+	if field == "" {
+		return true
+	}
+	for _, f := range DateFields {
+		if field == f {
+			return true
+		}
+	}
+	return false
+}
+
+// prDate returns the PR timestamp named by field, or nil when it is unset
+func prDate(pr *model.PR, field string) *time.Time {
+	switch field {
+	case DateFieldClosed:
+		return pr.ClosedAt
+	case DateFieldCreated:
+		if pr.CreatedAt.IsZero() {
+			return nil
+		}
+		return &pr.CreatedAt
+	default:
+		return pr.MergedAt
+	}
+}
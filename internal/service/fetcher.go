@@ -1,71 +1,374 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/willis7/prtool/internal/config"
 	"github.com/willis7/prtool/internal/gh"
+	"github.com/willis7/prtool/internal/llm"
+	"github.com/willis7/prtool/internal/logger"
 	"github.com/willis7/prtool/internal/model"
 	"github.com/willis7/prtool/internal/scope"
 	"github.com/willis7/prtool/internal/timeutil"
 )
 
+// SinceLatestRelease is the since value that bounds each repository's window
+// by the publish date of its latest release
+const SinceLatestRelease = "latest-release"
+
+// DefaultConcurrency is the number of repositories fetched at once when the
+// config does not set one
+const DefaultConcurrency = 8
+
+// ErrScanDeclined is returned by Fetch when the confirmation callback
+// declines to scan the resolved repositories
+var ErrScanDeclined = errors.New("scan declined")
+
 // Fetcher handles fetching PRs from GitHub
 type Fetcher struct {
 	ghClient gh.GitHubClient
+	log      *logger.Logger
+	ctx      context.Context
+	scanned  []string
+	confirm  func(repoCount int) bool
+	since    *time.Time
+	until    *time.Time
+	// hosts maps HostProfile names to their clients
+	hosts map[string]gh.GitHubClient
 }
 
 // NewFetcher creates a new PR fetcher
 func NewFetcher(ghClient gh.GitHubClient) *Fetcher {
 	return &Fetcher{
 		ghClient: ghClient,
+		ctx:      context.Background(),
 	}
 }
 
+// SetLogger sets the logger used to report fetch diagnostics
+func (f *Fetcher) SetLogger(log *logger.Logger) {
+	f.log = log
+}
+
+// SetContext sets the context that can interrupt a fetch between repositories
+func (f *Fetcher) SetContext(ctx context.Context) {
+	f.ctx = ctx
+}
+
+// SetHostClients sets the clients used for the configured host profiles,
+// keyed by host name
+func (f *Fetcher) SetHostClients(clients map[string]gh.GitHubClient) {
+	f.hosts = clients
+}
+
 // Fetch retrieves merged PRs from GitHub based on configuration
-// It resolves the repository scope, applies the since filter, and returns only merged PRs
+// It resolves the repository scope, applies the since filter, and returns only merged PRs,
+// or all closed PRs when filtering on the closed date.
+// If the fetcher's context is cancelled part way through, Fetch returns the PRs
+// fetched so far together with an error wrapping the context error.
 func (f *Fetcher) Fetch(cfg *config.Config) ([]*model.PR, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("configuration is required")
 	}
 
+	if len(cfg.Hosts) > 0 {
+		return f.fetchHosts(cfg)
+	}
+
 	if f.ghClient == nil {
 		return nil, fmt.Errorf("GitHub client is required")
 	}
 
-	// Parse the since filter
-	var sinceTime time.Time
-	if cfg.Since != "" {
-		parsed, err := timeutil.ParseRelativeDuration(cfg.Since)
-		if err != nil {
-			return nil, fmt.Errorf("invalid since filter '%s': %w", cfg.Since, err)
-		}
-		sinceTime = parsed
-	} else {
-		// Default to 7 days ago if no since filter is specified
-		sinceTime = time.Now().AddDate(0, 0, -7)
+	sinceTime, perRepoRelease, err := parseSince(cfg)
+	if err != nil {
+		return nil, err
+	}
+	until, err := parseUntil(cfg, sinceTime, perRepoRelease)
+	if err != nil {
+		return nil, err
+	}
+	f.since, f.until = nil, nil
+
+	grep, err := newGrepMatcher(cfg.Grep, cfg.GrepRegex)
+	if err != nil {
+		return nil, err
 	}
 
 	// Resolve repositories based on scope
-	repoNames, err := scope.ResolveRepos(cfg, f.ghClient)
+	repos, err := scope.ResolveRepositories(cfg, f.ghClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve repositories: %w", err)
 	}
 
-	// Fetch PRs from all repositories
+	// ResolveRepositories returns the canonical names reported by the API, so a renamed
+	// or transferred repository is fetched under its current name
+	if cfg.Repo != "" && len(repos) == 1 && !strings.EqualFold(repos[0].Name, cfg.Repo) {
+		f.log.Info("Repository %s has been renamed to %s", cfg.Repo, repos[0].Name)
+	}
+
+	if f.confirm != nil && !f.confirm(len(repos)) {
+		return nil, ErrScanDeclined
+	}
+
+	// Fetch PRs from all repositories, up to cfg.Concurrency at a time. The
+	// first error skips repositories that have not started and stops the
+	// others before their next per-PR call. GitHubClient calls take no
+	// context, so a call already in flight runs to completion.
+	f.scanned = nil
+	concurrency := cfg.Concurrency
+	if concurrency < 1 {
+		concurrency = DefaultConcurrency
+	}
+	ctx, cancel := context.WithCancel(f.ctx)
+	defer cancel()
+
+	results := make([]*repoResult, len(repos))
+	var (
+		mu       sync.Mutex
+		firstErr error
+		done     int
+	)
+	progress := f.log.NewProgressBar("Fetching repo", len(repos))
+	defer progress.Done()
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, repo scope.Repository) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return
+			}
+
+			result, err := f.fetchRepo(ctx, cfg, repo, sinceTime, until, perRepoRelease, grep)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			results[i] = result
+			done++
+			progress.Update(done, repo.Name)
+		}(i, repo)
+	}
+	wg.Wait()
+
 	var allPRs []*model.PR
-	for _, repoName := range repoNames {
-		prs, err := f.ghClient.ListPRs(repoName, sinceTime)
+	windowStart := sinceTime
+	if perRepoRelease {
+		windowStart = time.Time{}
+	}
+	for i, result := range results {
+		if result == nil {
+			continue
+		}
+		f.scanned = append(f.scanned, repos[i].Name)
+		allPRs = append(allPRs, result.prs...)
+		if perRepoRelease && (windowStart.IsZero() || result.since.Before(windowStart)) {
+			windowStart = result.since
+		}
+	}
+	// Record the window actually used; per-repo release windows start at the
+	// earliest release bound
+	if !windowStart.IsZero() {
+		since, end := windowStart.UTC().Truncate(time.Second), until.UTC().Truncate(time.Second)
+		f.since, f.until = &since, &end
+	}
+
+	// Sort by repository, then PR number, so the output does not depend on
+	// which calls finished first or the order GitHub lists PRs in
+	sort.SliceStable(allPRs, func(i, j int) bool {
+		if allPRs[i].Repository != allPRs[j].Repository {
+			return allPRs[i].Repository < allPRs[j].Repository
+		}
+		return allPRs[i].Number < allPRs[j].Number
+	})
+
+	if err := f.ctx.Err(); err != nil {
+		return allPRs, fmt.Errorf("fetch interrupted: %w", err)
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	markReverted(allPRs)
+
+	return allPRs, nil
+}
+
+// repoResult is what fetching one repository produced: its PRs and the start
+// of its window
+type repoResult struct {
+	prs   []*model.PR
+	since time.Time
+}
+
+// fetchRepo lists and filters the merged PRs of one repository, or its closed
+// PRs with the closed date field. It is called
+// concurrently for different repositories. Once ctx is cancelled it gives up
+// with ctx's error rather than make more per-PR calls.
+func (f *Fetcher) fetchRepo(ctx context.Context, cfg *config.Config, repo scope.Repository, sinceTime, until time.Time, perRepoRelease bool, grep *grepMatcher) (*repoResult, error) {
+	repoName := repo.Name
+	repoSince := sinceTime
+	if perRepoRelease {
+		var err error
+		repoSince, err = f.releaseSince(repoName, sinceTime)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch PRs from repository '%s': %w", repoName, err)
+			return nil, err
+		}
+	}
+
+	// The GitHub client filters on merge date, or on close date for the closed
+	// date field, which also reports PRs closed without merging
+	list := f.ghClient.ListPRs
+	if cfg.DateField == DateFieldClosed {
+		list = f.ghClient.ListClosedPRs
+	}
+	prs, err := list(repoName, repoSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch PRs from repository '%s': %w", repoName, err)
+	}
+
+	result := &repoResult{since: repoSince}
+
+	// The GitHub client already filters by merge or close date. Only merged PRs
+	// (MergedAt != nil and State == "closed") are kept, or all closed PRs for
+	// the closed date field, and other date fields and the until bound are
+	// checked against the window here.
+	perPRCalls := cfg.WeightBy == llm.WeightBySize || cfg.MinChanges > 0 || cfg.IncludeCommits || cfg.IncludeFiles
+	for _, pr := range prs {
+		if err := ctx.Err(); perPRCalls && err != nil {
+			return nil, err
 		}
+		if pr.State == "closed" && (pr.MergedAt != nil || cfg.DateField == DateFieldClosed) {
+			if cfg.DateField != "" && cfg.DateField != DateFieldMerged {
+				if date := prDate(pr, cfg.DateField); date == nil || !date.After(repoSince) {
+					continue
+				}
+			}
+			if date := prDate(pr, cfg.DateField); date != nil && date.After(until) {
+				continue
+			}
+			if pr.IsDraft && !cfg.IncludeDrafts {
+				continue
+			}
+			pr.Labels = normalizeLabels(pr.Labels, cfg.LabelAliases)
+			if !matchesLabels(pr.Labels, cfg.LabelsAny, cfg.LabelsAll, cfg.ExcludeLabels) {
+				continue
+			}
+			if cfg.StripPRTemplate {
+				pr.Body = stripTemplate(pr.Body)
+			}
+			if !grep.matches(pr) {
+				continue
+			}
+			if cfg.WeightBy == llm.WeightBySize || cfg.MinChanges > 0 {
+				// PR listings carry no line counts
+				additions, deletions, err := f.ghClient.GetPRStats(repoName, pr.Number)
+				if err != nil {
+					return nil, fmt.Errorf("failed to fetch size of %s#%d: %w", repoName, pr.Number, err)
+				}
+				pr.Additions, pr.Deletions = additions, deletions
+				if pr.Additions+pr.Deletions < cfg.MinChanges {
+					continue
+				}
+			}
+			if cfg.IncludeCommits {
+				commits, err := f.ghClient.ListPRCommits(repoName, pr.Number)
+				if err != nil {
+					return nil, fmt.Errorf("failed to fetch commits of %s#%d: %w", repoName, pr.Number, err)
+				}
+				pr.AuthorEmail = authorEmail(pr.Author, commits)
+				if len(cfg.AuthorDomains) > 0 && !matchesDomain(pr.AuthorEmail, cfg.AuthorDomains) {
+					continue
+				}
+			}
+			if cfg.IncludeFiles {
+				files, err := f.ghClient.ListPRFiles(repoName, pr.Number)
+				if err != nil {
+					return nil, fmt.Errorf("failed to fetch files of %s#%d: %w", repoName, pr.Number, err)
+				}
+				pr.FilePaths = files
+				if !matchesPaths(files, cfg.Paths, cfg.ExcludePaths) {
+					continue
+				}
+			}
+			pr.RepoTopics = repo.Topics
+			pr.RepoLanguage = repo.Language
+			pr.RepoDescription = repo.Description
+			pr.Scope = repo.Scope
+			result.prs = append(result.prs, pr)
+		}
+	}
+	return result, nil
+}
 
-		// The GitHub client already filters by since date
-		// We only need to filter for merged PRs (MergedAt != nil and State == "closed")
+// fetchHosts fetches the configured scope, if any, with the fetcher's client,
+// then each host profile's orgs and repos with that host's client, labelling
+// the host's PRs with its name. The window is that of the earliest scope.
+func (f *Fetcher) fetchHosts(cfg *config.Config) ([]*model.PR, error) {
+	var allPRs []*model.PR
+	var scanned []string
+	f.since, f.until = nil, nil
+	defer func() { f.scanned = scanned }()
+
+	fetchScope := func(client gh.GitHubClient, scopeCfg config.Config, label string) error {
+		scopeCfg.Hosts = nil
+		sub := &Fetcher{ghClient: client, log: f.log, ctx: f.ctx, confirm: f.confirm}
+		prs, err := sub.Fetch(&scopeCfg)
 		for _, pr := range prs {
-			if pr.MergedAt != nil && pr.State == "closed" {
-				allPRs = append(allPRs, pr)
+			if label != "" {
+				pr.Scope = label
+			}
+		}
+		allPRs = append(allPRs, prs...)
+		scanned = append(scanned, sub.scanned...)
+		if since, until := sub.Window(); since != nil && (f.since == nil || since.Before(*f.since)) {
+			f.since, f.until = since, until
+		}
+		return err
+	}
+
+	if len(cfg.Scopes()) > 0 {
+		if err := fetchScope(f.ghClient, *cfg, ""); err != nil {
+			return allPRs, err
+		}
+	}
+
+	for _, host := range cfg.Hosts {
+		client := f.hosts[host.Name]
+		if client == nil {
+			return allPRs, fmt.Errorf("no GitHub client for host '%s'", host.Name)
+		}
+
+		// Host scopes replace the configured scope and its extra repos
+		hostCfg := *cfg
+		hostCfg.Org, hostCfg.Team, hostCfg.User, hostCfg.Repo = "", nil, "", ""
+		hostCfg.ExtraRepos = nil
+		for _, org := range host.Orgs {
+			scopeCfg := hostCfg
+			scopeCfg.Org = org
+			if err := fetchScope(client, scopeCfg, host.Name); err != nil {
+				return allPRs, fmt.Errorf("host %s: %w", host.Name, err)
+			}
+		}
+		for _, repo := range host.Repos {
+			scopeCfg := hostCfg
+			scopeCfg.Repo = repo
+			if err := fetchScope(client, scopeCfg, host.Name); err != nil {
+				return allPRs, fmt.Errorf("host %s: %w", host.Name, err)
 			}
 		}
 	}
@@ -73,6 +376,146 @@ func (f *Fetcher) Fetch(cfg *config.Config) ([]*model.PR, error) {
 	return allPRs, nil
 }
 
+// SetConfirm sets a callback that Fetch calls with the number of resolved
+// repositories before listing any PRs. Returning false aborts the fetch with
+// ErrScanDeclined.
+func (f *Fetcher) SetConfirm(confirm func(repoCount int) bool) {
+	f.confirm = confirm
+}
+
+// Window returns the absolute time window used by the last Fetch, truncated
+// to seconds. With per-repo latest-release windows, since is the earliest
+// bound used. Both are nil until a fetch has started scanning repositories.
+func (f *Fetcher) Window() (since, until *time.Time) {
+	return f.since, f.until
+}
+
+// ScannedRepos returns the names of the repositories whose PRs were listed by
+// the last Fetch, including those without any matching PRs. After an
+// interrupted fetch only the repositories completed so far are returned.
+func (f *Fetcher) ScannedRepos() []string {
+	return f.scanned
+}
+
+// Plan describes the repositories a fetch would scan and the window used for
+// each, without fetching any PRs
+type Plan struct {
+	Repos []PlannedRepo
+}
+
+// PlannedRepo is a repository in a Plan
+type PlannedRepo struct {
+	Name   string
+	Topics []string
+	Since  time.Time
+}
+
+// Plan resolves the repositories and since windows that Fetch would use for
+// cfg. Only repository listing (and release lookups for latest-release) hit
+// the API; no PRs are listed.
+func (f *Fetcher) Plan(cfg *config.Config) (*Plan, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("configuration is required")
+	}
+
+	if f.ghClient == nil {
+		return nil, fmt.Errorf("GitHub client is required")
+	}
+
+	sinceTime, perRepoRelease, err := parseSince(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	repos, err := scope.ResolveRepositories(cfg, f.ghClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve repositories: %w", err)
+	}
+
+	plan := &Plan{}
+	for _, repo := range repos {
+		repoSince := sinceTime
+		if perRepoRelease {
+			repoSince, err = f.releaseSince(repo.Name, sinceTime)
+			if err != nil {
+				return nil, err
+			}
+		}
+		plan.Repos = append(plan.Repos, PlannedRepo{
+			Name:   repo.Name,
+			Topics: repo.Topics,
+			Since:  repoSince,
+		})
+	}
+
+	return plan, nil
+}
+
+// parseSince returns the since bound for cfg and whether each repository's
+// window should instead start at its latest release
+func parseSince(cfg *config.Config) (time.Time, bool, error) {
+	perRepoRelease := cfg.Since == SinceLatestRelease
+	if cfg.Since != "" && !perRepoRelease {
+		parsed, err := timeutil.ParseTime(cfg.Since)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid since filter '%s': %w", cfg.Since, err)
+		}
+		return parsed, false, nil
+	}
+
+	// Default to 7 days ago if no since filter is specified.
+	// This is also the fallback window for repos without releases.
+	return time.Now().AddDate(0, 0, -7), perRepoRelease, nil
+}
+
+// ValidateWindow checks that cfg's since and until values parse and that
+// until comes after since
+func ValidateWindow(cfg *config.Config) error {
+	since, perRepoRelease, err := parseSince(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = parseUntil(cfg, since, perRepoRelease)
+	return err
+}
+
+// parseUntil returns the until bound for cfg, or now when none is set. A
+// fixed since bound must come before it; per-repo release windows that start
+// after it are simply empty.
+func parseUntil(cfg *config.Config, since time.Time, perRepoRelease bool) (time.Time, error) {
+	if cfg.Until == "" {
+		return time.Now(), nil
+	}
+	until, err := timeutil.ParseTime(cfg.Until)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid until filter '%s': %w", cfg.Until, err)
+	}
+	if !perRepoRelease && !until.After(since) {
+		sinceValue := cfg.Since
+		if sinceValue == "" {
+			sinceValue = "-7d"
+		}
+		return time.Time{}, fmt.Errorf("until '%s' (%s) must be after since '%s' (%s)", cfg.Until, until.Format("2006-01-02 15:04"), sinceValue, since.Format("2006-01-02 15:04"))
+	}
+	return until, nil
+}
+
+// releaseSince returns the publish date of the repository's latest release,
+// or fallback when it has none
+func (f *Fetcher) releaseSince(repoName string, fallback time.Time) (time.Time, error) {
+	released, err := f.ghClient.LatestReleaseDate(repoName)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get latest release for repository '%s': %w", repoName, err)
+	}
+	if released == nil {
+		f.log.Info("Repository %s has no releases, using the default window", repoName)
+		return fallback, nil
+	}
+
+	f.log.Info("Using latest release of %s (%s) as since bound", repoName, released.Format("2006-01-02"))
+	return *released, nil
+}
+
 // Fetch is a convenience function that creates a fetcher and fetches PRs
 func Fetch(cfg *config.Config, ghClient gh.GitHubClient) ([]*model.PR, error) {
 	fetcher := NewFetcher(ghClient)
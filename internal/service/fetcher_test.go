@@ -1,14 +1,24 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/go-github/v55/github"
 	"github.com/willis7/prtool/internal/config"
 	"github.com/willis7/prtool/internal/gh"
+	"github.com/willis7/prtool/internal/llm"
+	"github.com/willis7/prtool/internal/logger"
 	"github.com/willis7/prtool/internal/model"
+	"github.com/willis7/prtool/internal/render"
 )
 
 func TestNewFetcher(t *testing.T) {
@@ -450,3 +460,981 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestFetcher_Fetch_RenamedRepository(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{
+		{FullName: github.String("new-org/new-name")},
+	})
+	mockClient.SetMockPRs([]*model.PR{
+		{
+			Title:      "Post-rename PR",
+			MergedAt:   &yesterday,
+			State:      "closed",
+			Repository: "new-org/new-name",
+		},
+	})
+
+	logPath := filepath.Join(t.TempDir(), "fetch.log")
+	log, err := logger.New(true, false, logPath)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	fetcher := NewFetcher(mockClient)
+	fetcher.SetLogger(log)
+
+	prs, err := fetcher.Fetch(&config.Config{Repo: "old-org/old-name"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(prs) != 1 || prs[0].Repository != "new-org/new-name" {
+		t.Fatalf("Expected 1 PR from the renamed repository, got %+v", prs)
+	}
+
+	for _, call := range mockClient.GetCallLog() {
+		if strings.Contains(call, "old-org/old-name") && strings.HasPrefix(call, "ListPRs") {
+			t.Errorf("Expected PRs to be fetched using the canonical name, got call %q", call)
+		}
+	}
+
+	logged, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(logged), "old-org/old-name has been renamed to new-org/new-name") {
+		t.Errorf("Expected rename to be logged, got: %s", logged)
+	}
+}
+
+func TestFetcher_Fetch_SinceLatestRelease(t *testing.T) {
+	now := time.Now()
+	oneDayAgo := now.AddDate(0, 0, -1)
+	threeDaysAgo := now.AddDate(0, 0, -3)
+	fiveDaysAgo := now.AddDate(0, 0, -5)
+	tenDaysAgo := now.AddDate(0, 0, -10)
+
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{
+		{FullName: github.String("test-org/released")},
+		{FullName: github.String("test-org/unreleased")},
+	})
+	mockClient.SetMockRelease("test-org/released", threeDaysAgo)
+	mockClient.SetMockPRs([]*model.PR{
+		{Title: "After release", MergedAt: &oneDayAgo, State: "closed", Repository: "test-org/released"},
+		{Title: "Before release", MergedAt: &fiveDaysAgo, State: "closed", Repository: "test-org/released"},
+		{Title: "Within default window", MergedAt: &fiveDaysAgo, State: "closed", Repository: "test-org/unreleased"},
+		{Title: "Outside default window", MergedAt: &tenDaysAgo, State: "closed", Repository: "test-org/unreleased"},
+	})
+
+	prs, err := Fetch(&config.Config{Org: "test-org", Since: SinceLatestRelease}, mockClient)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var titles []string
+	for _, pr := range prs {
+		titles = append(titles, pr.Title)
+	}
+	got := strings.Join(titles, ", ")
+	if got != "After release, Within default window" {
+		t.Errorf("Expected per-repo windows to select [After release, Within default window], got [%s]", got)
+	}
+
+	expectedCalls := []string{
+		"LatestReleaseDate(test-org/released)",
+		"ListPRs(test-org/released, " + threeDaysAgo.Format("2006-01-02") + ")",
+		"LatestReleaseDate(test-org/unreleased)",
+	}
+	calls := strings.Join(mockClient.GetCallLog(), "\n")
+	for _, expected := range expectedCalls {
+		if !strings.Contains(calls, expected) {
+			t.Errorf("Expected call %q, got:\n%s", expected, calls)
+		}
+	}
+}
+
+func TestFetcher_Fetch_Drafts(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	tests := []struct {
+		name           string
+		includeDrafts  bool
+		expectedTitles string
+	}{
+		{
+			name:           "drafts excluded by default",
+			expectedTitles: "Ready PR",
+		},
+		{
+			name:           "drafts included when requested",
+			includeDrafts:  true,
+			expectedTitles: "Draft PR, Ready PR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := gh.NewMockClient()
+			mockClient.SetMockRepos([]*github.Repository{
+				{FullName: github.String("test-org/repo1")},
+			})
+			mockClient.SetMockPRs([]*model.PR{
+				{Title: "Draft PR", MergedAt: &yesterday, State: "closed", IsDraft: true, Repository: "test-org/repo1"},
+				{Title: "Ready PR", MergedAt: &yesterday, State: "closed", Repository: "test-org/repo1"},
+			})
+
+			prs, err := Fetch(&config.Config{Org: "test-org", IncludeDrafts: tt.includeDrafts}, mockClient)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var titles []string
+			for _, pr := range prs {
+				titles = append(titles, pr.Title)
+			}
+			if got := strings.Join(titles, ", "); got != tt.expectedTitles {
+				t.Errorf("Expected PRs [%s], got [%s]", tt.expectedTitles, got)
+			}
+		})
+	}
+}
+
+func TestFetcher_Fetch_Topic(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{
+		{FullName: github.String("test-org/api"), Topics: []string{"backend"}, Language: github.String("Go"), Description: github.String("Public API")},
+		{FullName: github.String("test-org/web"), Topics: []string{"frontend"}},
+	})
+	mockClient.SetMockPRs([]*model.PR{
+		{Title: "API PR", MergedAt: &yesterday, State: "closed", Repository: "test-org/api"},
+	})
+
+	prs, err := Fetch(&config.Config{Org: "test-org", Topic: "backend"}, mockClient)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, call := range mockClient.GetCallLog() {
+		if strings.Contains(call, "test-org/web") {
+			t.Errorf("Expected repository without the topic to be skipped, got call %q", call)
+		}
+	}
+	if len(prs) != 1 || strings.Join(prs[0].RepoTopics, ",") != "backend" {
+		t.Errorf("Expected PR to carry its repository topics, got %+v", prs)
+	}
+	if len(prs) == 1 && prs[0].RepoLanguage != "Go" {
+		t.Errorf("Expected PR to carry its repository language, got %q", prs[0].RepoLanguage)
+	}
+	if len(prs) == 1 && prs[0].RepoDescription != "Public API" {
+		t.Errorf("Expected PR to carry its repository description, got %q", prs[0].RepoDescription)
+	}
+}
+
+func TestFetcher_ScannedRepos(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{
+		{FullName: github.String("test-org/api")},
+		{FullName: github.String("test-org/quiet")},
+	})
+	mockClient.SetMockPRs([]*model.PR{
+		{Title: "API PR", MergedAt: &yesterday, State: "closed", Repository: "test-org/api"},
+	})
+
+	fetcher := NewFetcher(mockClient)
+	prs, err := fetcher.Fetch(&config.Config{Org: "test-org"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(prs) != 1 {
+		t.Errorf("Expected 1 PR, got %d", len(prs))
+	}
+	if got := strings.Join(fetcher.ScannedRepos(), ","); got != "test-org/api,test-org/quiet" {
+		t.Errorf("Expected both repositories to be reported as scanned, got %s", got)
+	}
+}
+
+func TestFetcher_Fetch_ExtraRepos(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{
+		{FullName: github.String("test-org/public")},
+	})
+	mockClient.SetMockPRs([]*model.PR{
+		{Title: "Public PR", MergedAt: &yesterday, State: "closed", Repository: "test-org/public"},
+		{Title: "Private PR", MergedAt: &yesterday, State: "closed", Repository: "test-org/private"},
+	})
+
+	cfg := &config.Config{
+		Org:        "test-org",
+		ExtraRepos: []string{"test-org/private", "Test-Org/Public"},
+	}
+	prs, err := Fetch(cfg, mockClient)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var titles []string
+	for _, pr := range prs {
+		titles = append(titles, pr.Title)
+	}
+	if got := strings.Join(titles, ", "); got != "Private PR, Public PR" {
+		t.Errorf("Expected PRs from the org and the extra repo, got [%s]", got)
+	}
+
+	listCalls := 0
+	for _, call := range mockClient.GetCallLog() {
+		if strings.HasPrefix(call, "ListPRs(") {
+			listCalls++
+		}
+	}
+	if listCalls != 2 {
+		t.Errorf("Expected duplicate extra repo to be skipped (2 ListPRs calls), got %d", listCalls)
+	}
+}
+
+func TestFetcher_Fetch_RepoLimit(t *testing.T) {
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{
+		{FullName: github.String("test-org/one")},
+		{FullName: github.String("test-org/two")},
+		{FullName: github.String("test-org/three")},
+		{FullName: github.String("test-org/four")},
+	})
+
+	cfg := &config.Config{Org: "test-org", RepoLimit: 2, ExtraRepos: []string{"test-org/private"}}
+	if _, err := Fetch(cfg, mockClient); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var listed []string
+	for _, call := range mockClient.GetCallLog() {
+		if strings.HasPrefix(call, "ListPRs(") {
+			repo, _, _ := strings.Cut(strings.TrimPrefix(call, "ListPRs("), ",")
+			listed = append(listed, repo)
+		}
+	}
+	// Repositories are fetched concurrently, so calls can arrive in any order
+	sort.Strings(listed)
+	if got := strings.Join(listed, ", "); got != "test-org/one, test-org/private, test-org/two" {
+		t.Errorf("Expected the first 2 repos plus the extra repo to be fetched, got [%s]", got)
+	}
+}
+
+// cancellingClient cancels the run context once a number of repositories
+// have been fetched, simulating Ctrl-C mid-fetch
+type cancellingClient struct {
+	*gh.MockClient
+	cancel     context.CancelFunc
+	cancelFrom int
+	calls      int
+}
+
+func (c *cancellingClient) ListPRs(repo string, since time.Time) ([]*model.PR, error) {
+	c.calls++
+	if c.calls == c.cancelFrom {
+		c.cancel()
+	}
+	return c.MockClient.ListPRs(repo, since)
+}
+
+func TestFetcher_Fetch_Interrupted(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{
+		{FullName: github.String("test-org/repo1")},
+		{FullName: github.String("test-org/repo2")},
+		{FullName: github.String("test-org/repo3")},
+	})
+	mockClient.SetMockPRs([]*model.PR{
+		{Title: "PR 1", MergedAt: &yesterday, State: "closed", Repository: "test-org/repo1"},
+		{Title: "PR 2", MergedAt: &yesterday, State: "closed", Repository: "test-org/repo2"},
+		{Title: "PR 3", MergedAt: &yesterday, State: "closed", Repository: "test-org/repo3"},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := &cancellingClient{MockClient: mockClient, cancel: cancel, cancelFrom: 2}
+
+	fetcher := NewFetcher(client)
+	fetcher.SetContext(ctx)
+	// One repository at a time, so cancellation lands between repositories
+	prs, err := fetcher.Fetch(&config.Config{Org: "test-org", Concurrency: 1})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a context.Canceled error, got %v", err)
+	}
+	var titles []string
+	for _, pr := range prs {
+		titles = append(titles, pr.Title)
+	}
+	if got := strings.Join(titles, ", "); got != "PR 1, PR 2" {
+		t.Errorf("Expected the PRs fetched before cancellation, got [%s]", got)
+	}
+	if client.calls != 2 {
+		t.Errorf("Expected fetching to stop after cancellation, got %d ListPRs calls", client.calls)
+	}
+}
+
+// slowClient delays ListPRs, records the most calls in flight at once and
+// fails listing the failRepo repository
+type slowClient struct {
+	*gh.MockClient
+	failRepo string
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (c *slowClient) ListPRs(repo string, since time.Time) ([]*model.PR, error) {
+	c.mu.Lock()
+	c.inFlight++
+	if c.inFlight > c.peak {
+		c.peak = c.inFlight
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	if repo == c.failRepo {
+		return nil, errors.New("secondary rate limit")
+	}
+	return c.MockClient.ListPRs(repo, since)
+}
+
+func TestFetcher_Fetch_Concurrent(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	mockClient := gh.NewMockClient()
+	var repos []*github.Repository
+	var mockPRs []*model.PR
+	for i := 1; i <= 6; i++ {
+		name := fmt.Sprintf("test-org/repo%d", i)
+		repos = append(repos, &github.Repository{FullName: github.String(name)})
+		// Listed most recently updated first, not by number
+		mockPRs = append(mockPRs,
+			&model.PR{Number: 10 + i, Title: fmt.Sprintf("Late %d", i), MergedAt: &yesterday, State: "closed", Repository: name},
+			&model.PR{Number: i, Title: fmt.Sprintf("PR %d", i), MergedAt: &yesterday, State: "closed", Repository: name},
+		)
+	}
+	mockClient.SetMockRepos(repos)
+	mockClient.SetMockPRs(mockPRs)
+
+	t.Run("sorts by repository and number", func(t *testing.T) {
+		client := &slowClient{MockClient: mockClient}
+		fetcher := NewFetcher(client)
+		prs, err := fetcher.Fetch(&config.Config{Org: "test-org", Concurrency: 3})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var titles []string
+		for _, pr := range prs {
+			titles = append(titles, pr.Title)
+		}
+		if got := strings.Join(titles, ", "); got != "PR 1, Late 1, PR 2, Late 2, PR 3, Late 3, PR 4, Late 4, PR 5, Late 5, PR 6, Late 6" {
+			t.Errorf("Expected PRs by repository then number, got [%s]", got)
+		}
+		if got := strings.Join(fetcher.ScannedRepos(), ", "); got != "test-org/repo1, test-org/repo2, test-org/repo3, test-org/repo4, test-org/repo5, test-org/repo6" {
+			t.Errorf("Expected scanned repos in repository order, got [%s]", got)
+		}
+		if client.peak < 2 || client.peak > 3 {
+			t.Errorf("Expected between 2 and 3 repositories fetched at once, got %d", client.peak)
+		}
+	})
+
+	t.Run("first error is returned", func(t *testing.T) {
+		client := &slowClient{MockClient: mockClient, failRepo: "test-org/repo2"}
+		prs, err := NewFetcher(client).Fetch(&config.Config{Org: "test-org", Concurrency: 2})
+		if err == nil || !strings.Contains(err.Error(), "failed to fetch PRs from repository 'test-org/repo2'") {
+			t.Fatalf("Expected the repo2 listing error, got %v", err)
+		}
+		if prs != nil {
+			t.Errorf("Expected no PRs on error, got %d", len(prs))
+		}
+	})
+}
+
+func TestFetcher_Plan(t *testing.T) {
+	threeDaysAgo := time.Now().AddDate(0, 0, -3)
+
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{
+		{FullName: github.String("test-org/repo1")},
+		{FullName: github.String("test-org/repo2")},
+	})
+	mockClient.SetMockRelease("test-org/repo2", threeDaysAgo)
+
+	plan, err := NewFetcher(mockClient).Plan(&config.Config{Org: "test-org", Since: SinceLatestRelease})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(plan.Repos) != 2 || plan.Repos[0].Name != "test-org/repo1" || plan.Repos[1].Name != "test-org/repo2" {
+		t.Fatalf("Expected both repositories in the plan, got %+v", plan.Repos)
+	}
+	if !plan.Repos[1].Since.Equal(threeDaysAgo) {
+		t.Errorf("Expected repo2 window to start at its release, got %v", plan.Repos[1].Since)
+	}
+
+	for _, call := range mockClient.GetCallLog() {
+		if strings.HasPrefix(call, "ListPRs(") {
+			t.Errorf("Expected no PRs to be listed, got call %q", call)
+		}
+	}
+}
+
+func TestFetcher_Fetch_LabelAliases(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{
+		{FullName: github.String("test-org/api")},
+		{FullName: github.String("test-org/web")},
+	})
+	mockClient.SetMockPRs([]*model.PR{
+		{Title: "API fix", MergedAt: &yesterday, State: "closed", Repository: "test-org/api", Labels: []string{"Bug"}},
+		{Title: "Web fix", MergedAt: &yesterday, State: "closed", Repository: "test-org/web", Labels: []string{"type:bug"}},
+	})
+
+	cfg := &config.Config{
+		Org:          "test-org",
+		LabelAliases: map[string]string{"bug": "bug", "type:bug": "bug"},
+	}
+	prs, err := Fetch(cfg, mockClient)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	output := render.RenderWithOptions(render.Metadata{}, prs, render.Options{GroupBy: render.GroupByLabel})
+	if !strings.Contains(output, "### Label: bug (2)") {
+		t.Errorf("Expected aliased labels to collapse into one group, got:\n%s", output)
+	}
+	if strings.Contains(output, "type:bug") || strings.Contains(output, "Label: Bug") {
+		t.Errorf("Expected no un-aliased label groups, got:\n%s", output)
+	}
+}
+
+func TestFetcher_Window(t *testing.T) {
+	mergedAt := time.Now().AddDate(0, 0, -3)
+
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{{FullName: github.String("test-org/api")}})
+	mockClient.SetMockPRs([]*model.PR{
+		{Title: "Recent PR", MergedAt: &mergedAt, State: "closed", Repository: "test-org/api"},
+	})
+
+	fetcher := NewFetcher(mockClient)
+	if since, until := fetcher.Window(); since != nil || until != nil {
+		t.Errorf("Expected no window before fetching, got %v - %v", since, until)
+	}
+
+	before := time.Now().UTC().Truncate(time.Second)
+	prs, err := fetcher.Fetch(&config.Config{Org: "test-org", Since: "-14d"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	after := time.Now().UTC()
+
+	since, until := fetcher.Window()
+	if since == nil || until == nil {
+		t.Fatal("Expected the fetch window to be recorded")
+	}
+	if until.Before(before) || until.After(after) {
+		t.Errorf("Expected until to be the fetch time, got %v", until)
+	}
+	if days := until.Sub(*since).Hours() / 24; days < 13.9 || days > 14.1 {
+		t.Errorf("Expected a 14 day window, got %.2f days", days)
+	}
+	if since.Location() != time.UTC || since.Nanosecond() != 0 {
+		t.Errorf("Expected since to be UTC and truncated to seconds, got %v", since)
+	}
+
+	// Every fetched PR falls inside the reported window
+	for _, pr := range prs {
+		if pr.MergedAt.Before(*since) || pr.MergedAt.After(*until) {
+			t.Errorf("Expected PR merged at %v to be inside %v - %v", pr.MergedAt, since, until)
+		}
+	}
+}
+
+func TestFetcher_PathFilters(t *testing.T) {
+	mergedAt := time.Now().AddDate(0, 0, -1)
+	newPR := func(number int, files ...string) *model.PR {
+		return &model.PR{Number: number, Title: fmt.Sprintf("PR %d", number), MergedAt: &mergedAt, State: "closed", Repository: "test-org/api", FilePaths: files}
+	}
+
+	tests := []struct {
+		name     string
+		paths    []string
+		exclude  []string
+		expected []int
+	}{
+		{name: "no filters", expected: []int{1, 2, 3, 4}},
+		{name: "include", paths: []string{"internal/payments/**"}, expected: []int{1, 3}},
+		{name: "include and exclude", paths: []string{"internal/payments/**"}, exclude: []string{"**/*_test.go"}, expected: []int{1}},
+		{name: "exclude only", exclude: []string{"docs/**"}, expected: []int{1, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := gh.NewMockClient()
+			mockClient.SetMockRepos([]*github.Repository{{FullName: github.String("test-org/api")}})
+			mockClient.SetMockPRs([]*model.PR{
+				newPR(1, "internal/payments/charge.go"),
+				newPR(2, "docs/payments.md"),
+				newPR(3, "internal/payments/refund/refund_test.go", "internal/payments/refund/refund.go"),
+				newPR(4, "cmd/root.go"),
+			})
+
+			prs, err := NewFetcher(mockClient).Fetch(&config.Config{
+				Org:          "test-org",
+				IncludeFiles: true,
+				Paths:        tt.paths,
+				ExcludePaths: tt.exclude,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var got []int
+			for _, pr := range prs {
+				got = append(got, pr.Number)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("Expected PRs %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFetcher_DateField(t *testing.T) {
+	now := time.Now()
+	daysAgo := func(days int) *time.Time {
+		t := now.AddDate(0, 0, -days)
+		return &t
+	}
+	newPR := func(number int, created, merged, closed *time.Time) *model.PR {
+		return &model.PR{Number: number, CreatedAt: *created, MergedAt: merged, ClosedAt: closed, State: "closed", Repository: "test-org/api"}
+	}
+
+	tests := []struct {
+		field    string
+		expected []int
+	}{
+		{field: "", expected: []int{1, 2}},
+		{field: DateFieldMerged, expected: []int{1, 2}},
+		// Closed also reports PR 4, closed without merging
+		{field: DateFieldClosed, expected: []int{1, 2, 4}},
+		{field: DateFieldCreated, expected: []int{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run("field="+tt.field, func(t *testing.T) {
+			mockClient := gh.NewMockClient()
+			mockClient.SetMockRepos([]*github.Repository{{FullName: github.String("test-org/api")}})
+			mockClient.SetMockPRs([]*model.PR{
+				newPR(1, daysAgo(3), daysAgo(2), daysAgo(2)),
+				newPR(2, daysAgo(30), daysAgo(1), daysAgo(1)),
+				newPR(3, daysAgo(30), daysAgo(10), daysAgo(10)),
+				newPR(4, daysAgo(5), nil, daysAgo(1)),
+				newPR(5, daysAgo(30), nil, daysAgo(10)),
+			})
+
+			prs, err := NewFetcher(mockClient).Fetch(&config.Config{Org: "test-org", Since: "-7d", DateField: tt.field})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			// Every field lists from the since bound, so paging can stop early
+			for _, call := range mockClient.GetCallLog() {
+				if strings.HasPrefix(call, "List") && strings.Contains(call, "0001-01-01") {
+					t.Errorf("Expected PRs to be listed from the since bound, got %q", call)
+				}
+			}
+
+			var got []int
+			for _, pr := range prs {
+				got = append(got, pr.Number)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("Expected PRs %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFetcher_Until(t *testing.T) {
+	now := time.Now()
+	daysAgo := func(days int) *time.Time {
+		t := now.AddDate(0, 0, -days)
+		return &t
+	}
+
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{{FullName: github.String("test-org/api")}})
+	mockClient.SetMockPRs([]*model.PR{
+		{Number: 1, MergedAt: daysAgo(2), State: "closed", Repository: "test-org/api"},
+		{Number: 2, MergedAt: daysAgo(9), State: "closed", Repository: "test-org/api"},
+		{Number: 3, MergedAt: daysAgo(12), State: "closed", Repository: "test-org/api"},
+		{Number: 4, MergedAt: daysAgo(20), State: "closed", Repository: "test-org/api"},
+	})
+
+	fetcher := NewFetcher(mockClient)
+	prs, err := fetcher.Fetch(&config.Config{Org: "test-org", Since: "-14d", Until: "-7d"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var got []int
+	for _, pr := range prs {
+		got = append(got, pr.Number)
+	}
+	if fmt.Sprint(got) != "[2 3]" {
+		t.Errorf("Expected the PRs merged between 14 and 7 days ago, got %v", got)
+	}
+	if _, until := fetcher.Window(); until == nil || until.After(now.AddDate(0, 0, -7)) {
+		t.Errorf("Expected the window to end 7 days ago, got %v", until)
+	}
+
+	for _, cfg := range []*config.Config{
+		{Org: "test-org", Since: "-7d", Until: "-14d"},
+		{Org: "test-org", Until: "-8d"},
+	} {
+		_, err := NewFetcher(mockClient).Fetch(cfg)
+		if err == nil || !strings.Contains(err.Error(), "must be after since") {
+			t.Errorf("Expected until %s before since %q to be rejected, got %v", cfg.Until, cfg.Since, err)
+		}
+	}
+
+	if err := ValidateWindow(&config.Config{Until: "next week"}); err == nil || !strings.Contains(err.Error(), "invalid until filter") {
+		t.Errorf("Expected an invalid until to be rejected, got %v", err)
+	}
+}
+
+func TestFetcher_Grep(t *testing.T) {
+	mergedAt := time.Now().AddDate(0, 0, -1)
+	newPR := func(number int, title, body string) *model.PR {
+		return &model.PR{Number: number, Title: title, Body: body, MergedAt: &mergedAt, State: "closed", Repository: "test-org/api"}
+	}
+
+	tests := []struct {
+		name     string
+		terms    []string
+		regex    []string
+		expected []int
+	}{
+		{name: "no filters", expected: []int{1, 2, 3, 4}},
+		{name: "term in title ignoring case", terms: []string{"MIGRATION"}, expected: []int{1, 3}},
+		{name: "any term", terms: []string{"migration", "cache"}, expected: []int{1, 3, 4}},
+		{name: "regex", regex: []string{`^Fix\b`}, expected: []int{2}},
+		{name: "regex in body", regex: []string{`v\d+\.\d+`}, expected: []int{4}},
+		{name: "term or regex", terms: []string{"schema migration"}, regex: []string{`(?i)typo`}, expected: []int{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := gh.NewMockClient()
+			mockClient.SetMockRepos([]*github.Repository{{FullName: github.String("test-org/api")}})
+			mockClient.SetMockPRs([]*model.PR{
+				newPR(1, "Add schema migration for orders", ""),
+				newPR(2, "Fix typo in README", "Small TYPO fix"),
+				newPR(3, "Refactor storage", "Prepares the data Migration."),
+				newPR(4, "Bump redis", "Upgrades the cache to v7.2"),
+			})
+
+			prs, err := NewFetcher(mockClient).Fetch(&config.Config{Org: "test-org", Grep: tt.terms, GrepRegex: tt.regex})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var got []int
+			for _, pr := range prs {
+				got = append(got, pr.Number)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("Expected PRs %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := NewFetcher(gh.NewMockClient()).Fetch(&config.Config{Org: "test-org", GrepRegex: []string{"("}}); err == nil {
+		t.Error("Expected an error for an invalid regex")
+	}
+}
+
+func TestFetcher_LabelFilters(t *testing.T) {
+	mergedAt := time.Now().AddDate(0, 0, -1)
+	newPR := func(number int, labels ...string) *model.PR {
+		return &model.PR{Number: number, Title: fmt.Sprintf("PR %d", number), Labels: labels, MergedAt: &mergedAt, State: "closed", Repository: "test-org/api"}
+	}
+
+	tests := []struct {
+		name     string
+		any      []string
+		all      []string
+		exclude  []string
+		expected []int
+	}{
+		{name: "no filters", expected: []int{1, 2, 3, 4, 5}},
+		{name: "any label", any: []string{"feature", "bug"}, expected: []int{1, 2, 3, 4}},
+		{name: "all labels", all: []string{"feature", "reviewed"}, expected: []int{1, 2}},
+		{name: "labels match ignoring case", all: []string{"Feature", "REVIEWED"}, expected: []int{1, 2}},
+		{name: "any with exclude", any: []string{"feature", "bug"}, exclude: []string{"wip"}, expected: []int{1, 3, 4}},
+		{name: "all with exclude", all: []string{"feature", "reviewed"}, exclude: []string{"wip"}, expected: []int{1}},
+		{name: "any and all", any: []string{"bug", "security"}, all: []string{"reviewed"}, expected: []int{2, 4}},
+		{name: "exclude only", exclude: []string{"wip", "docs"}, expected: []int{1, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := gh.NewMockClient()
+			mockClient.SetMockRepos([]*github.Repository{{FullName: github.String("test-org/api")}})
+			mockClient.SetMockPRs([]*model.PR{
+				newPR(1, "feature", "reviewed"),
+				newPR(2, "feature", "reviewed", "wip", "security"),
+				newPR(3, "feature"),
+				newPR(4, "bug", "reviewed"),
+				newPR(5, "docs"),
+			})
+
+			prs, err := NewFetcher(mockClient).Fetch(&config.Config{Org: "test-org", LabelsAny: tt.any, LabelsAll: tt.all, ExcludeLabels: tt.exclude})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var got []int
+			for _, pr := range prs {
+				got = append(got, pr.Number)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("Expected PRs %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFetcher_MinChanges(t *testing.T) {
+	mergedAt := time.Now().AddDate(0, 0, -1)
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{{FullName: github.String("test-org/api")}})
+	mockClient.SetMockPRs([]*model.PR{
+		{Number: 1, Title: "Fix typo", Additions: 1, Deletions: 1, MergedAt: &mergedAt, State: "closed", Repository: "test-org/api"},
+		{Number: 2, Title: "Add billing service", Additions: 420, Deletions: 35, MergedAt: &mergedAt, State: "closed", Repository: "test-org/api"},
+		{Number: 3, Title: "Bump version", Additions: 5, Deletions: 5, MergedAt: &mergedAt, State: "closed", Repository: "test-org/api"},
+	})
+
+	tests := []struct {
+		minChanges int
+		expected   []int
+	}{
+		{minChanges: 0, expected: []int{1, 2, 3}},
+		{minChanges: 10, expected: []int{2, 3}},
+		{minChanges: 11, expected: []int{2}},
+		{minChanges: 1000, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("min %d", tt.minChanges), func(t *testing.T) {
+			mockClient.ClearCallLog()
+			prs, err := NewFetcher(mockClient).Fetch(&config.Config{Org: "test-org", MinChanges: tt.minChanges})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var got []int
+			for _, pr := range prs {
+				got = append(got, pr.Number)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("Expected PRs %v, got %v", tt.expected, got)
+			}
+
+			// Line counts come from the stats-only fetch, never the files
+			calls := strings.Join(mockClient.GetCallLog(), "\n")
+			if strings.Contains(calls, "GetPR(") || strings.Contains(calls, "ListPRFiles(") {
+				t.Errorf("Expected no GetPR or ListPRFiles calls, got:\n%s", calls)
+			}
+			if tt.minChanges > 0 && strings.Count(calls, "GetPRStats(") != 3 {
+				t.Errorf("Expected one GetPRStats call per PR, got:\n%s", calls)
+			}
+		})
+	}
+}
+
+func TestFetcher_WeightBySizeFetchesStatsOnly(t *testing.T) {
+	mergedAt := time.Now().AddDate(0, 0, -1)
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{{FullName: github.String("test-org/api")}})
+	mockClient.SetMockPRs([]*model.PR{
+		{Number: 1, Title: "Add billing service", Additions: 420, Deletions: 35, FilePaths: []string{"billing.go"}, MergedAt: &mergedAt, State: "closed", Repository: "test-org/api"},
+	})
+
+	prs, err := NewFetcher(mockClient).Fetch(&config.Config{Org: "test-org", WeightBy: llm.WeightBySize, IncludeFiles: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(prs) != 1 || prs[0].Additions != 420 || prs[0].Deletions != 35 {
+		t.Fatalf("Expected the PR with +420/-35, got %+v", prs)
+	}
+
+	calls := strings.Join(mockClient.GetCallLog(), "\n")
+	if !strings.Contains(calls, "GetPRStats(test-org/api, 1)") || strings.Contains(calls, "GetPR(") {
+		t.Errorf("Expected line counts from GetPRStats rather than GetPR, got calls:\n%s", calls)
+	}
+	if n := strings.Count(calls, "ListPRFiles("); n != 1 {
+		t.Errorf("Expected the files to be listed once, got %d calls:\n%s", n, calls)
+	}
+}
+
+func TestFetcher_AuthorDomain(t *testing.T) {
+	mergedAt := time.Now().AddDate(0, 0, -1)
+	commit := func(login, email string) *github.RepositoryCommit {
+		return &github.RepositoryCommit{
+			Author: &github.User{Login: github.String(login)},
+			Commit: &github.Commit{Author: &github.CommitAuthor{Email: github.String(email)}},
+		}
+	}
+
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockRepos([]*github.Repository{{FullName: github.String("test-org/api")}})
+	mockClient.SetMockPRs([]*model.PR{
+		{Number: 1, Author: "alice", MergedAt: &mergedAt, State: "closed", Repository: "test-org/api"},
+		{Number: 2, Author: "bob", MergedAt: &mergedAt, State: "closed", Repository: "test-org/api"},
+		{Number: 3, Author: "carol", MergedAt: &mergedAt, State: "closed", Repository: "test-org/api"},
+		{Number: 4, Author: "dave", MergedAt: &mergedAt, State: "closed", Repository: "test-org/api"},
+	})
+	// A co-author's commit comes first, the author's own email is preferred
+	mockClient.SetMockCommits("test-org/api", 1, []*github.RepositoryCommit{commit("bot", "bot@other.io"), commit("alice", "alice@Example.com")})
+	mockClient.SetMockCommits("test-org/api", 2, []*github.RepositoryCommit{commit("bob", "bob@contractor.io")})
+	mockClient.SetMockCommits("test-org/api", 3, []*github.RepositoryCommit{commit("carol", "carol@eng.example.com")})
+	// Private emails never match
+	mockClient.SetMockCommits("test-org/api", 4, []*github.RepositoryCommit{commit("dave", "123+dave@users.noreply.github.com")})
+
+	prs, err := NewFetcher(mockClient).Fetch(&config.Config{Org: "test-org", IncludeCommits: true, AuthorDomains: []string{"example.com"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, pr := range prs {
+		got = append(got, fmt.Sprintf("%d:%s", pr.Number, pr.AuthorEmail))
+	}
+	if fmt.Sprint(got) != "[1:alice@Example.com 3:carol@eng.example.com]" {
+		t.Errorf("Expected the example.com PRs with their emails, got %v", got)
+	}
+}
+
+func TestFetcher_TeamScopeLabels(t *testing.T) {
+	mergedAt := time.Now().AddDate(0, 0, -1)
+	mockClient := gh.NewMockClient()
+	mockClient.SetMockTeamRepos("acme/backend", []*github.Repository{{FullName: github.String("acme/api")}, {FullName: github.String("shared/lib")}})
+	// A repository shared by both teams keeps the first team's label
+	mockClient.SetMockTeamRepos("globex/platform", []*github.Repository{{FullName: github.String("globex/infra")}, {FullName: github.String("shared/lib")}})
+	mockClient.SetMockPRs([]*model.PR{
+		{Number: 1, MergedAt: &mergedAt, State: "closed", Repository: "acme/api"},
+		{Number: 2, MergedAt: &mergedAt, State: "closed", Repository: "globex/infra"},
+		{Number: 3, MergedAt: &mergedAt, State: "closed", Repository: "shared/lib"},
+	})
+
+	prs, err := NewFetcher(mockClient).Fetch(&config.Config{Team: config.TeamList{"acme/backend", "globex/platform"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, pr := range prs {
+		got = append(got, fmt.Sprintf("%s:%s", pr.Repository, pr.Scope))
+	}
+	if fmt.Sprint(got) != "[acme/api:acme/backend globex/infra:globex/platform shared/lib:acme/backend]" {
+		t.Errorf("Expected PRs labeled by their team, got %v", got)
+	}
+
+	// A single scope leaves PRs unlabeled
+	prs, err = NewFetcher(mockClient).Fetch(&config.Config{Team: config.TeamList{"acme/backend"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, pr := range prs {
+		if pr.Scope != "" {
+			t.Errorf("Expected no scope label with a single team, got %q", pr.Scope)
+		}
+	}
+}
+
+func TestFetcher_Hosts(t *testing.T) {
+	mergedAt := time.Now().AddDate(0, 0, -1)
+
+	public := gh.NewMockClient()
+	public.SetMockRepos([]*github.Repository{{FullName: github.String("acme/api")}})
+	public.SetMockPRs([]*model.PR{{Number: 1, Title: "Public change", MergedAt: &mergedAt, State: "closed", Repository: "acme/api"}})
+
+	enterprise := gh.NewMockClient()
+	enterprise.SetMockRepos([]*github.Repository{{FullName: github.String("corp/billing")}, {FullName: github.String("corp/tools")}})
+	enterprise.SetMockPRs([]*model.PR{
+		{Number: 7, Title: "Internal change", MergedAt: &mergedAt, State: "closed", Repository: "corp/billing"},
+		{Number: 8, Title: "Tooling change", MergedAt: &mergedAt, State: "closed", Repository: "corp/tools"},
+	})
+
+	cfg := &config.Config{Hosts: []config.HostProfile{
+		{Name: "github.com", Repos: []string{"acme/api"}},
+		{Name: "ghe", Orgs: []string{"corp"}},
+	}}
+	fetcher := NewFetcher(nil)
+	fetcher.SetHostClients(map[string]gh.GitHubClient{"github.com": public, "ghe": enterprise})
+
+	prs, err := fetcher.Fetch(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, pr := range prs {
+		got = append(got, fmt.Sprintf("%s#%d:%s", pr.Repository, pr.Number, pr.Scope))
+	}
+	if fmt.Sprint(got) != "[acme/api#1:github.com corp/billing#7:ghe corp/tools#8:ghe]" {
+		t.Errorf("Expected PRs from both hosts labeled by host, got %v", got)
+	}
+	if fmt.Sprint(fetcher.ScannedRepos()) != "[acme/api corp/billing corp/tools]" {
+		t.Errorf("Expected the repos scanned on both hosts, got %v", fetcher.ScannedRepos())
+	}
+	for _, call := range public.GetCallLog() {
+		if strings.Contains(call, "corp") {
+			t.Errorf("Expected the enterprise scopes not to hit the public client, got %q", call)
+		}
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		expected      bool
+	}{
+		{"internal/payments/**", "internal/payments/charge.go", true},
+		{"internal/payments/**", "internal/payments/a/b/c.go", true},
+		{"internal/payments/**", "internal/paymentsx/charge.go", false},
+		{"**/*_test.go", "main_test.go", true},
+		{"**/*_test.go", "a/b/main_test.go", true},
+		{"*.md", "docs/readme.md", false},
+		{"docs/*.md", "docs/readme.md", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchPath(tt.pattern, tt.name); got != tt.expected {
+			t.Errorf("matchPath(%q, %q) = %v, expected %v", tt.pattern, tt.name, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+// ValidateGrepPatterns checks that every pattern is a valid regular expression
+func ValidateGrepPatterns(patterns []string) error {
+	_, err := compileGrepPatterns(patterns)
+	return err
+}
+
+func compileGrepPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid grep regex '%s': %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// grepMatcher keeps PRs whose title or body contains one of the terms
+// (ignoring case) or matches one of the regular expressions
+type grepMatcher struct {
+	terms    []string
+	patterns []*regexp.Regexp
+}
+
+// newGrepMatcher returns a matcher for the terms and patterns, or nil when
+// there are none and every PR is kept
+func newGrepMatcher(terms, patterns []string) (*grepMatcher, error) {
+	if len(terms) == 0 && len(patterns) == 0 {
+		return nil, nil
+	}
+
+	compiled, err := compileGrepPatterns(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	lowered := make([]string, len(terms))
+	for i, term := range terms {
+		lowered[i] = strings.ToLower(term)
+	}
+
+	return &grepMatcher{terms: lowered, patterns: compiled}, nil
+}
+
+// matches reports whether pr passes the filter. A nil matcher keeps every PR.
+func (m *grepMatcher) matches(pr *model.PR) bool {
+	if m == nil {
+		return true
+	}
+
+	title, body := strings.ToLower(pr.Title), strings.ToLower(pr.Body)
+	for _, term := range m.terms {
+		if strings.Contains(title, term) || strings.Contains(body, term) {
+			return true
+		}
+	}
+	for _, re := range m.patterns {
+		if re.MatchString(pr.Title) || re.MatchString(pr.Body) {
+			return true
+		}
+	}
+	return false
+}
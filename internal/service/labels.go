@@ -0,0 +1,62 @@
+package service
+
+import "strings"
+
+// normalizeLabels maps labels to their canonical names using aliases, whose
+// keys match case-insensitively, and drops duplicates created by aliasing.
+// Labels without an alias are kept as-is.
+func normalizeLabels(labels []string, aliases map[string]string) []string {
+	if len(labels) == 0 || len(aliases) == 0 {
+		return labels
+	}
+
+	lookup := make(map[string]string, len(aliases))
+	for alias, canonical := range aliases {
+		lookup[strings.ToLower(alias)] = canonical
+	}
+
+	seen := make(map[string]bool, len(labels))
+	normalized := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if canonical, ok := lookup[strings.ToLower(label)]; ok {
+			label = canonical
+		}
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+
+	return normalized
+}
+
+// matchesLabels reports whether labels has at least one of any (when any is
+// set), every label of all, and none of exclude. Labels match
+// case-insensitively.
+func matchesLabels(labels, any, all, exclude []string) bool {
+	has := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		has[strings.ToLower(label)] = true
+	}
+
+	for _, label := range exclude {
+		if has[strings.ToLower(label)] {
+			return false
+		}
+	}
+	for _, label := range all {
+		if !has[strings.ToLower(label)] {
+			return false
+		}
+	}
+	if len(any) == 0 {
+		return true
+	}
+	for _, label := range any {
+		if has[strings.ToLower(label)] {
+			return true
+		}
+	}
+	return false
+}
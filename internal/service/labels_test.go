@@ -0,0 +1,53 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeLabels(t *testing.T) {
+	aliases := map[string]string{
+		"Bug":      "bug",
+		"type:bug": "bug",
+		"feat":     "feature",
+	}
+
+	tests := []struct {
+		name     string
+		labels   []string
+		aliases  map[string]string
+		expected []string
+	}{
+		{
+			name:     "aliases collapse into one label",
+			labels:   []string{"BUG", "type:bug", "ui"},
+			aliases:  aliases,
+			expected: []string{"bug", "ui"},
+		},
+		{
+			name:     "canonical name already present",
+			labels:   []string{"bug", "Bug"},
+			aliases:  aliases,
+			expected: []string{"bug"},
+		},
+		{
+			name:     "no aliases leaves labels untouched",
+			labels:   []string{"Bug", "type:bug"},
+			expected: []string{"Bug", "type:bug"},
+		},
+		{
+			name:     "no labels",
+			aliases:  aliases,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeLabels(tt.labels, tt.aliases)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ValidatePathPatterns checks that every pattern is a valid path glob
+func ValidatePathPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		for _, segment := range strings.Split(pattern, "/") {
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("invalid path pattern '%s': %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// matchesPaths reports whether a PR with the given changed files passes the
+// path filters: at least one file matches an include pattern (or there are no
+// include patterns) and no file matches an exclude pattern.
+func matchesPaths(files, include, exclude []string) bool {
+	included := len(include) == 0
+	for _, file := range files {
+		if matchAny(exclude, file) {
+			return false
+		}
+		if !included && matchAny(include, file) {
+			included = true
+		}
+	}
+	return included
+}
+
+// matchAny reports whether name matches any of the patterns
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matchPath(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath matches a slash-separated path against a glob pattern. Segments
+// use path.Match syntax, and a "**" segment matches any number of directories,
+// so "internal/payments/**" matches every file below internal/payments.
+func matchPath(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Try every possible number of directories for the wildcard
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+var (
+	// revertTitle matches GitHub's default revert title: Revert "Original title"
+	revertTitle = regexp.MustCompile(`^(?i:revert)\s+"(.+)"$`)
+	// revertPRRef matches PR references such as #123 or owner/repo#123
+	revertPRRef = regexp.MustCompile(`(?:([\w.-]+/[\w.-]+))?#(\d+)\b`)
+	// revertSHA matches abbreviated or full commit SHAs
+	revertSHA = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+)
+
+// isRevert reports whether pr looks like a revert PR
+func isRevert(pr *model.PR) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(pr.Title)), "revert")
+}
+
+// markReverted sets RevertedBy on every PR that a revert PR in prs refers to,
+// by PR number, merge commit SHA or the quoted original title. Only PRs in
+// the same repository and fetch are matched.
+func markReverted(prs []*model.PR) {
+	for _, revert := range prs {
+		if !isRevert(revert) {
+			continue
+		}
+		for _, original := range prs {
+			if original == revert || original.Repository != revert.Repository || original.RevertedBy != 0 {
+				continue
+			}
+			if revertRefersTo(revert, original) {
+				original.RevertedBy = revert.Number
+			}
+		}
+	}
+}
+
+// revertRefersTo reports whether the revert PR refers to original
+func revertRefersTo(revert, original *model.PR) bool {
+	if m := revertTitle.FindStringSubmatch(strings.TrimSpace(revert.Title)); m != nil && m[1] == original.Title {
+		return true
+	}
+
+	for _, m := range revertPRRef.FindAllStringSubmatch(revert.Body, -1) {
+		if m[1] != "" && !strings.EqualFold(m[1], original.Repository) {
+			continue
+		}
+		if n, err := strconv.Atoi(m[2]); err == nil && n == original.Number {
+			return true
+		}
+	}
+
+	if original.MergeCommitSHA != "" {
+		for _, sha := range revertSHA.FindAllString(strings.ToLower(revert.Body), -1) {
+			if strings.HasPrefix(strings.ToLower(original.MergeCommitSHA), sha) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/willis7/prtool/internal/model"
+)
+
+func TestMarkReverted(t *testing.T) {
+	prs := []*model.PR{
+		{Number: 10, Title: "Add caching layer", Repository: "acme/api"},
+		{Number: 11, Title: "Speed up search", Repository: "acme/api", MergeCommitSHA: "4f2a9c81d0e3b7a65c1f0e9d8b7a6c5d4e3f2a1b"},
+		{Number: 12, Title: "Add metrics", Repository: "acme/api"},
+		{Number: 13, Title: "Add caching layer", Repository: "acme/web"},
+		{Number: 20, Title: `Revert "Add caching layer"`, Body: "Reverts acme/api#10\n\nCache invalidation bugs.", Repository: "acme/api"},
+		{Number: 21, Title: "Revert search change", Body: "This reverts commit 4f2a9c81d0.", Repository: "acme/api"},
+		{Number: 22, Title: "Revert metrics", Body: "Reverts other/repo#12", Repository: "acme/api"},
+	}
+
+	markReverted(prs)
+
+	expected := map[int]int{10: 20, 11: 21, 12: 0, 13: 0, 20: 0, 21: 0, 22: 0}
+	for _, pr := range prs {
+		if pr.RevertedBy != expected[pr.Number] {
+			t.Errorf("PR #%d: expected RevertedBy %d, got %d", pr.Number, expected[pr.Number], pr.RevertedBy)
+		}
+	}
+}
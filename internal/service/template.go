@@ -0,0 +1,116 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// htmlCommentRe matches HTML comments, which PR templates use for author instructions
+	htmlCommentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+
+	// headingRe matches a markdown ATX heading and captures its text
+	headingRe = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)[\s#]*$`)
+
+	// checklistRe matches a markdown task list item and captures its text
+	checklistRe = regexp.MustCompile(`^\s*[-*+]\s+\[[ xX]?\]\s*(.*)$`)
+
+	// blankLinesRe matches runs of three or more newlines
+	blankLinesRe = regexp.MustCompile(`\n{3,}`)
+)
+
+// templateHeadings lists common PR template section headings (normalized).
+// The heading itself is dropped, but any author text beneath it is kept.
+var templateHeadings = map[string]bool{
+	"description":                  true,
+	"summary":                      true,
+	"changes":                      true,
+	"motivation and context":       true,
+	"related issue":                true,
+	"related issues":               true,
+	"how has this been tested":     true,
+	"testing":                      true,
+	"screenshots":                  true,
+	"screenshots (if appropriate)": true,
+	"checklist":                    true,
+	"type of change":               true,
+	"types of changes":             true,
+}
+
+// checklistHeadings lists template sections made up entirely of checkboxes.
+// Every task list item beneath these headings is treated as boilerplate.
+var checklistHeadings = map[string]bool{
+	"checklist":        true,
+	"type of change":   true,
+	"types of changes": true,
+}
+
+// templateSection is a heading and the lines that follow it
+type templateSection struct {
+	heading string
+	lines   []string
+}
+
+// stripTemplate removes common PR template boilerplate from a PR body.
+// It is deliberately conservative: HTML comments, empty checkboxes, checklist
+// sections, and known template headings are removed, while any other text the
+// author wrote is preserved.
+func stripTemplate(body string) string {
+	if body == "" {
+		return body
+	}
+
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	body = htmlCommentRe.ReplaceAllString(body, "")
+
+	// Split the body into sections keyed by their heading
+	sections := []templateSection{{}}
+	for _, line := range strings.Split(body, "\n") {
+		if headingRe.MatchString(line) {
+			sections = append(sections, templateSection{heading: line})
+			continue
+		}
+		last := &sections[len(sections)-1]
+		last.lines = append(last.lines, line)
+	}
+
+	var out []string
+	for _, section := range sections {
+		name := normalizeHeading(section.heading)
+
+		var kept []string
+		for _, line := range section.lines {
+			if m := checklistRe.FindStringSubmatch(line); m != nil {
+				// Drop empty checkboxes anywhere, and all checkboxes in checklist sections
+				if strings.TrimSpace(m[1]) == "" || checklistHeadings[name] {
+					continue
+				}
+			}
+			kept = append(kept, line)
+		}
+
+		hasContent := strings.TrimSpace(strings.Join(kept, "\n")) != ""
+
+		// Keep headings the author wrote; drop template headings
+		if section.heading != "" && !templateHeadings[name] {
+			out = append(out, section.heading)
+		}
+		if hasContent {
+			out = append(out, kept...)
+		}
+	}
+
+	result := strings.Join(out, "\n")
+	result = blankLinesRe.ReplaceAllString(result, "\n\n")
+
+	return strings.TrimSpace(result)
+}
+
+// normalizeHeading returns the lowercased heading text without trailing punctuation
+func normalizeHeading(heading string) string {
+	m := headingRe.FindStringSubmatch(heading)
+	if m == nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(m[1]), ":?"))
+}
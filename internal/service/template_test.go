@@ -0,0 +1,130 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/willis7/prtool/internal/config"
+	"github.com/willis7/prtool/internal/gh"
+	"github.com/willis7/prtool/internal/model"
+)
+
+func TestStripTemplate(t *testing.T) {
+	templated := `## Description
+
+<!-- Please include a summary of the change and which issue is fixed. -->
+
+Replaces the legacy retry loop with exponential backoff.
+
+## Type of change
+
+- [ ] Bug fix (non-breaking change which fixes an issue)
+- [x] New feature (non-breaking change which adds functionality)
+
+## Rollout notes
+
+Deploy the worker before the API.
+
+## Checklist
+
+- [x] My code follows the style guidelines of this project
+- [ ] I have added tests that prove my fix is effective
+- [ ]
+`
+
+	tests := []struct {
+		name       string
+		body       string
+		contains   []string
+		notContain []string
+	}{
+		{
+			name: "templated body keeps author text",
+			body: templated,
+			contains: []string{
+				"Replaces the legacy retry loop with exponential backoff.",
+				"## Rollout notes",
+				"Deploy the worker before the API.",
+			},
+			notContain: []string{
+				"## Description",
+				"Please include a summary",
+				"## Type of change",
+				"Bug fix (non-breaking change",
+				"## Checklist",
+				"follows the style guidelines",
+				"- [ ]",
+			},
+		},
+		{
+			name:     "plain body is unchanged",
+			body:     "Fixes the login redirect.",
+			contains: []string{"Fixes the login redirect."},
+		},
+		{
+			name:     "task list outside template sections is kept",
+			body:     "## Follow-ups\n\n- [ ] Remove the feature flag\n- [ ]\n",
+			contains: []string{"## Follow-ups", "- [ ] Remove the feature flag"},
+		},
+		{
+			name: "empty body",
+			body: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := stripTemplate(tt.body)
+
+			for _, want := range tt.contains {
+				if !strings.Contains(result, want) {
+					t.Errorf("Expected result to contain %q, got:\n%s", want, result)
+				}
+			}
+			for _, unwanted := range tt.notContain {
+				if strings.Contains(result, unwanted) {
+					t.Errorf("Expected result not to contain %q, got:\n%s", unwanted, result)
+				}
+			}
+		})
+	}
+}
+
+func TestFetcher_Fetch_StripPRTemplate(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1)
+	body := "## Description\n\nAdds caching.\n\n## Checklist\n\n- [x] Tests added\n"
+
+	for _, strip := range []bool{true, false} {
+		mockClient := gh.NewMockClient()
+		mockClient.SetMockRepos([]*github.Repository{
+			{FullName: github.String("test-org/repo1")},
+		})
+		mockClient.SetMockPRs([]*model.PR{
+			{
+				Title:      "Add caching",
+				Body:       body,
+				MergedAt:   &yesterday,
+				State:      "closed",
+				Repository: "test-org/repo1",
+			},
+		})
+
+		cfg := &config.Config{Org: "test-org", StripPRTemplate: strip}
+		prs, err := Fetch(cfg, mockClient)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(prs) != 1 {
+			t.Fatalf("Expected 1 PR, got %d", len(prs))
+		}
+
+		if strip && prs[0].Body != "Adds caching." {
+			t.Errorf("Expected stripped body %q, got %q", "Adds caching.", prs[0].Body)
+		}
+		if !strip && prs[0].Body != body {
+			t.Errorf("Expected body to be unchanged, got %q", prs[0].Body)
+		}
+	}
+}
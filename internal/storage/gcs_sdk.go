@@ -0,0 +1,32 @@
+//go:build cloud
+
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	gcs "cloud.google.com/go/storage"
+)
+
+// uploadGCS uploads content to a GCS object with the Cloud Storage client,
+// which authenticates with Application Default Credentials (a service account
+// key in GOOGLE_APPLICATION_CREDENTIALS, gcloud credentials or the metadata
+// server). STORAGE_EMULATOR_HOST selects a GCS emulator.
+func uploadGCS(ctx context.Context, bucket, key string, content []byte, contentType string) error {
+	client, err := gcs.NewClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create GCS client: %w", err)
+	}
+	defer client.Close()
+
+	w := client.Bucket(bucket).Object(key).NewWriter(ctx)
+	w.ContentType = contentType
+	// Reports are small, so upload them in a single request
+	w.ChunkSize = 0
+	if _, err := w.Write(content); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
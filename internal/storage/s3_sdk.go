@@ -0,0 +1,41 @@
+//go:build cloud
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// uploadS3 uploads content to an S3 object with the AWS SDK. Credentials and
+// the region come from the SDK's default chain: the environment, shared
+// config and credentials files (profiles, SSO), web identity and IMDS.
+func uploadS3(ctx context.Context, bucket, key string, content []byte, contentType string) error {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		// Custom endpoints such as MinIO (AWS_ENDPOINT_URL_S3) and bucket
+		// names containing dots need path-style addressing
+		o.UsePathStyle = o.BaseEndpoint != nil || strings.Contains(bucket, ".")
+	})
+
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
@@ -0,0 +1,108 @@
+//go:build cloud
+
+package storage
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUpload_S3SDK(t *testing.T) {
+	var gotMethod, gotPath, gotBody, gotAuth, gotType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethod, gotPath, gotBody = r.Method, r.URL.EscapedPath(), string(body)
+		gotAuth = r.Header.Get("Authorization")
+		gotType = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ENDPOINT_URL_S3", server.URL)
+	// Send the body as is rather than with a trailing checksum
+	t.Setenv("AWS_REQUEST_CHECKSUM_CALCULATION", "when_required")
+
+	if err := Upload(context.Background(), "s3://reports/weekly/2024 01.md", []byte("# Report\n")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if gotMethod != http.MethodPut {
+		t.Errorf("Expected PUT, got %s", gotMethod)
+	}
+	if gotPath != "/reports/weekly/2024%2001.md" {
+		t.Errorf("Expected path-style object path, got %s", gotPath)
+	}
+	if gotBody != "# Report\n" {
+		t.Errorf("Expected report body to be uploaded, got %q", gotBody)
+	}
+	if !strings.Contains(gotAuth, "Credential=AKIDTEST/") || !strings.Contains(gotAuth, "/eu-west-1/s3/aws4_request") {
+		t.Errorf("Expected SigV4 authorization for eu-west-1, got %q", gotAuth)
+	}
+	if gotType != "text/markdown; charset=utf-8" {
+		t.Errorf("Expected markdown content type, got %q", gotType)
+	}
+}
+
+func TestUpload_GCSSDK(t *testing.T) {
+	var gotPath, gotName, gotBody, gotType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		// A single-request upload is multipart: the object metadata, then the
+		// content
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			t.Errorf("Expected a multipart upload: %v", err)
+			return
+		}
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		for i := 0; ; i++ {
+			part, err := reader.NextPart()
+			if err != nil {
+				break
+			}
+			data, _ := io.ReadAll(part)
+			if i == 0 {
+				gotName = string(data)
+			} else {
+				gotBody, gotType = string(data), part.Header.Get("Content-Type")
+			}
+		}
+		// The client verifies the stored object's CRC32C against what it sent
+		sum := make([]byte, 4)
+		binary.BigEndian.PutUint32(sum, crc32.Checksum([]byte(gotBody), crc32.MakeTable(crc32.Castagnoli)))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"bucket":"reports","name":"weekly/report.json","crc32c":%q}`, base64.StdEncoding.EncodeToString(sum))
+	}))
+	defer server.Close()
+
+	t.Setenv("STORAGE_EMULATOR_HOST", strings.TrimPrefix(server.URL, "http://"))
+
+	if err := Upload(context.Background(), "gs://reports/weekly/report.json", []byte(`{}`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if gotPath != "/upload/storage/v1/b/reports/o" || !strings.Contains(gotName, `"weekly/report.json"`) {
+		t.Errorf("Expected an upload of weekly/report.json, got %s with metadata %s", gotPath, gotName)
+	}
+	if gotBody != `{}` {
+		t.Errorf("Expected report body to be uploaded, got %q", gotBody)
+	}
+	if gotType != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", gotType)
+	}
+}
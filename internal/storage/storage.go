@@ -0,0 +1,97 @@
+// Package storage uploads rendered reports to cloud object storage.
+//
+// The default build uses the providers' REST APIs directly so no cloud SDK is
+// linked in. It only supports credentials from the environment:
+//
+//   - s3://bucket/key uses AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, the
+//     optional AWS_SESSION_TOKEN and AWS_REGION (or AWS_DEFAULT_REGION,
+//     default us-east-1). AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL selects an
+//     S3-compatible endpoint such as MinIO. Bucket names containing dots use
+//     path-style URLs, since they don't match the wildcard TLS certificate.
+//   - gs://bucket/key uses the raw OAuth access token in
+//     GOOGLE_OAUTH_ACCESS_TOKEN (for example from `gcloud auth
+//     print-access-token`). STORAGE_EMULATOR_HOST selects a GCS emulator.
+//
+// Building with -tags cloud uploads with aws-sdk-go-v2 and
+// cloud.google.com/go/storage instead, which use the SDKs' standard
+// credential chains (profiles, SSO, web identity, instance roles and
+// Application Default Credentials).
+package storage
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Supported URL schemes
+const (
+	SchemeS3  = "s3://"
+	SchemeGCS = "gs://"
+)
+
+// httpClient performs the upload requests
+var httpClient = http.DefaultClient
+
+// IsRemote reports whether target is a cloud storage URL rather than a local path
+func IsRemote(target string) bool {
+	return strings.HasPrefix(target, SchemeS3) || strings.HasPrefix(target, SchemeGCS)
+}
+
+// Upload writes content to the object named by an s3:// or gs:// URL
+func Upload(ctx context.Context, target string, content []byte) error {
+	var scheme string
+	switch {
+	case strings.HasPrefix(target, SchemeS3):
+		scheme = SchemeS3
+	case strings.HasPrefix(target, SchemeGCS):
+		scheme = SchemeGCS
+	default:
+		return fmt.Errorf("unsupported storage URL '%s' (expected s3://bucket/key or gs://bucket/key)", target)
+	}
+
+	bucket, key, err := splitBucketKey(strings.TrimPrefix(target, scheme))
+	if err != nil {
+		return fmt.Errorf("invalid storage URL '%s': %w", target, err)
+	}
+
+	contentType := contentTypeFor(key)
+	if scheme == SchemeS3 {
+		err = uploadS3(ctx, bucket, key, content, contentType)
+	} else {
+		err = uploadGCS(ctx, bucket, key, content, contentType)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", target, err)
+	}
+	return nil
+}
+
+// splitBucketKey splits "bucket/key/parts" into the bucket and object key
+func splitBucketKey(location string) (string, string, error) {
+	bucket, key, ok := strings.Cut(location, "/")
+	if !ok || bucket == "" || key == "" || strings.HasSuffix(key, "/") {
+		return "", "", fmt.Errorf("expected bucket/key")
+	}
+	return bucket, key, nil
+}
+
+// contentTypeFor picks the Content-Type for an object from its extension
+func contentTypeFor(key string) string {
+	switch ext := path.Ext(key); ext {
+	case ".md", ".markdown":
+		return "text/markdown; charset=utf-8"
+	case ".json":
+		return "application/json"
+	case ".jsonl", ".ndjson":
+		return "application/x-ndjson"
+	default:
+		if contentType := mime.TypeByExtension(ext); contentType != "" {
+			return contentType
+		}
+		return "text/plain; charset=utf-8"
+	}
+}
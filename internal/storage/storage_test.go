@@ -0,0 +1,22 @@
+package storage
+
+import "testing"
+
+func TestIsRemote(t *testing.T) {
+	tests := []struct {
+		target   string
+		expected bool
+	}{
+		{"s3://bucket/report.md", true},
+		{"gs://bucket/report.md", true},
+		{"report.md", false},
+		{"/tmp/s3://odd", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRemote(tt.target); got != tt.expected {
+			t.Errorf("IsRemote(%q) = %v, expected %v", tt.target, got, tt.expected)
+		}
+	}
+}
@@ -9,13 +9,51 @@ import (
 )
 
 // ParseRelativeDuration parses relative duration strings like "-7d", "-1m", "-1yr"
-// and returns the corresponding time.Time relative to now.
-// Only negative durations (past times) are allowed.
+// and returns the corresponding time.Time relative to now. "-5bd" counts
+// back business days, skipping Saturdays and Sundays in the local time zone.
+// Only negative durations (past times) are allowed. The phrases "now",
+// "today" and "yesterday" are also accepted; the latter two resolve to
+// midnight in the local time zone (set with TZ).
 func ParseRelativeDuration(r string) (time.Time, error) {
+	return parseRelative(r, time.Now)
+}
+
+// ParseTime parses r as ParseRelativeDuration does, or as an absolute date:
+// "2024-03-01" (midnight in the local time zone) or an RFC 3339 timestamp
+func ParseTime(r string) (time.Time, error) {
+	trimmed := strings.TrimSpace(r)
+	if t, err := time.ParseInLocation(dateLayout, trimmed, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, trimmed); err == nil {
+		return t, nil
+	}
+	t, err := ParseRelativeDuration(r)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w (or an absolute YYYY-MM-DD date or RFC 3339 timestamp)", err)
+	}
+	return t, nil
+}
+
+// dateLayout is the absolute date format accepted by ParseTime
+const dateLayout = "2006-01-02"
+
+// parseRelative implements ParseRelativeDuration, reading the current time
+// from clock once the input has been parsed
+func parseRelative(r string, clock func() time.Time) (time.Time, error) {
 	if r == "" {
 		return time.Time{}, fmt.Errorf("duration string cannot be empty")
 	}
 
+	switch strings.ToLower(strings.TrimSpace(r)) {
+	case "now":
+		return clock(), nil
+	case "today":
+		return startOfDay(clock(), 0), nil
+	case "yesterday":
+		return startOfDay(clock(), -1), nil
+	}
+
 	// Must start with minus sign (only past times allowed)
 	if !strings.HasPrefix(r, "-") {
 		return time.Time{}, fmt.Errorf("duration must be negative (past time): %s", r)
@@ -44,11 +82,13 @@ func ParseRelativeDuration(r string) (time.Time, error) {
 
 	// Parse the unit
 	unit := strings.ToLower(matches[2])
-	now := time.Now()
+	now := clock()
 
 	switch unit {
 	case "d", "day", "days":
 		return now.AddDate(0, 0, -num), nil
+	case "bd":
+		return businessDaysAgo(now, num), nil
 	case "w", "week", "weeks":
 		return now.AddDate(0, 0, -num*7), nil
 	case "m", "month", "months":
@@ -62,6 +102,24 @@ func ParseRelativeDuration(r string) (time.Time, error) {
 	case "s", "sec", "second", "seconds":
 		return now.Add(-time.Duration(num) * time.Second), nil
 	default:
-		return time.Time{}, fmt.Errorf("unsupported time unit: %s (supported: d, w, m, y, h, min, s)", unit)
+		return time.Time{}, fmt.Errorf("unsupported time unit: %s (supported: d, bd, w, m, y, h, min, s)", unit)
+	}
+}
+
+// businessDaysAgo steps back from t one day at a time until it has passed n
+// weekdays, keeping the time of day. Weekdays are taken in t's location.
+func businessDaysAgo(t time.Time, n int) time.Time {
+	for n > 0 {
+		t = t.AddDate(0, 0, -1)
+		if wd := t.Weekday(); wd != time.Saturday && wd != time.Sunday {
+			n--
+		}
 	}
+	return t
+}
+
+// startOfDay returns midnight at the start of the day offset by days from t,
+// in t's location
+func startOfDay(t time.Time, days int) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+days, 0, 0, 0, 0, t.Location())
 }
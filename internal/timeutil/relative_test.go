@@ -303,6 +303,79 @@ func TestParseRelativeDurationUnits(t *testing.T) {
 	}
 }
 
+func TestParseRelativeDuration_Phrases(t *testing.T) {
+	berlin := time.FixedZone("CET", 60*60)
+	now := time.Date(2024, 3, 1, 0, 30, 0, 0, berlin)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{input: "now", expected: now},
+		{input: "today", expected: time.Date(2024, 3, 1, 0, 0, 0, 0, berlin)},
+		// Crosses a month boundary (2024 is a leap year)
+		{input: "yesterday", expected: time.Date(2024, 2, 29, 0, 0, 0, 0, berlin)},
+		{input: "Yesterday", expected: time.Date(2024, 2, 29, 0, 0, 0, 0, berlin)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseRelative(tt.input, func() time.Time { return now })
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Equal(tt.expected) || got.Location() != berlin {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	// Midnight is local: in UTC this is still the previous day
+	got, _ := parseRelative("today", func() time.Time { return now })
+	if utc := got.UTC(); utc.Day() != 29 || utc.Hour() != 23 {
+		t.Errorf("Expected local midnight to be 23:00 UTC the day before, got %v", utc)
+	}
+
+	for _, input := range []string{"tomorrow", "yesterdays", "last week"} {
+		if _, err := ParseRelativeDuration(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	got, err := ParseTime("2024-03-01")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !got.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected local midnight on 2024-03-01, got %v", got)
+	}
+
+	got, err = ParseTime("2024-03-01T12:30:00Z")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !got.Equal(time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected 2024-03-01 12:30 UTC, got %v", got)
+	}
+
+	before := time.Now().AddDate(0, 0, -7)
+	got, err = ParseTime("-7d")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Before(before) || got.After(time.Now().AddDate(0, 0, -7)) {
+		t.Errorf("Expected a relative duration to be 7 days ago, got %v", got)
+	}
+
+	for _, input := range []string{"2024-13-01", "03/01/2024", "next week"} {
+		if _, err := ParseTime(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
+
 // Custom error type for test result checking
 type testError struct {
 	msg string
@@ -311,3 +384,35 @@ type testError struct {
 func (e *testError) Error() string {
 	return e.msg
 }
+
+func TestParseRelativeDuration_BusinessDays(t *testing.T) {
+	berlin := time.FixedZone("CET", 60*60)
+	// Monday 2024-03-04 00:30 in Berlin is still Sunday in UTC
+	monday := time.Date(2024, 3, 4, 0, 30, 0, 0, berlin)
+	wednesday := time.Date(2024, 3, 6, 9, 0, 0, 0, berlin)
+	sunday := time.Date(2024, 3, 3, 9, 0, 0, 0, berlin)
+
+	tests := []struct {
+		name     string
+		input    string
+		now      time.Time
+		expected time.Time
+	}{
+		{name: "monday to prior friday", input: "-1bd", now: monday, expected: time.Date(2024, 3, 1, 0, 30, 0, 0, berlin)},
+		{name: "wednesday back one week", input: "-5bd", now: wednesday, expected: time.Date(2024, 2, 28, 9, 0, 0, 0, berlin)},
+		{name: "within the week", input: "-2bd", now: wednesday, expected: time.Date(2024, 3, 4, 9, 0, 0, 0, berlin)},
+		{name: "from a weekend", input: "-1bd", now: sunday, expected: time.Date(2024, 3, 1, 9, 0, 0, 0, berlin)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRelative(tt.input, func() time.Time { return tt.now })
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}